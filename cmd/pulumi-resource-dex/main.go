@@ -32,14 +32,44 @@ func main() {
 		WithRepository("github.com/kotaicode/pulumi-dex").
 		WithResources(
 			infer.Resource(&resources.Client{}),
+			infer.Resource(&resources.Password{}),
+			infer.Resource(&resources.LocalUser{}),
 			infer.Resource(&resources.Connector{}),
+			infer.Resource(&resources.ConnectorJSON{}),
 			infer.Resource(&resources.AzureOidcConnector{}),
 			infer.Resource(&resources.AzureMicrosoftConnector{}),
 			infer.Resource(&resources.CognitoOidcConnector{}),
+			infer.Resource(&resources.AwsSsoOidcConnector{}),
+			infer.Resource(&resources.SalesforceOidcConnector{}),
 			infer.Resource(&resources.GitLabConnector{}),
 			infer.Resource(&resources.GitHubConnector{}),
 			infer.Resource(&resources.GoogleConnector{}),
+			infer.Resource(&resources.KeycloakOidcConnector{}),
+			infer.Resource(&resources.LdapConnector{}),
 			infer.Resource(&resources.LocalConnector{}),
+			infer.Resource(&resources.PingOidcConnector{}),
+			infer.Resource(&resources.OAuthConnector{}),
+			infer.Resource(&resources.OktaOidcConnector{}),
+			infer.Resource(&resources.Auth0OidcConnector{}),
+			infer.Resource(&resources.AuthSetup{}),
+			infer.Resource(&resources.ConnectorGroup{}),
+			infer.Resource(&resources.ConnectorSet{}),
+		).
+		WithFunctions(
+			infer.Function(&resources.PreviewConnectorConfig{}),
+			infer.Function(&resources.RotateClientSecret{}),
+			infer.Function(&resources.ReorderConnectors{}),
+			infer.Function(&resources.GetSupportedConnectorTypes{}),
+			infer.Function(&resources.DiffConnectorConfig{}),
+			infer.Function(&resources.ReconcileConnectors{}),
+			infer.Function(&resources.DetectConnectorDrift{}),
+			infer.Function(&resources.GetDexVersion{}),
+			infer.Function(&resources.WaitForDex{}),
+			infer.Function(&resources.GetConnector{}),
+			infer.Function(&resources.GetConnectors{}),
+			infer.Function(&resources.GetClient{}),
+			infer.Function(&resources.PruneConnectors{}),
+			infer.Function(&resources.PruneClients{}),
 		).
 		WithConfig(infer.Config(&provider.DexConfig{})).
 		Build()
@@ -48,4 +78,8 @@ func main() {
 	}
 
 	prov.Run(context.Background(), providerName, provider.Version)
+
+	if err := provider.CloseConnections(); err != nil {
+		log.Printf("failed to close Dex connections: %v", err)
+	}
 }
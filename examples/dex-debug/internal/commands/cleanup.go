@@ -9,6 +9,15 @@ import (
 // CleanupCmd cleans up test clients and connectors (excluding static ones).
 type CleanupCmd struct {
 	BaseCmd
+	ClientsOnly    bool `help:"Only clean up clients" xor:"only"`
+	ConnectorsOnly bool `help:"Only clean up connectors" xor:"only"`
+}
+
+// cleanupSummary counts the outcome of each object considered for deletion.
+type cleanupSummary struct {
+	Deleted int
+	Skipped int
+	Failed  int
 }
 
 // Run executes the cleanup command.
@@ -17,55 +26,70 @@ func (c *CleanupCmd) Run() error {
 	client, gctx, cleanup := connectDex(host)
 	defer cleanup()
 
-	// List and delete leftover test clients (excluding static ones)
-	fmt.Println("=== Cleaning up leftover test clients ===")
-	clientsResp, err := client.ListClients(gctx, &api.ListClientReq{})
-	if err != nil {
-		return fmt.Errorf("failed to list clients: %w", err)
-	}
+	var clients, connectors cleanupSummary
 
-	for _, cl := range clientsResp.Clients {
-		// Skip static clients from config
-		if cl.Id == "test-client" {
-			continue
+	if !c.ConnectorsOnly {
+		// List and delete leftover test clients (excluding static ones)
+		fmt.Println("=== Cleaning up leftover test clients ===")
+		clientsResp, err := client.ListClients(gctx, &api.ListClientReq{})
+		if err != nil {
+			return fmt.Errorf("failed to list clients: %w", err)
 		}
-		// Delete test clients
-		if cl.Id == "my-web-app" || len(cl.Id) > 20 { // Timestamp-based IDs are longer
+
+		for _, cl := range clientsResp.Clients {
+			// Skip static clients from config; only delete test clients
+			if cl.Id == "test-client" || !(cl.Id == "my-web-app" || len(cl.Id) > 20) { // Timestamp-based IDs are longer
+				clients.Skipped++
+				continue
+			}
 			fmt.Printf("Deleting client: %s\n", cl.Id)
 			_, err := client.DeleteClient(gctx, &api.DeleteClientReq{Id: cl.Id})
 			if err != nil {
 				fmt.Printf("  Error deleting %s: %v\n", cl.Id, err)
+				clients.Failed++
 			} else {
 				fmt.Printf("  ✓ Deleted %s\n", cl.Id)
+				clients.Deleted++
 			}
 		}
 	}
 
-	// List and delete leftover test connectors (excluding static ones)
-	fmt.Println("\n=== Cleaning up leftover test connectors ===")
-	connectorsResp, err := client.ListConnectors(gctx, &api.ListConnectorReq{})
-	if err != nil {
-		return fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	for _, con := range connectorsResp.Connectors {
-		// Skip static connectors from config
-		if con.Id == "local" {
-			continue
+	if !c.ClientsOnly {
+		// List and delete leftover test connectors (excluding static ones)
+		fmt.Println("\n=== Cleaning up leftover test connectors ===")
+		connectorsResp, err := client.ListConnectors(gctx, &api.ListConnectorReq{})
+		if err != nil {
+			return fmt.Errorf("failed to list connectors: %w", err)
 		}
-		// Delete test connectors
-		if con.Id == "generic-oidc" || len(con.Id) > 20 { // Timestamp-based IDs are longer
+
+		for _, con := range connectorsResp.Connectors {
+			// Skip static connectors from config; only delete test connectors
+			if con.Id == "local" || !(con.Id == "generic-oidc" || len(con.Id) > 20) { // Timestamp-based IDs are longer
+				connectors.Skipped++
+				continue
+			}
 			fmt.Printf("Deleting connector: %s\n", con.Id)
 			_, err := client.DeleteConnector(gctx, &api.DeleteConnectorReq{Id: con.Id})
 			if err != nil {
 				fmt.Printf("  Error deleting %s: %v\n", con.Id, err)
+				connectors.Failed++
 			} else {
 				fmt.Printf("  ✓ Deleted %s\n", con.Id)
+				connectors.Deleted++
 			}
 		}
 	}
 
-	fmt.Println("\n=== Cleanup complete ===")
+	fmt.Println("\n=== Cleanup summary ===")
+	if !c.ConnectorsOnly {
+		fmt.Printf("Clients:    deleted=%d skipped=%d failed=%d\n", clients.Deleted, clients.Skipped, clients.Failed)
+	}
+	if !c.ClientsOnly {
+		fmt.Printf("Connectors: deleted=%d skipped=%d failed=%d\n", connectors.Deleted, connectors.Skipped, connectors.Failed)
+	}
+
+	if failed := clients.Failed + connectors.Failed; failed > 0 {
+		return fmt.Errorf("cleanup failed for %d object(s)", failed)
+	}
 	return nil
 }
-
@@ -4,109 +4,224 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	api "github.com/dexidp/dex/api/v2"
+	p "github.com/pulumi/pulumi-go-provider"
 	"github.com/pulumi/pulumi-go-provider/infer"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 // DexConfig describes provider-level configuration (connection to Dex gRPC).
 // This struct doubles as the configured client object passed to resources.
-// Note: Environment variables can be set but are not automatically read by the provider.
-// Users should set them in their Pulumi program or use Pulumi config.
+// The host and TLS file paths fall back to the DEX_GRPC_* environment
+// variables when not set in config; see Configure.
 type DexConfig struct {
-	Host            string  `pulumi:"host"`
-	CACertPEM       *string `pulumi:"caCert,optional" provider:"secret"`
-	ClientCertPEM   *string `pulumi:"clientCert,optional" provider:"secret"`
-	ClientKeyPEM    *string `pulumi:"clientKey,optional" provider:"secret"`
-	InsecureSkipTLS *bool   `pulumi:"insecureSkipVerify,optional"`
-	TimeoutSeconds  *int    `pulumi:"timeoutSeconds,optional"`
+	Host                        string   `pulumi:"host,optional"`
+	CACertPEM                   *string  `pulumi:"caCert,optional" provider:"secret"`
+	ClientCertPEM               *string  `pulumi:"clientCert,optional" provider:"secret"`
+	ClientKeyPEM                *string  `pulumi:"clientKey,optional" provider:"secret"`
+	CACertPath                  *string  `pulumi:"caCertPath,optional"`
+	ClientCertPath              *string  `pulumi:"clientCertPath,optional"`
+	ClientKeyPath               *string  `pulumi:"clientKeyPath,optional"`
+	TlsDir                      *string  `pulumi:"tlsDir,optional"`
+	InsecureSkipTLS             *bool    `pulumi:"insecureSkipVerify,optional"`
+	ServerNameOverride          *string  `pulumi:"serverNameOverride,optional"`
+	ProxyUrl                    *string  `pulumi:"proxyUrl,optional" provider:"secret"`
+	TimeoutSeconds              *int     `pulumi:"timeoutSeconds,optional"`
+	MaxSendMsgSizeMB            *int     `pulumi:"maxSendMsgSizeMB,optional"`
+	MaxRetries                  *int     `pulumi:"maxRetries,optional"`
+	RetryBackoffMs              *int     `pulumi:"retryBackoffMs,optional"`
+	KeepaliveTimeSeconds        *int     `pulumi:"keepaliveTimeSeconds,optional"`
+	KeepaliveTimeoutSeconds     *int     `pulumi:"keepaliveTimeoutSeconds,optional"`
+	NormalizeRawConfigKeys      *bool    `pulumi:"normalizeRawConfigKeys,optional"`
+	IgnoreConfigKeys            []string `pulumi:"ignoreConfigKeys,optional"`
+	StrictValidation            *bool    `pulumi:"strictValidation,optional"`
+	PreviewValidateConnectivity *bool    `pulumi:"previewValidateConnectivity,optional"`
+	WaitForReadySeconds         *int     `pulumi:"waitForReadySeconds,optional"`
+	DexPublicUrl                *string  `pulumi:"dexPublicUrl,optional"`
+	DeleteVerify                *bool    `pulumi:"deleteVerify,optional"`
+	DeleteVerifyDelayMs         *int     `pulumi:"deleteVerifyDelayMs,optional"`
+	ValidateTrustedPeers        *bool    `pulumi:"validateTrustedPeers,optional"`
+	DefaultOidcScopes           []string `pulumi:"defaultOidcScopes,optional"`
+	DebugLogging                *bool    `pulumi:"debugLogging,optional"`
+	WriteOnlySecrets            *bool    `pulumi:"writeOnlySecrets,optional"`
 
 	// internal fields are not exposed in schema and are used at runtime only.
-	Client api.DexClient
+	Client       api.DexClient
+	connectors   *connectorCache
+	previewCheck *previewCheck
 }
 
 // Annotate config fields with descriptions & defaults for the schema.
 func (c *DexConfig) Annotate(a infer.Annotator) {
-	a.Describe(&c.Host, "Dex gRPC host:port, e.g. dex.internal.example.com:5557.")
+	a.Describe(&c.Host, "Dex gRPC host:port, e.g. dex.internal.example.com:5557, or a Unix socket as unix:///var/run/dex/grpc.sock. Falls back to the DEX_GRPC_HOST environment variable.")
 	a.Describe(&c.CACertPEM, "PEM-encoded CA certificate for validating Dex's TLS certificate.")
 	a.Describe(&c.ClientCertPEM, "PEM-encoded client certificate for mTLS to Dex.")
 	a.Describe(&c.ClientKeyPEM, "PEM-encoded private key for the client certificate.")
+	a.Describe(&c.CACertPath, "Path to a PEM-encoded CA certificate file; alternative to caCert. Falls back to the DEX_GRPC_CA_CERT environment variable.")
+	a.Describe(&c.ClientCertPath, "Path to a PEM-encoded client certificate file for mTLS; alternative to clientCert. Falls back to the DEX_GRPC_CLIENT_CERT environment variable.")
+	a.Describe(&c.ClientKeyPath, "Path to a PEM-encoded private key file for the client certificate; alternative to clientKey. Falls back to the DEX_GRPC_CLIENT_KEY environment variable.")
+	a.Describe(&c.TlsDir, "Directory holding ca.crt, client.crt, and client.key, e.g. a Kubernetes secret mounted at /etc/dex/grpc. Shorthand for setting caCertPath, clientCertPath, and clientKeyPath to those files; cannot be combined with the individual TLS settings.")
 	a.Describe(&c.InsecureSkipTLS, "If true, disables TLS verification (development only).")
-	a.Describe(&c.TimeoutSeconds, "Per-RPC timeout in seconds when talking to Dex.")
+	a.Describe(&c.ServerNameOverride, "Server name to verify Dex's TLS certificate against, when it differs from the host being dialed (e.g. behind a proxy). Setting it enables TLS.")
+	a.Describe(&c.ProxyUrl, "SOCKS5 proxy to reach Dex through, e.g. socks5://bastion.example.com:1080, with optional user:password credentials. Use the socks5h scheme to have the proxy resolve Dex's host name. Works with the TLS and mTLS settings.")
+	a.Describe(&c.TimeoutSeconds, "Per-RPC timeout in seconds when talking to Dex. Defaults to 5. Resources can override it with their own timeoutSeconds.")
+	a.Describe(&c.MaxSendMsgSizeMB, "Maximum size in MiB of a single request sent to Dex. Raise it (together with Dex's own gRPC receive limit) for very large connector configs.")
+	a.Describe(&c.MaxRetries, "Number of times an RPC failing with a transient error (Unavailable, DeadlineExceeded) is retried, e.g. during a rolling restart of Dex. Defaults to 3; 0 disables retries.")
+	a.Describe(&c.RetryBackoffMs, "Wait in milliseconds before the first retry; it doubles after each attempt, up to 5 seconds. Defaults to 250.")
+	a.Describe(&c.KeepaliveTimeSeconds, "Interval in seconds after which an idle connection to Dex is pinged to keep it open through load balancers and detect dead peers. Defaults to 30; gRPC enforces a minimum of 10.")
+	a.Describe(&c.KeepaliveTimeoutSeconds, "Seconds to wait for a keepalive ping to be acknowledged before the connection is considered broken and re-established. Defaults to 10.")
+	a.Describe(&c.NormalizeRawConfigKeys, "If true, camelCase keys in a connector's rawConfig that Dex spells differently (e.g. clientId, redirectUri) are rewritten to Dex casing. If false (the default), they are rejected during preview.")
+	a.Describe(&c.IgnoreConfigKeys, "Top-level connector config keys managed outside Pulumi. Changes to them in Dex do not cause a diff, and updates keep the value currently stored in Dex.")
+	a.Describe(&c.StrictValidation, "If true, advisory validation warnings (e.g. an unrecognized connector type) fail the check instead of only being logged.")
+	a.Describe(&c.PreviewValidateConnectivity, "If true, previews of creates and updates call Dex's GetVersion once to confirm the API is reachable and accepts the provider's credentials. If false (the default), previews do not call Dex.")
+	a.Describe(&c.WaitForReadySeconds, "If set, Configure waits up to this many seconds for Dex to come up, connecting and then polling GetVersion until it succeeds, before any resource operation. Useful when Dex is started right before Pulumi, e.g. in CI.")
+	a.Describe(&c.DexPublicUrl, "Dex's public issuer URL, e.g. https://dex.example.com. Connector resources derive their callbackUrl output from it.")
+	a.Describe(&c.DeleteVerify, "If true (the default), deletes are confirmed by listing until the object is gone, polling a few times with backoff.")
+	a.Describe(&c.DeleteVerifyDelayMs, "Milliseconds to wait before the first delete verification check; the wait doubles after each check. Defaults to 200.")
+	a.Describe(&c.ValidateTrustedPeers, "If true, checks of dex.Client list Dex's clients and fail for trustedPeers that do not exist. Leave unset when peers are created in the same update, since they do not exist yet during preview.")
+	a.Describe(&c.DefaultOidcScopes, "Scopes used by the Azure OIDC, Cognito, AWS SSO, Salesforce, Keycloak, Okta, Auth0, and Ping connectors when they do not set scopes, instead of each resource's built-in default.")
+	a.Describe(&c.DebugLogging, "If true, every RPC to Dex is logged at debug level with the ID it targets, its status code, and its latency; request bodies, and so secrets, are never logged. Falls back to the PULUMI_DEX_DEBUG environment variable.")
+	a.Describe(&c.WriteOnlySecrets, "If true, refresh does not read secret values (client secrets, connector clientSecret, bindPW, serviceAccountJSON) back from Dex; the last-applied value is kept in state. Secrets changed in Dex outside Pulumi are then not detected as drift. Imports still read secrets from Dex.")
 }
 
 // Configure is called once per provider instance to establish a Dex gRPC client.
 // It satisfies infer.CustomConfigure via pointer receiver.
 func (c *DexConfig) Configure(ctx context.Context) error {
+	if err := c.resolveTLSDir(); err != nil {
+		return err
+	}
+	c.resolveFromEnv()
 	if c.Host == "" {
-		return fmt.Errorf("host is required")
+		return fmt.Errorf("host is required (set it in provider config or DEX_GRPC_HOST)")
 	}
 
 	// TODO: Optionally make Configure preview-safe by checking runInfo.Preview
 	// For now, we'll let Configure connect to Dex even in preview mode.
 	// The Create/Update methods will short-circuit based on req.DryRun before making API calls.
 
-	dialCtx, cancel := context.WithTimeout(ctx, time.Duration(PtrOr(c.TimeoutSeconds, 5))*time.Second)
+	// With waitForReadySeconds, a Dex that is still starting may take longer
+	// than one RPC timeout to accept the connection.
+	start := time.Now()
+	waitForReady := time.Duration(PtrOr(c.WaitForReadySeconds, 0)) * time.Second
+	dialCtx, cancel := context.WithTimeout(ctx, max(ResolveTimeout(*c, nil), waitForReady))
 	defer cancel()
 
 	var (
 		conn *grpc.ClientConn
 		err  error
+		opts []grpc.DialOption
 	)
 
+	if c.MaxSendMsgSizeMB != nil {
+		if *c.MaxSendMsgSizeMB <= 0 {
+			return fmt.Errorf("maxSendMsgSizeMB must be positive")
+		}
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(*c.MaxSendMsgSizeMB<<20)))
+	}
+
+	if c.DeleteVerifyDelayMs != nil && *c.DeleteVerifyDelayMs < 0 {
+		return fmt.Errorf("deleteVerifyDelayMs must not be negative")
+	}
+
+	keepaliveTime := PtrOr(c.KeepaliveTimeSeconds, 30)
+	keepaliveTimeout := PtrOr(c.KeepaliveTimeoutSeconds, 10)
+	if keepaliveTime <= 0 || keepaliveTimeout <= 0 {
+		return fmt.Errorf("keepaliveTimeSeconds and keepaliveTimeoutSeconds must be positive")
+	}
+	opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+		Time:                time.Duration(keepaliveTime) * time.Second,
+		Timeout:             time.Duration(keepaliveTimeout) * time.Second,
+		PermitWithoutStream: true,
+	}))
+
+	target := c.Host
+	// gRPC resolves unix:// targets itself; the socket is local, so TLS and
+	// proxies do not apply.
+	unixSocket := strings.HasPrefix(target, "unix:")
+	if unixSocket && c.ProxyUrl != nil && *c.ProxyUrl != "" {
+		return fmt.Errorf("proxyUrl cannot be used with a unix socket host (%s)", c.Host)
+	}
+	if c.ProxyUrl != nil && *c.ProxyUrl != "" {
+		proxyOpt, err := proxyDialOption(*c.ProxyUrl)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, proxyOpt)
+		// Hand the host name to the proxy dialer unresolved, so socks5h can
+		// resolve it on the far side of the proxy.
+		if !strings.Contains(target, "://") {
+			target = "passthrough:///" + target
+		}
+	}
+
+	// The cache interceptor comes first so it sees the outcome of all retries.
+	c.connectors = &connectorCache{}
+	c.previewCheck = &previewCheck{}
+	opts = append(opts, grpc.WithChainUnaryInterceptor(connectorCacheInterceptor(c.connectors)))
+
+	maxRetries := PtrOr(c.MaxRetries, defaultMaxRetries)
+	retryBackoffMs := PtrOr(c.RetryBackoffMs, defaultRetryBackoffMs)
+	if maxRetries < 0 {
+		return fmt.Errorf("maxRetries must not be negative")
+	}
+	if retryBackoffMs < 0 {
+		return fmt.Errorf("retryBackoffMs must not be negative")
+	}
+	if maxRetries > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(retryInterceptor(maxRetries, time.Duration(retryBackoffMs)*time.Millisecond)))
+	}
+	// Innermost, so that each attempt of a retried call is logged.
+	if c.debugLoggingEnabled() {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(debugLogInterceptor()))
+	}
+
+	caCert, err := resolvePEM("caCert", c.CACertPEM, c.CACertPath)
+	if err != nil {
+		return err
+	}
+	clientCert, err := resolvePEM("clientCert", c.ClientCertPEM, c.ClientCertPath)
+	if err != nil {
+		return err
+	}
+	clientKey, err := resolvePEM("clientKey", c.ClientKeyPEM, c.ClientKeyPath)
+	if err != nil {
+		return err
+	}
+
 	// Prefer TLS/mTLS when credentials are provided; otherwise fall back to insecure (plaintext)
 	// to match Dex's examples and make local development easy. See:
 	// https://dexidp.io/docs/configuration/api/
-	hasTLSMaterial := (c.CACertPEM != nil && *c.CACertPEM != "") ||
-		(c.ClientCertPEM != nil && *c.ClientCertPEM != "") ||
-		(c.ClientKeyPEM != nil && *c.ClientKeyPEM != "") ||
+	hasTLSMaterial := caCert != "" || clientCert != "" || clientKey != "" ||
+		(c.ServerNameOverride != nil && *c.ServerNameOverride != "") ||
 		PtrOr(c.InsecureSkipTLS, false)
 
+	if hasTLSMaterial && unixSocket {
+		p.GetLogger(ctx).Warningf("TLS settings are ignored for the unix socket host %s", c.Host)
+		hasTLSMaterial = false
+	}
+
 	if hasTLSMaterial {
-		tlsCfg := &tls.Config{}
-
-		// Root CA for validating Dex's server certificate.
-		if c.CACertPEM != nil && *c.CACertPEM != "" {
-			rootCAs := x509.NewCertPool()
-			if ok := rootCAs.AppendCertsFromPEM([]byte(*c.CACertPEM)); !ok {
-				return fmt.Errorf("failed to parse CA certificate")
-			}
-			tlsCfg.RootCAs = rootCAs
-		}
-
-		// Optional client certificate for mTLS.
-		if (c.ClientCertPEM != nil && *c.ClientCertPEM != "") || (c.ClientKeyPEM != nil && *c.ClientKeyPEM != "") {
-			if c.ClientCertPEM == nil || c.ClientKeyPEM == nil || *c.ClientCertPEM == "" || *c.ClientKeyPEM == "" {
-				return fmt.Errorf("both clientCert and clientKey must be provided (and non-empty) for mTLS")
-			}
-			cert, err := tls.X509KeyPair([]byte(*c.ClientCertPEM), []byte(*c.ClientKeyPEM))
-			if err != nil {
-				return fmt.Errorf("failed to load client certificate/key: %w", err)
-			}
-			tlsCfg.Certificates = []tls.Certificate{cert}
-		}
-
-		// Optionally skip server certificate verification (development only).
-		if PtrOr(c.InsecureSkipTLS, false) {
-			tlsCfg.InsecureSkipVerify = true
-		}
-
-		conn, err = grpc.NewClient(
-			c.Host,
-			grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)),
-		)
+		tlsCfg, err := c.tlsConfig(caCert, clientCert, clientKey)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
 	} else {
-		conn, err = grpc.NewClient(
-			c.Host,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-		)
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
+	conn, err = grpc.NewClient(target, opts...)
 
 	if err != nil {
 		return fmt.Errorf("failed to connect to Dex at %s: %w", c.Host, err)
@@ -123,15 +238,225 @@ func (c *DexConfig) Configure(ctx context.Context) error {
 			break
 		}
 		if !conn.WaitForStateChange(dialCtx, state) {
+			_ = conn.Close()
 			return fmt.Errorf("timed out while connecting to Dex at %s", c.Host)
 		}
 	}
 
+	// The connection is shared by every operation of this provider instance
+	// and closed by CloseConnections on shutdown.
+	trackConnection(conn)
 	c.Client = api.NewDexClient(conn)
 
+	if waitForReady > 0 {
+		if _, _, err := c.WaitForReady(ctx, waitForReady-time.Since(start), defaultReadyInterval); err != nil {
+			return fmt.Errorf("waitForReadySeconds: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// tlsConfig builds the TLS configuration for the connection to Dex from the
+// resolved PEM material and the TLS settings.
+func (c *DexConfig) tlsConfig(caCert, clientCert, clientKey string) (*tls.Config, error) {
+	tlsCfg := &tls.Config{}
+
+	// Root CA for validating Dex's server certificate.
+	if caCert != "" {
+		rootCAs := x509.NewCertPool()
+		if ok := rootCAs.AppendCertsFromPEM([]byte(caCert)); !ok {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+		tlsCfg.RootCAs = rootCAs
+	} else if !PtrOr(c.InsecureSkipTLS, false) {
+		// Without an explicit CA, trust the system roots so publicly-trusted
+		// Dex endpoints work out of the box.
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load system certificate pool: %w", err)
+		}
+		tlsCfg.RootCAs = rootCAs
+	}
+
+	if c.ServerNameOverride != nil && *c.ServerNameOverride != "" {
+		tlsCfg.ServerName = *c.ServerNameOverride
+	}
+
+	// Optional client certificate for mTLS.
+	if clientCert != "" || clientKey != "" {
+		if clientCert == "" || clientKey == "" {
+			return nil, fmt.Errorf("both a client certificate (clientCert or clientCertPath) and a client key (clientKey or clientKeyPath) must be provided for mTLS")
+		}
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate/key: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	// Optionally skip server certificate verification (development only).
+	if PtrOr(c.InsecureSkipTLS, false) {
+		tlsCfg.InsecureSkipVerify = true
+	}
+
+	return tlsCfg, nil
+}
+
+// previewCheck memoizes the preview connectivity check of one provider
+// instance, so a preview with many resources calls Dex only once.
+type previewCheck struct {
+	once sync.Once
+	err  error
+}
+
+// CheckPreviewConnectivity confirms during preview that the Dex API can be
+// called, when previewValidateConnectivity is set. The check runs once per
+// provider instance and its result is reused; without the flag it is a no-op.
+func (c *DexConfig) CheckPreviewConnectivity(ctx context.Context) error {
+	if !PtrOr(c.PreviewValidateConnectivity, false) {
+		return nil
+	}
+	if c.Client == nil {
+		return ErrClientNotConfigured
+	}
+	check := func() error {
+		callCtx, cancel := context.WithTimeout(ctx, ResolveTimeout(*c, nil))
+		defer cancel()
+		if _, err := c.Client.GetVersion(callCtx, &api.VersionReq{}); err != nil {
+			return fmt.Errorf("previewValidateConnectivity: cannot call the Dex API at %s: %w", c.Host, err)
+		}
+		return nil
+	}
+	if c.previewCheck == nil {
+		return check()
+	}
+	c.previewCheck.once.Do(func() {
+		c.previewCheck.err = check()
+	})
+	return c.previewCheck.err
+}
+
+var (
+	connectionsMu sync.Mutex
+	connections   []*grpc.ClientConn
+)
+
+func trackConnection(conn *grpc.ClientConn) {
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+	connections = append(connections, conn)
+}
+
+// CloseConnections closes every Dex connection opened by Configure. Call it
+// once the provider has stopped serving requests.
+func CloseConnections() error {
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+	var errs []error
+	for _, conn := range connections {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	connections = nil
+	return errors.Join(errs...)
+}
+
+// resolveFromEnv fills unset connection settings from the environment, so
+// explicit config always takes precedence. A path from the environment is
+// ignored when the matching inline PEM is configured.
+func (c *DexConfig) resolveFromEnv() {
+	if c.Host == "" {
+		c.Host = os.Getenv("DEX_GRPC_HOST")
+	}
+	envPath := func(inline, path *string, key string) *string {
+		if (inline != nil && *inline != "") || (path != nil && *path != "") {
+			return path
+		}
+		if v := os.Getenv(key); v != "" {
+			return &v
+		}
+		return path
+	}
+	c.CACertPath = envPath(c.CACertPEM, c.CACertPath, "DEX_GRPC_CA_CERT")
+	c.ClientCertPath = envPath(c.ClientCertPEM, c.ClientCertPath, "DEX_GRPC_CLIENT_CERT")
+	c.ClientKeyPath = envPath(c.ClientKeyPEM, c.ClientKeyPath, "DEX_GRPC_CLIENT_KEY")
+}
+
+// tlsDirFiles are the files tlsDir loads, in the layout of a Kubernetes TLS
+// secret for Dex's gRPC API mounted as a directory.
+var tlsDirFiles = [...]string{"ca.crt", "client.crt", "client.key"}
+
+// resolveTLSDir points caCertPath, clientCertPath, and clientKeyPath at the
+// files in tlsDir, if set. Every file must exist and be readable; the error
+// lists each one that is not.
+func (c *DexConfig) resolveTLSDir() error {
+	if c.TlsDir == nil || *c.TlsDir == "" {
+		return nil
+	}
+	for _, set := range []*string{c.CACertPEM, c.ClientCertPEM, c.ClientKeyPEM, c.CACertPath, c.ClientCertPath, c.ClientKeyPath} {
+		if set != nil && *set != "" {
+			return fmt.Errorf("tlsDir cannot be combined with caCert, clientCert, clientKey, or their Path settings")
+		}
+	}
+
+	var (
+		paths [len(tlsDirFiles)]string
+		errs  []error
+	)
+	for i, name := range tlsDirFiles {
+		paths[i] = filepath.Join(*c.TlsDir, name)
+		f, err := os.Open(paths[i])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		f.Close()
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("tlsDir %s is missing TLS files:\n%w", *c.TlsDir, errors.Join(errs...))
+	}
+
+	c.CACertPath, c.ClientCertPath, c.ClientKeyPath = &paths[0], &paths[1], &paths[2]
+	return nil
+}
+
+// resolvePEM returns the PEM content for a TLS setting given either inline or
+// as a file path. Setting both is an error.
+func resolvePEM(name string, inline, path *string) (string, error) {
+	hasInline := inline != nil && *inline != ""
+	hasPath := path != nil && *path != ""
+	switch {
+	case hasInline && hasPath:
+		return "", fmt.Errorf("%s and %sPath are mutually exclusive; set only one", name, name)
+	case hasInline:
+		return *inline, nil
+	case hasPath:
+		data, err := os.ReadFile(*path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %sPath: %w", name, err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+// defaultTimeoutSeconds is the per-RPC timeout used when neither the provider
+// nor the resource sets timeoutSeconds.
+const defaultTimeoutSeconds = 5
+
+// ResolveTimeout returns the timeout for a Dex RPC: a resource's own
+// timeoutSeconds when set and positive, otherwise the provider's
+// timeoutSeconds, otherwise defaultTimeoutSeconds. Pass nil as override for
+// calls not made on behalf of a single resource.
+func ResolveTimeout(cfg DexConfig, override *int) time.Duration {
+	if override != nil && *override > 0 {
+		return time.Duration(*override) * time.Second
+	}
+	return time.Duration(PtrOr(cfg.TimeoutSeconds, defaultTimeoutSeconds)) * time.Second
+}
+
 // PtrOr returns the value pointed to by p, or def if p is nil.
 func PtrOr[T any](p *T, def T) T {
 	if p == nil {
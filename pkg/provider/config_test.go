@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// handshake dials srv under the host name "localhost", which its certificate
+// (issued for example.com and the loopback IPs) does not cover.
+func handshake(t *testing.T, srv *httptest.Server, cfg *tls.Config) error {
+	t.Helper()
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := tls.Dial("tcp", net.JoinHostPort("localhost", port), cfg)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func TestTLSConfigServerNameOverride(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	c := &DexConfig{}
+	cfg, err := c.tlsConfig(caCert, "", "")
+	if err != nil {
+		t.Fatalf("tlsConfig() = %v", err)
+	}
+	if err := handshake(t, srv, cfg); err == nil {
+		t.Fatal("handshake without serverNameOverride succeeded, want a host name mismatch")
+	}
+
+	override := "example.com"
+	c.ServerNameOverride = &override
+	cfg, err = c.tlsConfig(caCert, "", "")
+	if err != nil {
+		t.Fatalf("tlsConfig() = %v", err)
+	}
+	if cfg.ServerName != override {
+		t.Errorf("ServerName = %q, want %q", cfg.ServerName, override)
+	}
+	if err := handshake(t, srv, cfg); err != nil {
+		t.Errorf("handshake with serverNameOverride = %v, want success", err)
+	}
+}
+
+func TestTLSConfigSystemCertPoolFallback(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	override := "example.com"
+	c := &DexConfig{ServerNameOverride: &override}
+	cfg, err := c.tlsConfig("", "", "")
+	if err != nil {
+		t.Fatalf("tlsConfig() = %v", err)
+	}
+	if cfg.RootCAs == nil {
+		t.Fatal("RootCAs = nil, want the system certificate pool")
+	}
+	// The test server's self-signed certificate is not in the system pool.
+	var verifyErr *tls.CertificateVerificationError
+	if err := handshake(t, srv, cfg); !errors.As(err, &verifyErr) {
+		t.Errorf("handshake = %v, want a certificate verification error", err)
+	}
+
+	insecure := true
+	c.InsecureSkipTLS = &insecure
+	cfg, err = c.tlsConfig("", "", "")
+	if err != nil {
+		t.Fatalf("tlsConfig() = %v", err)
+	}
+	if err := handshake(t, srv, cfg); err != nil {
+		t.Errorf("handshake with insecureSkipVerify = %v, want success", err)
+	}
+}
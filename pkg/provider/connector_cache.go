@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/dexidp/dex/api/v2"
+	"google.golang.org/grpc"
+)
+
+// connectorCacheTTL is how long a ListConnectors result is reused. A refresh
+// reads all connectors of a stack within a short burst, so this is enough to
+// collapse their lists into one RPC while staying well below the time a human
+// would notice stale data.
+const connectorCacheTTL = 500 * time.Millisecond
+
+// connectorCache memoizes the ListConnectors result of one provider instance.
+// The mutex is held while fetching, so concurrent reads wait for a single
+// in-flight list instead of each issuing their own.
+type connectorCache struct {
+	mu         sync.Mutex
+	fetched    time.Time
+	connectors []*api.Connector
+	byID       map[string]*api.Connector
+}
+
+func (c *connectorCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connectors = nil
+	c.byID = nil
+	c.fetched = time.Time{}
+}
+
+// load refetches the list unless the cached one is younger than
+// connectorCacheTTL. The caller must hold c.mu.
+func (c *connectorCache) load(ctx context.Context, client api.DexClient) error {
+	if c.connectors != nil && time.Since(c.fetched) < connectorCacheTTL {
+		return nil
+	}
+	resp, err := client.ListConnectors(ctx, &api.ListConnectorReq{})
+	if err != nil {
+		return err
+	}
+	c.connectors = resp.Connectors
+	c.byID = nil
+	c.fetched = time.Now()
+	return nil
+}
+
+// ListConnectors returns the connectors in Dex, reusing a list fetched within
+// the last connectorCacheTTL. The cache is dropped whenever a connector is
+// created, updated, or deleted through this provider instance.
+func (c *DexConfig) ListConnectors(ctx context.Context) ([]*api.Connector, error) {
+	if c.connectors == nil {
+		resp, err := c.Client.ListConnectors(ctx, &api.ListConnectorReq{})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Connectors, nil
+	}
+
+	cache := c.connectors
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err := cache.load(ctx, c.Client); err != nil {
+		return nil, err
+	}
+	return cache.connectors, nil
+}
+
+// FindConnector returns the connector with the given ID, or nil if Dex has
+// none. It shares ListConnectors' cache and indexes a cached list by ID the
+// first time it is searched, so the reads of a refresh each find their
+// connector without scanning the whole list.
+func (c *DexConfig) FindConnector(ctx context.Context, id string) (*api.Connector, error) {
+	if c.connectors == nil {
+		connectors, err := c.ListConnectors(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, con := range connectors {
+			if con.GetId() == id {
+				return con, nil
+			}
+		}
+		return nil, nil
+	}
+
+	cache := c.connectors
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if err := cache.load(ctx, c.Client); err != nil {
+		return nil, err
+	}
+	if cache.byID == nil {
+		cache.byID = make(map[string]*api.Connector, len(cache.connectors))
+		for _, con := range cache.connectors {
+			cache.byID[con.GetId()] = con
+		}
+	}
+	return cache.byID[id], nil
+}
+
+// connectorCacheInterceptor drops cache after every connector write RPC,
+// successful or not, so a later read never sees a list from before the write.
+func connectorCacheInterceptor(cache *connectorCache) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		switch {
+		case strings.HasSuffix(method, "/CreateConnector"),
+			strings.HasSuffix(method, "/UpdateConnector"),
+			strings.HasSuffix(method, "/DeleteConnector"):
+			cache.invalidate()
+		}
+		return err
+	}
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	api "github.com/dexidp/dex/api/v2"
+	"google.golang.org/grpc"
+)
+
+// listOnlyDex is a DexClient that only serves ListConnectors.
+type listOnlyDex struct {
+	api.DexClient
+	connectors []*api.Connector
+	lists      int
+}
+
+func (d *listOnlyDex) ListConnectors(ctx context.Context, in *api.ListConnectorReq, opts ...grpc.CallOption) (*api.ListConnectorResp, error) {
+	d.lists++
+	return &api.ListConnectorResp{Connectors: d.connectors}, nil
+}
+
+func newListOnlyDex(n int) *listOnlyDex {
+	d := &listOnlyDex{}
+	for i := 0; i < n; i++ {
+		d.connectors = append(d.connectors, &api.Connector{Id: fmt.Sprintf("connector-%d", i), Type: "oidc"})
+	}
+	return d
+}
+
+func TestFindConnectorUsesCache(t *testing.T) {
+	dex := newListOnlyDex(3)
+	cfg := &DexConfig{Client: dex, connectors: &connectorCache{}}
+
+	for _, id := range []string{"connector-0", "connector-2", "connector-1"} {
+		con, err := cfg.FindConnector(context.Background(), id)
+		if err != nil {
+			t.Fatalf("FindConnector(%q) = %v", id, err)
+		}
+		if con == nil || con.Id != id {
+			t.Errorf("FindConnector(%q) = %v", id, con)
+		}
+	}
+	if con, err := cfg.FindConnector(context.Background(), "missing"); err != nil || con != nil {
+		t.Errorf("FindConnector(missing) = %v, %v, want nil, nil", con, err)
+	}
+	if dex.lists != 1 {
+		t.Errorf("ListConnectors called %d times, want 1", dex.lists)
+	}
+
+	cfg.connectors.invalidate()
+	if _, err := cfg.FindConnector(context.Background(), "connector-0"); err != nil {
+		t.Fatal(err)
+	}
+	if dex.lists != 2 {
+		t.Errorf("ListConnectors called %d times after invalidate, want 2", dex.lists)
+	}
+}
+
+func BenchmarkFindByID(b *testing.B) {
+	const n = 5000
+	ctx := context.Background()
+
+	b.Run("cached index", func(b *testing.B) {
+		cfg := &DexConfig{Client: newListOnlyDex(n), connectors: &connectorCache{}}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if con, err := cfg.FindConnector(ctx, fmt.Sprintf("connector-%d", i%n)); err != nil || con == nil {
+				b.Fatalf("FindConnector() = %v, %v", con, err)
+			}
+		}
+	})
+
+	b.Run("uncached scan", func(b *testing.B) {
+		cfg := &DexConfig{Client: newListOnlyDex(n)}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if con, err := cfg.FindConnector(ctx, fmt.Sprintf("connector-%d", i%n)); err != nil || con == nil {
+				b.Fatalf("FindConnector() = %v, %v", con, err)
+			}
+		}
+	})
+}
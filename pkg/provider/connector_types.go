@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"sort"
+
+	api "github.com/dexidp/dex/api/v2"
+)
+
+// ConnectorTypeSourceStatic and ConnectorTypeSourceServer name where a list of
+// supported connector types came from.
+const (
+	ConnectorTypeSourceStatic = "static"
+	ConnectorTypeSourceServer = "server"
+)
+
+// StaticConnectorTypes is the provider's built-in registry of connector types,
+// matching the connectors compiled into upstream Dex. "local" is the builtin
+// password database connector.
+var StaticConnectorTypes = []string{
+	"atlassian-crowd",
+	"authproxy",
+	"bitbucket-cloud",
+	"gitea",
+	"github",
+	"gitlab",
+	"google",
+	"keystone",
+	"ldap",
+	"linkedin",
+	"local",
+	"microsoft",
+	"mockCallback",
+	"mockPassword",
+	"oauth",
+	"oidc",
+	"openshift",
+	"saml",
+}
+
+// SupportedConnectorTypes returns the connector types the Dex server accepts
+// and the source of the list. Dex does not expose its connector registry over
+// the gRPC API yet, so the static registry is always returned; once it does,
+// query client here and keep the static list as the fallback.
+func SupportedConnectorTypes(ctx context.Context, client api.DexClient) ([]string, string) {
+	types := append([]string(nil), StaticConnectorTypes...)
+	sort.Strings(types)
+	return types, ConnectorTypeSourceStatic
+}
+
+// IsSupportedConnectorType reports whether connectorType is in types.
+func IsSupportedConnectorType(types []string, connectorType string) bool {
+	for _, t := range types {
+		if t == connectorType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	api "github.com/dexidp/dex/api/v2"
+	p "github.com/pulumi/pulumi-go-provider"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// debugEnvVar enables debug logging when debugLogging is not set in config.
+const debugEnvVar = "PULUMI_DEX_DEBUG"
+
+// debugLoggingEnabled reports whether RPCs to Dex should be logged: the
+// debugLogging setting if configured, otherwise PULUMI_DEX_DEBUG.
+func (c *DexConfig) debugLoggingEnabled() bool {
+	if c.DebugLogging != nil {
+		return *c.DebugLogging
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv(debugEnvVar))
+	return enabled
+}
+
+// debugLogInterceptor logs every RPC sent to Dex with the ID of the object
+// it targets, its outcome, and its latency, through the provider host's
+// logger at debug level. Request and response bodies are never logged, so
+// secrets such as client secrets and connector configs cannot leak.
+func debugLogInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		elapsed := time.Since(start).Round(time.Millisecond)
+
+		rpc := path.Base(method)
+		if id := rpcTargetID(req); id != "" {
+			rpc += " " + strconv.Quote(id)
+		}
+		p.GetLogger(ctx).Debugf("dex rpc %s: %s in %s", rpc, status.Code(err), elapsed)
+		return err
+	}
+}
+
+// rpcTargetID returns the ID of the client, connector, or password a Dex
+// API request refers to, or "" for requests without one such as listings.
+func rpcTargetID(req any) string {
+	switch r := req.(type) {
+	case interface{ GetId() string }:
+		return r.GetId()
+	case interface{ GetEmail() string }:
+		return r.GetEmail()
+	case *api.CreateClientReq:
+		return r.GetClient().GetId()
+	case *api.CreateConnectorReq:
+		return r.GetConnector().GetId()
+	case *api.CreatePasswordReq:
+		return r.GetPassword().GetEmail()
+	}
+	return ""
+}
@@ -1,15 +1,68 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrClientNotConfigured is returned by operations that need Dex when the
+	// provider has no gRPC client, e.g. because Configure has not run.
+	ErrClientNotConfigured = errors.New("Dex client not configured")
+
+	// ErrAlreadyExists is wrapped by errors about an object that already exists
+	// in Dex and cannot be adopted, so callers can match it with errors.Is.
+	ErrAlreadyExists = errors.New("already exists")
 )
 
+// DexAPIError is a failed Dex API call with the operation, resource type, and
+// resource ID it was made for. Code is the gRPC status code of the failure;
+// Err is the original error, so status.Code and errors.Is see through it.
+type DexAPIError struct {
+	Operation    string
+	ResourceType string
+	ResourceID   string
+	Code         codes.Code
+	Err          error
+}
+
+func (e *DexAPIError) Error() string {
+	msg := fmt.Sprintf("dex %s %s %q: %v", e.Operation, e.ResourceType, e.ResourceID, e.Err)
+	switch e.Code {
+	case codes.ResourceExhausted:
+		// Usually an oversized request; gRPC's own message does not say what to change.
+		msg += " (the request likely exceeded a gRPC message size limit: raise the provider's maxSendMsgSizeMB and Dex's gRPC receive limit, or shrink the config, e.g. by referencing CA files by path instead of inlining them)"
+	case codes.PermissionDenied, codes.Unauthenticated:
+		// Almost always mTLS: Dex only checks who the caller is at the TLS layer.
+		msg += " (Dex rejected the provider's credentials: check that clientCert and clientKey (or their Path settings, or tlsDir) hold a client certificate signed by the CA in Dex's grpc.tlsClientCA, and that it has not expired)"
+	}
+	return msg
+}
+
+func (e *DexAPIError) Unwrap() error {
+	return e.Err
+}
+
+// Is makes errors.Is(err, ErrAlreadyExists) match a Dex AlreadyExists status.
+func (e *DexAPIError) Is(target error) bool {
+	return target == ErrAlreadyExists && e.Code == codes.AlreadyExists
+}
+
 // WrapError wraps a Dex API error with context to make it more user-friendly.
-// This provides better error messages that include the operation, resource type,
-// and resource ID for easier debugging.
+// The result is a *DexAPIError that includes the operation, resource type,
+// and resource ID for easier debugging, and keeps the gRPC status code.
 func WrapError(operation, resourceType, resourceID string, err error) error {
 	if err == nil {
 		return nil
 	}
-	return fmt.Errorf("dex %s %s %q: %w", operation, resourceType, resourceID, err)
+	return &DexAPIError{
+		Operation:    operation,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Code:         status.Code(err),
+		Err:          err,
+	}
 }
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrapErrorCredentialHint(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     codes.Code
+		wantHint bool
+	}{
+		{name: "permission denied", code: codes.PermissionDenied, wantHint: true},
+		{name: "unauthenticated", code: codes.Unauthenticated, wantHint: true},
+		{name: "not found", code: codes.NotFound},
+		{name: "unavailable", code: codes.Unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grpcErr := status.Error(tt.code, "rejected")
+			err := WrapError("create", "connector", "oidc", grpcErr)
+
+			msg := err.Error()
+			if !strings.Contains(msg, `dex create connector "oidc"`) || !strings.Contains(msg, "rejected") {
+				t.Errorf("Error() = %q, want the operation, resource, and cause", msg)
+			}
+			if got := strings.Contains(msg, "grpc.tlsClientCA"); got != tt.wantHint {
+				t.Errorf("Error() = %q, want credential hint %v", msg, tt.wantHint)
+			}
+
+			var apiErr *DexAPIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("errors.As(%v, *DexAPIError) = false", err)
+			}
+			if apiErr.Code != tt.code || apiErr.ResourceID != "oidc" {
+				t.Errorf("DexAPIError = %+v, want code %v for oidc", apiErr, tt.code)
+			}
+			if !errors.Is(err, grpcErr) {
+				t.Error("errors.Is(err, original) = false")
+			}
+			if errors.Is(err, ErrAlreadyExists) {
+				t.Error("errors.Is(err, ErrAlreadyExists) = true")
+			}
+			if got := status.Code(err); got != tt.code {
+				t.Errorf("status.Code() = %v, want %v", got, tt.code)
+			}
+		})
+	}
+}
+
+func TestWrapErrorAlreadyExists(t *testing.T) {
+	err := WrapError("create", "client", "app", status.Error(codes.AlreadyExists, "exists"))
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("errors.Is(%v, ErrAlreadyExists) = false", err)
+	}
+	if WrapError("create", "client", "app", nil) != nil {
+		t.Error("WrapError(nil) != nil")
+	}
+}
@@ -0,0 +1,41 @@
+package provider
+
+// ImmutableFields lists, per resource, the input properties that cannot be
+// changed in place. Changing one forces a replacement in the resource's Diff,
+// and Update refuses to apply a change to one. Resources are keyed by their
+// type name without the package prefix (e.g. "GitHubConnector"). A field of a
+// nested object is given by its path, e.g. "connector.connectorId".
+var ImmutableFields = map[string][]string{
+	"Client":                  {"clientId", "secret", "public"},
+	"Connector":               {"connectorId", "type"},
+	"ConnectorJSON":           {"connectorId"},
+	"AzureOidcConnector":      {"connectorId", "tenantId"},
+	"AzureMicrosoftConnector": {"connectorId", "tenant"},
+	"CognitoOidcConnector":    {"connectorId", "region", "userPoolId"},
+	"AwsSsoOidcConnector":     {"connectorId", "region", "startUrl"},
+	"SalesforceOidcConnector": {"connectorId"},
+	"GitHubConnector":         {"connectorId", "hostName"},
+	"GitLabConnector":         {"connectorId", "baseURL"},
+	"GoogleConnector":         {"connectorId"},
+	"KeycloakOidcConnector":   {"connectorId", "baseUrl", "realm"},
+	"LdapConnector":           {"connectorId", "host"},
+	"LocalConnector":          {"connectorId"},
+	"OAuthConnector":          {"connectorId"},
+	"Password":                {"email", "userId"},
+	"LocalUser":               {"email", "userId"},
+	"OktaOidcConnector":       {"connectorId", "oktaDomain"},
+	"Auth0OidcConnector":      {"connectorId", "domain"},
+	"PingOidcConnector":       {"connectorId", "environmentId", "region"},
+	"AuthSetup":               {"connector.connectorId"},
+	"ConnectorGroup":          {"primary.connectorId", "fallback.connectorId"},
+}
+
+// IsImmutableField reports whether property is listed in ImmutableFields for resource.
+func IsImmutableField(resource, property string) bool {
+	for _, f := range ImmutableFields[resource] {
+		if f == property {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	"golang.org/x/net/proxy"
+	"google.golang.org/grpc"
+)
+
+// proxyDialOption returns a dial option that opens the TCP connection to Dex
+// through the SOCKS5 proxy at rawURL. TLS is negotiated over the proxied
+// connection as usual. With the socks5h scheme the proxy resolves Dex's host
+// name, for hosts only resolvable behind a bastion.
+func proxyDialOption(rawURL string) (grpc.DialOption, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxyUrl: %w", err)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxyUrl scheme %q: only socks5:// and socks5h:// are supported; for HTTP CONNECT proxies set HTTPS_PROXY instead, which gRPC honors natively", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid proxyUrl %q: missing proxy host", u.Redacted())
+	}
+
+	dialer, err := proxy.FromURL(u, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxyUrl %q: %w", u.Redacted(), err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("proxyUrl %q: dialer does not support contexts", u.Redacted())
+	}
+
+	return grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return contextDialer.DialContext(ctx, "tcp", addr)
+	}), nil
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	api "github.com/dexidp/dex/api/v2"
+)
+
+// defaultReadyInterval is the wait between GetVersion polls in WaitForReady.
+const defaultReadyInterval = time.Second
+
+// WaitForReady calls GetVersion every interval until it succeeds or timeout
+// has elapsed, for a Dex that is still starting up. It makes at least one
+// call, and returns the version Dex reported and the time spent waiting.
+func (c *DexConfig) WaitForReady(ctx context.Context, timeout, interval time.Duration) (*api.VersionResp, time.Duration, error) {
+	if c.Client == nil {
+		return nil, 0, ErrClientNotConfigured
+	}
+	if interval <= 0 {
+		interval = defaultReadyInterval
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	for {
+		callCtx, cancel := context.WithTimeout(ctx, ResolveTimeout(*c, nil))
+		resp, err := c.Client.GetVersion(callCtx, &api.VersionReq{})
+		cancel()
+		if err == nil {
+			return resp, time.Since(start), nil
+		}
+		if ctx.Err() != nil || time.Now().Add(interval).After(deadline) {
+			return nil, time.Since(start), fmt.Errorf("Dex at %s was not ready after %s: %w", c.Host, time.Since(start).Round(time.Second), err)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, time.Since(start), fmt.Errorf("Dex at %s was not ready: %w", c.Host, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// Auth0OidcConnector - Uses generic OIDC connector (type: "oidc")
+// ============================================================================
+
+// Auth0OidcConnectorArgs defines inputs for Auth0OidcConnector.
+type Auth0OidcConnectorArgs struct {
+	ConnectorId    string         `pulumi:"connectorId"`
+	Name           string         `pulumi:"name"`
+	Domain         string         `pulumi:"domain"`
+	ClientId       string         `pulumi:"clientId"`
+	ClientSecret   string         `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri    string         `pulumi:"redirectUri"`
+	Scopes         []string       `pulumi:"scopes,optional"`
+	UserNameSource *string        `pulumi:"userNameSource,optional"` // "email" | "nickname" | "name" | "sub"
+	ExtraOidc      map[string]any `pulumi:"extraOidc,optional"`
+	TimeoutSeconds *int           `pulumi:"timeoutSeconds,optional"`
+}
+
+// Auth0OidcConnectorState defines outputs for Auth0OidcConnector.
+type Auth0OidcConnectorState struct {
+	Auth0OidcConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
+}
+
+// Auth0OidcConnector manages an Auth0 connector using Dex's generic OIDC connector.
+type Auth0OidcConnector struct{}
+
+// Annotate provides schema metadata.
+func (c *Auth0OidcConnector) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages an Auth0 connector in Dex using the generic OIDC connector (type: oidc). The issuer is derived from the Auth0 domain as 'https://<domain>/', with the trailing slash Auth0 uses.")
+}
+
+// Annotate provides schema metadata for Auth0OidcConnectorArgs.
+func (c *Auth0OidcConnectorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the Auth0 connector.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.Domain, "Auth0 tenant domain, e.g. 'acme.us.auth0.com' or a custom domain. A scheme or trailing slash is removed. Changing this forces a replacement.")
+	a.Describe(&c.ClientId, "Auth0 application client ID.")
+	a.Describe(&c.ClientSecret, "Auth0 application client secret.")
+	a.Describe(&c.RedirectUri, "Allowed callback URL registered in the Auth0 application. Must match Dex's callback URL.")
+	a.Describe(&c.Scopes, "OIDC scopes to request from Auth0. Defaults to ['openid', 'profile', 'email'] if not specified.")
+	a.Describe(&c.UserNameSource, "Source for the username claim. Valid values: 'email' (default), 'nickname', 'name', or 'sub'. Auth0 does not issue preferred_username by default.")
+	a.Describe(&c.ExtraOidc, "Additional OIDC configuration fields as key-value pairs for advanced scenarios.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for Auth0OidcConnectorState.
+func (c *Auth0OidcConnectorState) Annotate(a infer.Annotator) {
+	// Auth0OidcConnectorState embeds Auth0OidcConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// Check validates inputs and reduces domain to a bare host name, so that it
+// round-trips through the issuer Read derives it from.
+func (c *Auth0OidcConnector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[Auth0OidcConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[Auth0OidcConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[Auth0OidcConnectorArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	args.Domain = auth0DomainFromIssuer(strings.TrimSpace(args.Domain))
+	if args.Domain != "" && !bareDomainRegex.MatchString(args.Domain) {
+		failures = append(failures, p.CheckFailure{
+			Property: "domain",
+			Reason:   fmt.Sprintf("must be a domain such as 'acme.us.auth0.com' (no path), got %q", args.Domain),
+		})
+	}
+
+	// Validate userNameSource
+	if args.UserNameSource != nil {
+		valid := map[string]bool{"email": true, "nickname": true, "name": true, "sub": true}
+		if !valid[*args.UserNameSource] {
+			failures = append(failures, p.CheckFailure{
+				Property: "userNameSource",
+				Reason:   "must be one of: email, nickname, name, sub",
+			})
+		}
+	}
+
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+	failures = append(failures, checkOpenIDScope(ctx, cfg, "scopes", args.Scopes)...)
+
+	applyAuth0OidcDefaults(cfg, &args)
+
+	return infer.CheckResponse[Auth0OidcConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as
+// replacements; domain is baked into the issuer.
+func (c *Auth0OidcConnector) Diff(ctx context.Context, req infer.DiffRequest[Auth0OidcConnectorArgs, Auth0OidcConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("Auth0OidcConnector", req.State.Auth0OidcConnectorArgs, req.Inputs), nil
+}
+
+// Create creates a new Auth0 OIDC connector.
+func (c *Auth0OidcConnector) Create(ctx context.Context, req infer.CreateRequest[Auth0OidcConnectorArgs]) (infer.CreateResponse[Auth0OidcConnectorState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[Auth0OidcConnectorState]{}, err
+		}
+		state := Auth0OidcConnectorState{
+			Auth0OidcConnectorArgs: args,
+			CallbackUrl:            dexCallbackURL(ctx),
+		}
+		return infer.CreateResponse[Auth0OidcConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[Auth0OidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := json.Marshal(buildAuth0OidcConfig(args))
+	if err != nil {
+		return infer.CreateResponse[Auth0OidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "oidc",
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[Auth0OidcConnectorState]{}, provider.WrapError("create", "auth0-oidc-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[Auth0OidcConnectorState]{}, err
+		}
+	}
+
+	state := Auth0OidcConnectorState{
+		Auth0OidcConnectorArgs: args,
+		CallbackUrl:            dexCallbackURL(ctx),
+	}
+
+	return infer.CreateResponse[Auth0OidcConnectorState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing Auth0 OIDC connector.
+func (c *Auth0OidcConnector) Read(ctx context.Context, req infer.ReadRequest[Auth0OidcConnectorArgs, Auth0OidcConnectorState]) (infer.ReadResponse[Auth0OidcConnectorArgs, Auth0OidcConnectorState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[Auth0OidcConnectorArgs, Auth0OidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[Auth0OidcConnectorArgs, Auth0OidcConnectorState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[Auth0OidcConnectorArgs, Auth0OidcConnectorState]{}, nil
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildAuth0OidcConfig(req.State.Auth0OidcConnectorArgs))
+	}
+
+	// Extract the domain from the issuer, with or without its trailing slash
+	domain := auth0DomainFromIssuer(GetString(configMap, "issuer"))
+
+	scopes, _ := configMap["scopes"].([]any)
+	scopesStr := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if str, ok := s.(string); ok {
+			scopesStr = append(scopesStr, str)
+		}
+	}
+
+	args := Auth0OidcConnectorArgs{
+		ConnectorId:    found.Id,
+		Name:           found.Name,
+		Domain:         domain,
+		ClientId:       GetString(configMap, "clientID"),
+		ClientSecret:   readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
+		RedirectUri:    GetString(configMap, "redirectURI"),
+		Scopes:         scopesStr,
+		UserNameSource: GetStringPtr(configMap, "userNameKey"),
+		ExtraOidc:      readExtraOidc(configMap, req.Inputs.ExtraOidc),
+	}
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := Auth0OidcConnectorState{
+		Auth0OidcConnectorArgs: args,
+		CallbackUrl:            dexCallbackURL(ctx),
+		RawConfigOut:           redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[Auth0OidcConnectorArgs, Auth0OidcConnectorState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing Auth0 OIDC connector.
+func (c *Auth0OidcConnector) Update(ctx context.Context, req infer.UpdateRequest[Auth0OidcConnectorArgs, Auth0OidcConnectorState]) (infer.UpdateResponse[Auth0OidcConnectorState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[Auth0OidcConnectorState]{}, err
+		}
+		state := Auth0OidcConnectorState{
+			Auth0OidcConnectorArgs: args,
+			CallbackUrl:            dexCallbackURL(ctx),
+		}
+		return infer.UpdateResponse[Auth0OidcConnectorState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[Auth0OidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("Auth0OidcConnector", oldState.Auth0OidcConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[Auth0OidcConnectorState]{}, err
+	}
+
+	configBytes, err := json.Marshal(buildAuth0OidcConfig(args))
+	if err != nil {
+		return infer.UpdateResponse[Auth0OidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[Auth0OidcConnectorState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   "oidc",
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[Auth0OidcConnectorState]{}, provider.WrapError("update", "auth0-oidc-connector", args.ConnectorId, err)
+	}
+
+	state := Auth0OidcConnectorState{
+		Auth0OidcConnectorArgs: args,
+		CallbackUrl:            dexCallbackURL(ctx),
+	}
+
+	return infer.UpdateResponse[Auth0OidcConnectorState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes a Auth0 OIDC connector.
+func (c *Auth0OidcConnector) Delete(ctx context.Context, req infer.DeleteRequest[Auth0OidcConnectorState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	// Note: Pulumi does not call Delete during preview, so no preview check needed
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "auth0-oidc-connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// applyAuth0OidcDefaults fills in defaults for optional Auth0OidcConnector inputs.
+// Unset scopes default to the provider's defaultOidcScopes, if set.
+func applyAuth0OidcDefaults(cfg provider.DexConfig, args *Auth0OidcConnectorArgs) {
+	args.Scopes = defaultOidcScopes(cfg, args.Scopes, "openid", "profile", "email")
+	if args.UserNameSource == nil {
+		defaultUserNameSource := "email"
+		args.UserNameSource = &defaultUserNameSource
+	}
+}
+
+// buildAuth0OidcConfig builds the Dex "oidc" connector config for an Auth0
+// tenant. The issuer is derived from domain and ends in a slash, since Dex
+// requires it to match the issuer in Auth0's discovery document exactly.
+func buildAuth0OidcConfig(args Auth0OidcConnectorArgs) map[string]any {
+	issuer := fmt.Sprintf("https://%s/", args.Domain)
+
+	oidcConfig := map[string]any{
+		"issuer":       issuer,
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"scopes":       args.Scopes,
+		"userNameKey":  provider.PtrOr(args.UserNameSource, "email"),
+	}
+
+	for k, v := range args.ExtraOidc {
+		oidcConfig[k] = v
+	}
+
+	return oidcConfig
+}
+
+// auth0DomainFromIssuer returns the host of an Auth0 issuer or domain,
+// dropping the https:// scheme and trailing slash.
+func auth0DomainFromIssuer(issuer string) string {
+	return strings.TrimRight(strings.TrimPrefix(issuer, "https://"), "/")
+}
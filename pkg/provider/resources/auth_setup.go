@@ -0,0 +1,514 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// AuthSetup - A connector plus a set of clients managed as one unit
+// ============================================================================
+
+// defaultAuthSetupCallbackPath is appended to each client's baseUrl when callbackPath is not set.
+const defaultAuthSetupCallbackPath = "/callback"
+
+// AuthSetupClient describes one OAuth2 client provisioned by an AuthSetup.
+type AuthSetupClient struct {
+	ClientId     string   `pulumi:"clientId"`
+	Name         string   `pulumi:"name"`
+	BaseUrl      string   `pulumi:"baseUrl"`
+	Secret       *string  `pulumi:"secret,optional" provider:"secret"`
+	Public       *bool    `pulumi:"public,optional"`
+	RedirectUris []string `pulumi:"redirectUris,optional"`
+}
+
+// AuthSetupClientOutput describes a client created by an AuthSetup.
+type AuthSetupClientOutput struct {
+	ClientId     string   `pulumi:"clientId"`
+	Secret       string   `pulumi:"secret" provider:"secret"`
+	RedirectUris []string `pulumi:"redirectUris"`
+}
+
+// AuthSetupArgs defines inputs for AuthSetup.
+type AuthSetupArgs struct {
+	Connector      ConnectorArgs     `pulumi:"connector"`
+	Clients        []AuthSetupClient `pulumi:"clients"`
+	CallbackPath   *string           `pulumi:"callbackPath,optional"`
+	TimeoutSeconds *int              `pulumi:"timeoutSeconds,optional"`
+}
+
+// AuthSetupState defines outputs for AuthSetup.
+type AuthSetupState struct {
+	AuthSetupArgs
+	ConnectorId   string                  `pulumi:"connectorId"`
+	ClientOutputs []AuthSetupClientOutput `pulumi:"clientOutputs"`
+}
+
+// AuthSetup provisions a connector and a set of clients that share a redirect-URI convention.
+type AuthSetup struct{}
+
+// Annotate provides schema metadata.
+func (r *AuthSetup) Annotate(a infer.Annotator) {
+	a.Describe(r, "Provisions a connector together with a set of OAuth2 clients as one logical unit. Each client's redirect URI is derived from its baseUrl plus a shared callbackPath.")
+}
+
+// Annotate provides schema metadata for AuthSetupArgs.
+func (r *AuthSetupArgs) Annotate(a infer.Annotator) {
+	a.Describe(&r.Connector, "The connector to provision.")
+	a.Describe(&r.Clients, "The OAuth2 clients to provision alongside the connector.")
+	a.Describe(&r.CallbackPath, "Path appended to each client's baseUrl to form its redirect URI. Defaults to '/callback'.")
+	a.Describe(&r.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for AuthSetupClient.
+func (r *AuthSetupClient) Annotate(a infer.Annotator) {
+	a.Describe(&r.ClientId, "Unique identifier for the OAuth2 client.")
+	a.Describe(&r.Name, "Human-readable name for the OAuth2 client.")
+	a.Describe(&r.BaseUrl, "Base URL of the application, e.g. 'https://app.example.com'. The redirect URI is baseUrl + callbackPath.")
+	a.Describe(&r.Secret, "Client secret. If not provided, a secure random secret is generated.")
+	a.Describe(&r.Public, "If true, the client is a public client.")
+	a.Describe(&r.RedirectUris, "Additional redirect URIs beyond the derived one.")
+}
+
+// Annotate provides schema metadata for AuthSetupClientOutput.
+func (r *AuthSetupClientOutput) Annotate(a infer.Annotator) {
+	a.Describe(&r.ClientId, "The client ID.")
+	a.Describe(&r.Secret, "The client secret.")
+	a.Describe(&r.RedirectUris, "The redirect URIs registered for the client.")
+}
+
+// Annotate provides schema metadata for AuthSetupState.
+func (r *AuthSetupState) Annotate(a infer.Annotator) {
+	a.Describe(&r.ConnectorId, "ID of the provisioned connector.")
+	a.Describe(&r.ClientOutputs, "Details of each provisioned client.")
+}
+
+// Check validates inputs.
+func (r *AuthSetup) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[AuthSetupArgs], error) {
+	args, failures, err := infer.DefaultCheck[AuthSetupArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[AuthSetupArgs]{Failures: failures}, err
+	}
+
+	if err := validateConnectorArgs(args.Connector); err != nil {
+		failures = append(failures, p.CheckFailure{Property: "connector", Reason: err.Error()})
+	}
+	if args.Connector.Enabled != nil {
+		failures = append(failures, p.CheckFailure{Property: "connector.enabled", Reason: "enabled is only supported on dex.Connector"})
+	}
+
+	seen := map[string]bool{}
+	for i, cl := range args.Clients {
+		if seen[cl.ClientId] {
+			failures = append(failures, p.CheckFailure{
+				Property: fmt.Sprintf("clients[%d].clientId", i),
+				Reason:   fmt.Sprintf("duplicate client ID %q", cl.ClientId),
+			})
+		}
+		seen[cl.ClientId] = true
+	}
+
+	if args.CallbackPath == nil || *args.CallbackPath == "" {
+		defaultPath := defaultAuthSetupCallbackPath
+		args.CallbackPath = &defaultPath
+	}
+
+	return infer.CheckResponse[AuthSetupArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (r *AuthSetup) Diff(ctx context.Context, req infer.DiffRequest[AuthSetupArgs, AuthSetupState]) (infer.DiffResponse, error) {
+	return diffResource("AuthSetup", req.State.AuthSetupArgs, req.Inputs), nil
+}
+
+// Create provisions the connector, then each client.
+func (r *AuthSetup) Create(ctx context.Context, req infer.CreateRequest[AuthSetupArgs]) (infer.CreateResponse[AuthSetupState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[AuthSetupState]{}, err
+		}
+		return infer.CreateResponse[AuthSetupState]{
+			ID:     args.Connector.ConnectorId,
+			Output: authSetupState(args, nil),
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[AuthSetupState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := buildConnectorConfigBytes(args.Connector)
+	if err != nil {
+		return infer.CreateResponse[AuthSetupState]{}, err
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: &api.Connector{
+			Id:     args.Connector.ConnectorId,
+			Type:   args.Connector.Type,
+			Name:   args.Connector.Name,
+			Config: configBytes,
+		},
+	})
+	if err != nil {
+		return infer.CreateResponse[AuthSetupState]{}, provider.WrapError("create", "auth-setup-connector", args.Connector.ConnectorId, err)
+	}
+	if resp.AlreadyExists {
+		return infer.CreateResponse[AuthSetupState]{}, fmt.Errorf("connector with id %q %w", args.Connector.ConnectorId, provider.ErrAlreadyExists)
+	}
+
+	secrets := map[string]string{}
+	for _, cl := range args.Clients {
+		secret, err := createAuthSetupClient(ctx, cfg, cl, authSetupRedirectUris(cl, *args.CallbackPath), args.TimeoutSeconds)
+		if err != nil {
+			// Roll back what was created so a retried Create starts clean.
+			deleteAuthSetupObjects(ctx, cfg, args.Connector.ConnectorId, mapKeys(secrets), args.TimeoutSeconds)
+			return infer.CreateResponse[AuthSetupState]{}, err
+		}
+		secrets[cl.ClientId] = secret
+	}
+
+	return infer.CreateResponse[AuthSetupState]{
+		ID:     args.Connector.ConnectorId,
+		Output: authSetupState(args, secrets),
+	}, nil
+}
+
+// Read refreshes the connector and clients from Dex.
+func (r *AuthSetup) Read(ctx context.Context, req infer.ReadRequest[AuthSetupArgs, AuthSetupState]) (infer.ReadResponse[AuthSetupArgs, AuthSetupState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[AuthSetupArgs, AuthSetupState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[AuthSetupArgs, AuthSetupState]{}, err
+	}
+	if found == nil {
+		// The connector anchors the setup; without it the resource is gone.
+		return infer.ReadResponse[AuthSetupArgs, AuthSetupState]{}, nil
+	}
+
+	connectorArgs, _, err := decodeConnector(found)
+	if err != nil {
+		return infer.ReadResponse[AuthSetupArgs, AuthSetupState]{}, err
+	}
+
+	args := req.State.AuthSetupArgs
+	args.Connector = connectorArgs
+
+	// Refresh each known client; drop the ones that no longer exist.
+	clients := make([]AuthSetupClient, 0, len(args.Clients))
+	secrets := map[string]string{}
+	for _, cl := range args.Clients {
+		getCtx, getCancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.Inputs.TimeoutSeconds))
+		getResp, err := cfg.Client.GetClient(getCtx, &api.GetClientReq{Id: cl.ClientId})
+		getCancel()
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return infer.ReadResponse[AuthSetupArgs, AuthSetupState]{}, fmt.Errorf("failed to get Dex client %q: %w", cl.ClientId, err)
+		}
+		if getResp.Client == nil {
+			continue
+		}
+		cl.Name = getResp.Client.Name
+		secrets[cl.ClientId] = getResp.Client.Secret
+		clients = append(clients, cl)
+	}
+	args.Clients = clients
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := authSetupState(args, secrets)
+
+	return infer.ReadResponse[AuthSetupArgs, AuthSetupState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update reconciles the connector and the client set: new clients are created,
+// existing ones updated, and removed ones deleted.
+func (r *AuthSetup) Update(ctx context.Context, req infer.UpdateRequest[AuthSetupArgs, AuthSetupState]) (infer.UpdateResponse[AuthSetupState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[AuthSetupState]{}, err
+		}
+		// Recreated clients get the new secret input, or a new generated one.
+		secrets := authSetupSecrets(oldState)
+		for _, old := range oldState.Clients {
+			for _, cl := range args.Clients {
+				if cl.ClientId == old.ClientId && authSetupClientRecreated(old, cl) {
+					delete(secrets, cl.ClientId)
+				}
+			}
+		}
+		return infer.UpdateResponse[AuthSetupState]{Output: authSetupState(args, secrets)}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[AuthSetupState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("AuthSetup", oldState.AuthSetupArgs, args); err != nil {
+		return infer.UpdateResponse[AuthSetupState]{}, err
+	}
+
+	configBytes, err := buildConnectorConfigBytes(args.Connector)
+	if err != nil {
+		return infer.UpdateResponse[AuthSetupState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.Connector.ConnectorId,
+		NewType:   args.Connector.Type,
+		NewName:   args.Connector.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[AuthSetupState]{}, provider.WrapError("update", "auth-setup-connector", args.Connector.ConnectorId, err)
+	}
+
+	secrets, err := updateAuthSetupClients(ctx, cfg, args, oldState)
+	if err != nil {
+		return infer.UpdateResponse[AuthSetupState]{}, err
+	}
+
+	return infer.UpdateResponse[AuthSetupState]{Output: authSetupState(args, secrets)}, nil
+}
+
+// updateAuthSetupClients reconciles the clients of an AuthSetup with args and
+// returns the secret of each client in args. Dex's UpdateClient cannot change
+// a client's secret or public flag, so a client whose secret or public input
+// changed is deleted and created again, as rotateSecret does for a Client.
+func updateAuthSetupClients(ctx context.Context, cfg provider.DexConfig, args AuthSetupArgs, oldState AuthSetupState) (map[string]string, error) {
+	oldSecrets := authSetupSecrets(oldState)
+	oldClients := make(map[string]AuthSetupClient, len(oldState.Clients))
+	for _, cl := range oldState.Clients {
+		oldClients[cl.ClientId] = cl
+	}
+
+	secrets := map[string]string{}
+	desired := map[string]bool{}
+	for _, cl := range args.Clients {
+		desired[cl.ClientId] = true
+		redirectUris := authSetupRedirectUris(cl, *args.CallbackPath)
+
+		old, exists := oldClients[cl.ClientId]
+		if exists && authSetupClientRecreated(old, cl) {
+			if err := deleteDexClient(ctx, cfg, cl.ClientId, args.TimeoutSeconds); err != nil {
+				return nil, err
+			}
+			exists = false
+		}
+		if !exists {
+			secret, err := createAuthSetupClient(ctx, cfg, cl, redirectUris, args.TimeoutSeconds)
+			if err != nil {
+				return nil, err
+			}
+			secrets[cl.ClientId] = secret
+			continue
+		}
+
+		clientCtx, clientCancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+		_, err := cfg.Client.UpdateClient(clientCtx, &api.UpdateClientReq{
+			Id:           cl.ClientId,
+			Name:         cl.Name,
+			RedirectUris: redirectUris,
+		})
+		clientCancel()
+		if err != nil {
+			return nil, provider.WrapError("update", "auth-setup-client", cl.ClientId, err)
+		}
+		secrets[cl.ClientId] = oldSecrets[cl.ClientId]
+	}
+
+	for id := range oldClients {
+		if desired[id] {
+			continue
+		}
+		if err := deleteDexClient(ctx, cfg, id, args.TimeoutSeconds); err != nil {
+			return nil, err
+		}
+	}
+	return secrets, nil
+}
+
+// authSetupClientRecreated reports whether a client's secret or public input
+// changed, which UpdateClient cannot apply in place.
+func authSetupClientRecreated(old, cl AuthSetupClient) bool {
+	return provider.PtrOr(old.Secret, "") != provider.PtrOr(cl.Secret, "") ||
+		provider.PtrOr(old.Public, false) != provider.PtrOr(cl.Public, false)
+}
+
+// Delete removes the clients first, then the connector.
+func (r *AuthSetup) Delete(ctx context.Context, req infer.DeleteRequest[AuthSetupState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	connectorID := req.ID
+	if connectorID == "" {
+		connectorID = req.State.ConnectorId
+	}
+
+	for _, cl := range req.State.Clients {
+		if err := deleteDexClient(ctx, cfg, cl.ClientId, req.State.TimeoutSeconds); err != nil {
+			return infer.DeleteResponse{}, err
+		}
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{Id: connectorID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "auth-setup-connector", connectorID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, connectorID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// authSetupRedirectUris derives a client's redirect URIs from its baseUrl and the shared callback path.
+func authSetupRedirectUris(cl AuthSetupClient, callbackPath string) []string {
+	derived := strings.TrimRight(cl.BaseUrl, "/") + "/" + strings.TrimLeft(callbackPath, "/")
+	uris := []string{derived}
+	for _, u := range cl.RedirectUris {
+		if u != derived {
+			uris = append(uris, u)
+		}
+	}
+	return uris
+}
+
+// authSetupState assembles the output state, including the aggregated per-client outputs.
+func authSetupState(args AuthSetupArgs, secrets map[string]string) AuthSetupState {
+	callbackPath := provider.PtrOr(args.CallbackPath, defaultAuthSetupCallbackPath)
+
+	outputs := make([]AuthSetupClientOutput, 0, len(args.Clients))
+	for _, cl := range args.Clients {
+		secret := provider.PtrOr(cl.Secret, "")
+		if s, ok := secrets[cl.ClientId]; ok {
+			secret = s
+		}
+		outputs = append(outputs, AuthSetupClientOutput{
+			ClientId:     cl.ClientId,
+			Secret:       secret,
+			RedirectUris: authSetupRedirectUris(cl, callbackPath),
+		})
+	}
+
+	return AuthSetupState{
+		AuthSetupArgs: args,
+		ConnectorId:   args.Connector.ConnectorId,
+		ClientOutputs: outputs,
+	}
+}
+
+// authSetupSecrets indexes the secrets recorded in state by client ID.
+func authSetupSecrets(state AuthSetupState) map[string]string {
+	secrets := make(map[string]string, len(state.ClientOutputs))
+	for _, o := range state.ClientOutputs {
+		secrets[o.ClientId] = o.Secret
+	}
+	return secrets
+}
+
+// createAuthSetupClient creates one client and returns its secret.
+func createAuthSetupClient(ctx context.Context, cfg provider.DexConfig, cl AuthSetupClient, redirectUris []string, timeoutSeconds *int) (string, error) {
+	secret := provider.PtrOr(cl.Secret, "")
+	if secret == "" {
+		generated, err := generateClientSecret()
+		if err != nil {
+			return "", provider.WrapError("create", "auth-setup-client", cl.ClientId, err)
+		}
+		secret = generated
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateClient(createCtx, &api.CreateClientReq{
+		Client: &api.Client{
+			Id:           cl.ClientId,
+			Secret:       secret,
+			Name:         cl.Name,
+			RedirectUris: redirectUris,
+			Public:       provider.PtrOr(cl.Public, false),
+		},
+	})
+	if err != nil {
+		return "", provider.WrapError("create", "auth-setup-client", cl.ClientId, err)
+	}
+	if resp.AlreadyExists {
+		return "", fmt.Errorf("client with id %q %w", cl.ClientId, provider.ErrAlreadyExists)
+	}
+	return secret, nil
+}
+
+// deleteDexClient deletes a client, treating NotFound as success.
+func deleteDexClient(ctx context.Context, cfg provider.DexConfig, id string, timeoutSeconds *int) error {
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteClient(deleteCtx, &api.DeleteClientReq{Id: id})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return provider.WrapError("delete", "auth-setup-client", id, err)
+	}
+	return nil
+}
+
+// deleteAuthSetupObjects makes a best-effort attempt to remove partially created objects.
+func deleteAuthSetupObjects(ctx context.Context, cfg provider.DexConfig, connectorID string, clientIDs []string, timeoutSeconds *int) {
+	for _, id := range clientIDs {
+		_ = deleteDexClient(ctx, cfg, id, timeoutSeconds)
+	}
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+	_, _ = cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{Id: connectorID})
+}
+
+// mapKeys returns the keys of m in unspecified order.
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,112 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+func TestUpdateAuthSetupClientsSecretChange(t *testing.T) {
+	oldSecret, newSecret := "old-secret", "new-secret"
+	callbackPath := "/callback"
+	app := AuthSetupClient{ClientId: "app", Name: "App", BaseUrl: "https://app.example.com", Secret: &oldSecret}
+	other := AuthSetupClient{ClientId: "other", Name: "Other", BaseUrl: "https://other.example.com", Secret: &oldSecret}
+	oldArgs := AuthSetupArgs{Clients: []AuthSetupClient{app, other}, CallbackPath: &callbackPath}
+	oldState := authSetupState(oldArgs, nil)
+
+	dex := &fakeDex{clients: []*api.Client{
+		{Id: "app", Name: "App", Secret: oldSecret, RedirectUris: []string{"https://app.example.com/callback"}},
+		{Id: "other", Name: "Other", Secret: oldSecret, RedirectUris: []string{"https://other.example.com/callback"}},
+	}}
+	cfg := provider.DexConfig{Client: dex}
+
+	rotated := app
+	rotated.Secret = &newSecret
+	renamed := other
+	renamed.Name = "Other App"
+	args := AuthSetupArgs{Clients: []AuthSetupClient{rotated, renamed}, CallbackPath: &callbackPath}
+
+	secrets, err := updateAuthSetupClients(context.Background(), cfg, args, oldState)
+	if err != nil {
+		t.Fatalf("updateAuthSetupClients() = %v", err)
+	}
+	if got := dex.client("app"); got == nil || got.Secret != newSecret {
+		t.Errorf("Dex client app = %v, want secret %q", got, newSecret)
+	}
+	if secrets["app"] != newSecret {
+		t.Errorf("secret of app = %q, want %q", secrets["app"], newSecret)
+	}
+	if got := dex.client("other"); got == nil || got.Secret != oldSecret || got.Name != "Other App" {
+		t.Errorf("Dex client other = %v, want it renamed in place with its secret kept", got)
+	}
+	if secrets["other"] != oldSecret {
+		t.Errorf("secret of other = %q, want %q", secrets["other"], oldSecret)
+	}
+
+	// Making a client public also recreates it.
+	public := true
+	state := authSetupState(args, secrets)
+	published := rotated
+	published.Public = &public
+	args.Clients = []AuthSetupClient{published, renamed}
+	if _, err := updateAuthSetupClients(context.Background(), cfg, args, state); err != nil {
+		t.Fatalf("updateAuthSetupClients() = %v", err)
+	}
+	if got := dex.client("app"); got == nil || !got.Public {
+		t.Errorf("Dex client app = %v, want public", got)
+	}
+}
+
+func TestAuthSetupConnectorIdChangeReplaces(t *testing.T) {
+	callbackPath := "/callback"
+	config := `{"issuer":"https://idp.example.com"}`
+	olds := AuthSetupArgs{
+		Connector:    ConnectorArgs{ConnectorId: "sso", Type: "oidc", Name: "SSO", RawConfig: &config},
+		Clients:      []AuthSetupClient{{ClientId: "app", Name: "App", BaseUrl: "https://app.example.com"}},
+		CallbackPath: &callbackPath,
+	}
+
+	diffSetup := func(news AuthSetupArgs) infer.DiffResponse {
+		resp, err := (&AuthSetup{}).Diff(context.Background(), infer.DiffRequest[AuthSetupArgs, AuthSetupState]{
+			ID:     "sso",
+			State:  authSetupState(olds, nil),
+			Inputs: news,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	renamed := olds
+	renamed.Connector.ConnectorId = "sso2"
+	diff := diffSetup(renamed)
+	if got := diff.DetailedDiff["connector.connectorId"].Kind; got != p.UpdateReplace {
+		t.Errorf("connector.connectorId diff kind = %q, want %q (diff %+v)", got, p.UpdateReplace, diff.DetailedDiff)
+	}
+	if !diff.DeleteBeforeReplace {
+		t.Error("DeleteBeforeReplace = false, want true")
+	}
+	if err := checkImmutableFields("AuthSetup", olds, renamed); err == nil {
+		t.Error("checkImmutableFields() = nil, want an error for connector.connectorId")
+	}
+
+	retitled := olds
+	retitled.Connector.Name = "Company SSO"
+	diff = diffSetup(retitled)
+	if got := diff.DetailedDiff["connector.name"].Kind; got != p.Update || diff.DeleteBeforeReplace {
+		t.Errorf("connector.name diff = %+v, want an in-place update", diff)
+	}
+
+	moreClients := olds
+	moreClients.Clients = append([]AuthSetupClient{}, olds.Clients...)
+	moreClients.Clients = append(moreClients.Clients, AuthSetupClient{ClientId: "cli", Name: "CLI", BaseUrl: "http://localhost:8000"})
+	diff = diffSetup(moreClients)
+	if got := diff.DetailedDiff["clients"].Kind; got != p.Update || diff.DeleteBeforeReplace {
+		t.Errorf("clients diff = %+v, want an in-place update", diff)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"time"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
@@ -31,11 +30,21 @@ type AzureOidcConnectorArgs struct {
 	Scopes         []string       `pulumi:"scopes,optional"`
 	UserNameSource *string        `pulumi:"userNameSource,optional"` // "preferred_username" | "upn" | "email"
 	ExtraOidc      map[string]any `pulumi:"extraOidc,optional"`      // Additional OIDC config fields
+
+	OverrideClaimMapping *bool             `pulumi:"overrideClaimMapping,optional"`
+	ClaimMapping         *OIDCClaimMapping `pulumi:"claimMapping,optional"`
+	AllowedDomains       []string          `pulumi:"allowedDomains,optional"`
+	TimeoutSeconds       *int              `pulumi:"timeoutSeconds,optional"`
 }
 
+// bareDomainRegex matches a DNS domain name without scheme, port, or path.
+var bareDomainRegex = regexp.MustCompile(`(?i)^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
 // AzureOidcConnectorState defines outputs for AzureOidcConnector.
 type AzureOidcConnectorState struct {
 	AzureOidcConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
 }
 
 // AzureOidcConnector manages an Azure/Entra ID connector using Dex's generic OIDC connector.
@@ -54,14 +63,20 @@ func (c *AzureOidcConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.ClientId, "Azure AD application (client) ID.")
 	a.Describe(&c.ClientSecret, "Azure AD application client secret.")
 	a.Describe(&c.RedirectUri, "Redirect URI registered in Azure AD. Must match Dex's callback URL (typically 'https://dex.example.com/callback').")
-	a.Describe(&c.Scopes, "OIDC scopes to request from Azure AD. Defaults to ['openid', 'profile', 'email', 'offline_access'] if not specified.")
+	a.Describe(&c.Scopes, "OIDC scopes to request from Azure AD. Defaults to ['openid', 'profile', 'email', 'offline_access'] if not specified. Resource scopes such as 'api://<app-id>/.default' are accepted.")
 	a.Describe(&c.UserNameSource, "Source for the username claim. Valid values: 'preferred_username' (default), 'upn' (User Principal Name), or 'email'.")
 	a.Describe(&c.ExtraOidc, "Additional OIDC configuration fields as key-value pairs for advanced scenarios.")
+	a.Describe(&c.OverrideClaimMapping, "If true, Dex uses claimMapping even when the standard claims are present in the ID token.")
+	a.Describe(&c.ClaimMapping, "Mapping of nonstandard upstream claims to Dex user attributes (e.g. Entra's 'roles' as groups).")
+	a.Describe(&c.AllowedDomains, "Restricts logins to these verified domains (e.g. 'example.com'). Written as Dex's 'hostedDomains', which Dex matches against the 'hd' claim, so the Entra app must emit 'hd' (for example via a claims mapping policy). Useful for multi-tenant apps.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for AzureOidcConnectorState.
 func (c *AzureOidcConnectorState) Annotate(a infer.Annotator) {
 	// AzureOidcConnectorState embeds AzureOidcConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
 }
 
 // Check validates inputs before creation/update.
@@ -71,6 +86,8 @@ func (c *AzureOidcConnector) Check(ctx context.Context, req infer.CheckRequest)
 		return infer.CheckResponse[AzureOidcConnectorArgs]{Failures: failures}, err
 	}
 
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
 	// Validate tenantId format (UUID)
 	if args.TenantId != "" {
 		uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
@@ -93,17 +110,33 @@ func (c *AzureOidcConnector) Check(ctx context.Context, req infer.CheckRequest)
 		}
 	}
 
-	// Apply defaults
-	if len(args.Scopes) == 0 {
-		args.Scopes = []string{"openid", "profile", "email", "offline_access"}
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+	failures = append(failures, checkOpenIDScope(ctx, cfg, "scopes", args.Scopes)...)
+
+	// Validate allowedDomains
+	for i, d := range args.AllowedDomains {
+		if !bareDomainRegex.MatchString(d) {
+			failures = append(failures, p.CheckFailure{
+				Property: fmt.Sprintf("allowedDomains[%d]", i),
+				Reason:   fmt.Sprintf("must be a bare domain such as 'example.com', got %q", d),
+			})
+		}
 	}
 
+	applyAzureOidcDefaults(cfg, &args)
+
 	return infer.CheckResponse[AzureOidcConnectorArgs]{
 		Inputs:   args,
 		Failures: failures,
 	}, nil
 }
 
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *AzureOidcConnector) Diff(ctx context.Context, req infer.DiffRequest[AzureOidcConnectorArgs, AzureOidcConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("AzureOidcConnector", req.State.AzureOidcConnectorArgs, req.Inputs), nil
+}
+
 // Create creates a new Azure OIDC connector.
 func (c *AzureOidcConnector) Create(ctx context.Context, req infer.CreateRequest[AzureOidcConnectorArgs]) (infer.CreateResponse[AzureOidcConnectorState], error) {
 	args := req.Inputs
@@ -111,8 +144,12 @@ func (c *AzureOidcConnector) Create(ctx context.Context, req infer.CreateRequest
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[AzureOidcConnectorState]{}, err
+		}
 		state := AzureOidcConnectorState{
 			AzureOidcConnectorArgs: args,
+			CallbackUrl:            dexCallbackURL(ctx),
 		}
 		return infer.CreateResponse[AzureOidcConnectorState]{
 			ID:     args.ConnectorId,
@@ -122,34 +159,10 @@ func (c *AzureOidcConnector) Create(ctx context.Context, req infer.CreateRequest
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[AzureOidcConnectorState]{}, fmt.Errorf("Dex client not configured")
-	}
-
-	// Derive issuer from tenantId
-	issuer := fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0", args.TenantId)
-
-	// Derive userNameKey from userNameSource
-	userNameKey := "preferred_username" // default
-	if args.UserNameSource != nil {
-		userNameKey = *args.UserNameSource
-	}
-
-	// Build OIDC config
-	oidcConfig := map[string]any{
-		"issuer":       issuer,
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-		"scopes":       args.Scopes,
-		"userNameKey":  userNameKey,
-	}
-
-	// Merge extraOidc fields
-	for k, v := range args.ExtraOidc {
-		oidcConfig[k] = v
+		return infer.CreateResponse[AzureOidcConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	configBytes, err := json.Marshal(oidcConfig)
+	configBytes, err := json.Marshal(buildAzureOidcConfig(args))
 	if err != nil {
 		return infer.CreateResponse[AzureOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
 	}
@@ -162,7 +175,7 @@ func (c *AzureOidcConnector) Create(ctx context.Context, req infer.CreateRequest
 		Config: configBytes,
 	}
 
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
@@ -173,11 +186,15 @@ func (c *AzureOidcConnector) Create(ctx context.Context, req infer.CreateRequest
 	}
 
 	if resp.AlreadyExists {
-		return infer.CreateResponse[AzureOidcConnectorState]{}, fmt.Errorf("connector with id %q already exists", args.ConnectorId)
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[AzureOidcConnectorState]{}, err
+		}
 	}
 
 	state := AzureOidcConnectorState{
 		AzureOidcConnectorArgs: args,
+		CallbackUrl:            dexCallbackURL(ctx),
 	}
 
 	return infer.CreateResponse[AzureOidcConnectorState]{
@@ -190,24 +207,13 @@ func (c *AzureOidcConnector) Create(ctx context.Context, req infer.CreateRequest
 func (c *AzureOidcConnector) Read(ctx context.Context, req infer.ReadRequest[AzureOidcConnectorArgs, AzureOidcConnectorState]) (infer.ReadResponse[AzureOidcConnectorArgs, AzureOidcConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[AzureOidcConnectorArgs, AzureOidcConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[AzureOidcConnectorArgs, AzureOidcConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
 	// List connectors and find by ID
-	listCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[AzureOidcConnectorArgs, AzureOidcConnectorState]{}, fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, conn := range listResp.Connectors {
-		if conn.Id == req.ID {
-			found = conn
-			break
-		}
+		return infer.ReadResponse[AzureOidcConnectorArgs, AzureOidcConnectorState]{}, err
 	}
 
 	if found == nil {
@@ -217,9 +223,16 @@ func (c *AzureOidcConnector) Read(ctx context.Context, req infer.ReadRequest[Azu
 
 	// Parse config back to args
 	var configMap map[string]any
-	if err := json.Unmarshal(found.Config, &configMap); err != nil {
-		// If we can't parse, return empty (connector exists but config is invalid)
-		return infer.ReadResponse[AzureOidcConnectorArgs, AzureOidcConnectorState]{}, nil
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildAzureOidcConfig(req.State.AzureOidcConnectorArgs))
 	}
 
 	// Extract tenantId from issuer
@@ -232,11 +245,12 @@ func (c *AzureOidcConnector) Read(ctx context.Context, req infer.ReadRequest[Azu
 		}
 	}
 
-	// Extract userNameKey and map to userNameSource
+	// Extract userNameKey and map to userNameSource. The default is reported
+	// as unset unless the program set it explicitly, so it does not diff.
 	userNameKey, _ := configMap["userNameKey"].(string)
 	userNameSource := &userNameKey
-	if userNameKey == "preferred_username" {
-		// This is the default, so we can leave it as is
+	if userNameKey == "" || (userNameKey == "preferred_username" && req.Inputs.UserNameSource == nil) {
+		userNameSource = nil
 	}
 
 	scopes, _ := configMap["scopes"].([]any)
@@ -247,20 +261,38 @@ func (c *AzureOidcConnector) Read(ctx context.Context, req infer.ReadRequest[Azu
 		}
 	}
 
+	var allowedDomains []string
+	if domainsVal, ok := configMap["hostedDomains"].([]any); ok {
+		for _, d := range domainsVal {
+			if str, ok := d.(string); ok {
+				allowedDomains = append(allowedDomains, str)
+			}
+		}
+	}
+
 	// Build args from config
 	args := AzureOidcConnectorArgs{
 		ConnectorId:    found.Id,
 		Name:           found.Name,
 		TenantId:       tenantId,
 		ClientId:       GetString(configMap, "clientID"),
-		ClientSecret:   GetString(configMap, "clientSecret"),
+		ClientSecret:   readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
 		RedirectUri:    GetString(configMap, "redirectURI"),
 		Scopes:         scopesStr,
 		UserNameSource: userNameSource,
+
+		OverrideClaimMapping: GetBoolPtr(configMap, "overrideClaimMapping"),
+		ClaimMapping:         decodeClaimMapping(configMap["claimMapping"]),
+		AllowedDomains:       allowedDomains,
+		ExtraOidc:            readExtraOidc(configMap, req.Inputs.ExtraOidc),
 	}
 
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
 	state := AzureOidcConnectorState{
 		AzureOidcConnectorArgs: args,
+		CallbackUrl:            dexCallbackURL(ctx),
+		RawConfigOut:           redactedRawConfig(found.Config),
 	}
 
 	return infer.ReadResponse[AzureOidcConnectorArgs, AzureOidcConnectorState]{
@@ -278,8 +310,12 @@ func (c *AzureOidcConnector) Update(ctx context.Context, req infer.UpdateRequest
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[AzureOidcConnectorState]{}, err
+		}
 		state := AzureOidcConnectorState{
 			AzureOidcConnectorArgs: args,
+			CallbackUrl:            dexCallbackURL(ctx),
 		}
 		return infer.UpdateResponse[AzureOidcConnectorState]{
 			Output: state,
@@ -288,43 +324,24 @@ func (c *AzureOidcConnector) Update(ctx context.Context, req infer.UpdateRequest
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[AzureOidcConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.UpdateResponse[AzureOidcConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	// Validate immutable fields
-	if args.ConnectorId != oldState.ConnectorId {
-		return infer.UpdateResponse[AzureOidcConnectorState]{}, fmt.Errorf("connectorId cannot be changed")
-	}
-	if args.TenantId != oldState.TenantId {
-		return infer.UpdateResponse[AzureOidcConnectorState]{}, fmt.Errorf("tenantId cannot be changed (would require replace)")
+	if err := checkImmutableFields("AzureOidcConnector", oldState.AzureOidcConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[AzureOidcConnectorState]{}, err
 	}
 
 	// Rebuild config (same as Create)
-	issuer := fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0", args.TenantId)
-	userNameKey := "preferred_username"
-	if args.UserNameSource != nil {
-		userNameKey = *args.UserNameSource
-	}
-
-	oidcConfig := map[string]any{
-		"issuer":       issuer,
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-		"scopes":       args.Scopes,
-		"userNameKey":  userNameKey,
-	}
-
-	for k, v := range args.ExtraOidc {
-		oidcConfig[k] = v
-	}
-
-	configBytes, err := json.Marshal(oidcConfig)
+	configBytes, err := json.Marshal(buildAzureOidcConfig(args))
 	if err != nil {
 		return infer.UpdateResponse[AzureOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
 	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[AzureOidcConnectorState]{}, err
+	}
 
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
@@ -339,6 +356,7 @@ func (c *AzureOidcConnector) Update(ctx context.Context, req infer.UpdateRequest
 
 	state := AzureOidcConnectorState{
 		AzureOidcConnectorArgs: args,
+		CallbackUrl:            dexCallbackURL(ctx),
 	}
 
 	return infer.UpdateResponse[AzureOidcConnectorState]{
@@ -350,7 +368,7 @@ func (c *AzureOidcConnector) Update(ctx context.Context, req infer.UpdateRequest
 func (c *AzureOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest[AzureOidcConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -360,7 +378,7 @@ func (c *AzureOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest
 
 	// Note: Pulumi does not call Delete during preview, so no preview check needed
 
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
@@ -373,27 +391,77 @@ func (c *AzureOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest
 		return infer.DeleteResponse{}, provider.WrapError("delete", "azure-oidc-connector", deleteID, err)
 	}
 
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
 	return infer.DeleteResponse{}, nil
 }
 
+// applyAzureOidcDefaults fills in defaults for optional AzureOidcConnector inputs.
+// Unset scopes default to the provider's defaultOidcScopes, if set.
+func applyAzureOidcDefaults(cfg provider.DexConfig, args *AzureOidcConnectorArgs) {
+	args.Scopes = defaultOidcScopes(cfg, args.Scopes, "openid", "profile", "email", "offline_access")
+}
+
+// buildAzureOidcConfig builds the Dex "oidc" connector config for an Entra ID tenant.
+// The issuer is derived from tenantId, and userNameKey from userNameSource.
+func buildAzureOidcConfig(args AzureOidcConnectorArgs) map[string]any {
+	issuer := fmt.Sprintf("https://login.microsoftonline.com/%s/v2.0", args.TenantId)
+
+	userNameKey := "preferred_username" // default
+	if args.UserNameSource != nil {
+		userNameKey = *args.UserNameSource
+	}
+
+	oidcConfig := map[string]any{
+		"issuer":       issuer,
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"scopes":       args.Scopes,
+		"userNameKey":  userNameKey,
+	}
+
+	// Merge extraOidc fields
+	if args.OverrideClaimMapping != nil {
+		oidcConfig["overrideClaimMapping"] = *args.OverrideClaimMapping
+	}
+	if cm := claimMappingConfig(args.ClaimMapping); cm != nil {
+		oidcConfig["claimMapping"] = cm
+	}
+	if len(args.AllowedDomains) > 0 {
+		oidcConfig["hostedDomains"] = args.AllowedDomains
+	}
+
+	for k, v := range args.ExtraOidc {
+		oidcConfig[k] = v
+	}
+
+	return oidcConfig
+}
+
 // ============================================================================
 // AzureMicrosoftConnector - Uses Dex's Microsoft-specific connector (type: "microsoft")
 // ============================================================================
 
 // AzureMicrosoftConnectorArgs defines inputs for AzureMicrosoftConnector using Microsoft connector.
 type AzureMicrosoftConnectorArgs struct {
-	ConnectorId  string  `pulumi:"connectorId"`
-	Name         string  `pulumi:"name"`
-	Tenant       string  `pulumi:"tenant"` // "common", "organizations", or tenant ID
-	ClientId     string  `pulumi:"clientId"`
-	ClientSecret string  `pulumi:"clientSecret" provider:"secret"`
-	RedirectUri  string  `pulumi:"redirectUri"`
-	Groups       *string `pulumi:"groups,optional"` // Group claim name, e.g., "groups"
+	ConnectorId    string  `pulumi:"connectorId"`
+	Name           string  `pulumi:"name"`
+	Tenant         string  `pulumi:"tenant"` // "common", "organizations", or tenant ID
+	ClientId       string  `pulumi:"clientId"`
+	ClientSecret   string  `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri    string  `pulumi:"redirectUri"`
+	Groups         *string `pulumi:"groups,optional"` // Group claim name, e.g., "groups"
+	TimeoutSeconds *int    `pulumi:"timeoutSeconds,optional"`
 }
 
 // AzureMicrosoftConnectorState defines outputs for AzureMicrosoftConnector.
 type AzureMicrosoftConnectorState struct {
 	AzureMicrosoftConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
 }
 
 // AzureMicrosoftConnector manages an Azure/Entra ID connector using Dex's Microsoft-specific connector.
@@ -413,11 +481,14 @@ func (c *AzureMicrosoftConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.ClientSecret, "Azure AD application client secret.")
 	a.Describe(&c.RedirectUri, "Redirect URI registered in Azure AD. Must match Dex's callback URL.")
 	a.Describe(&c.Groups, "Name of the claim that contains group memberships (e.g., 'groups'). Used for group-based access control.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for AzureMicrosoftConnectorState.
 func (c *AzureMicrosoftConnectorState) Annotate(a infer.Annotator) {
 	// AzureMicrosoftConnectorState embeds AzureMicrosoftConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
 }
 
 // Check validates inputs.
@@ -427,6 +498,8 @@ func (c *AzureMicrosoftConnector) Check(ctx context.Context, req infer.CheckRequ
 		return infer.CheckResponse[AzureMicrosoftConnectorArgs]{Failures: failures}, err
 	}
 
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
 	// Validate tenant format
 	if args.Tenant != "" && args.Tenant != "common" && args.Tenant != "organizations" {
 		// Check if it's a UUID
@@ -439,12 +512,19 @@ func (c *AzureMicrosoftConnector) Check(ctx context.Context, req infer.CheckRequ
 		}
 	}
 
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+
 	return infer.CheckResponse[AzureMicrosoftConnectorArgs]{
 		Inputs:   args,
 		Failures: failures,
 	}, nil
 }
 
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *AzureMicrosoftConnector) Diff(ctx context.Context, req infer.DiffRequest[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("AzureMicrosoftConnector", req.State.AzureMicrosoftConnectorArgs, req.Inputs), nil
+}
+
 // Create creates a new Azure Microsoft connector.
 func (c *AzureMicrosoftConnector) Create(ctx context.Context, req infer.CreateRequest[AzureMicrosoftConnectorArgs]) (infer.CreateResponse[AzureMicrosoftConnectorState], error) {
 	args := req.Inputs
@@ -452,8 +532,12 @@ func (c *AzureMicrosoftConnector) Create(ctx context.Context, req infer.CreateRe
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[AzureMicrosoftConnectorState]{}, err
+		}
 		state := AzureMicrosoftConnectorState{
 			AzureMicrosoftConnectorArgs: args,
+			CallbackUrl:                 dexCallbackURL(ctx),
 		}
 		return infer.CreateResponse[AzureMicrosoftConnectorState]{
 			ID:     args.ConnectorId,
@@ -463,22 +547,10 @@ func (c *AzureMicrosoftConnector) Create(ctx context.Context, req infer.CreateRe
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[AzureMicrosoftConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.CreateResponse[AzureMicrosoftConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	// Build Microsoft connector config
-	microsoftConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-		"tenant":       args.Tenant,
-	}
-
-	if args.Groups != nil {
-		microsoftConfig["groups"] = *args.Groups
-	}
-
-	configBytes, err := json.Marshal(microsoftConfig)
+	configBytes, err := json.Marshal(buildAzureMicrosoftConfig(args))
 	if err != nil {
 		return infer.CreateResponse[AzureMicrosoftConnectorState]{}, fmt.Errorf("failed to marshal Microsoft config: %w", err)
 	}
@@ -490,7 +562,7 @@ func (c *AzureMicrosoftConnector) Create(ctx context.Context, req infer.CreateRe
 		Config: configBytes,
 	}
 
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
@@ -501,11 +573,15 @@ func (c *AzureMicrosoftConnector) Create(ctx context.Context, req infer.CreateRe
 	}
 
 	if resp.AlreadyExists {
-		return infer.CreateResponse[AzureMicrosoftConnectorState]{}, fmt.Errorf("connector with id %q already exists", args.ConnectorId)
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[AzureMicrosoftConnectorState]{}, err
+		}
 	}
 
 	state := AzureMicrosoftConnectorState{
 		AzureMicrosoftConnectorArgs: args,
+		CallbackUrl:                 dexCallbackURL(ctx),
 	}
 
 	return infer.CreateResponse[AzureMicrosoftConnectorState]{
@@ -518,23 +594,12 @@ func (c *AzureMicrosoftConnector) Create(ctx context.Context, req infer.CreateRe
 func (c *AzureMicrosoftConnector) Read(ctx context.Context, req infer.ReadRequest[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]) (infer.ReadResponse[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	listCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]{}, fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, conn := range listResp.Connectors {
-		if conn.Id == req.ID {
-			found = conn
-			break
-		}
+		return infer.ReadResponse[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]{}, err
 	}
 
 	if found == nil {
@@ -542,8 +607,16 @@ func (c *AzureMicrosoftConnector) Read(ctx context.Context, req infer.ReadReques
 	}
 
 	var configMap map[string]any
-	if err := json.Unmarshal(found.Config, &configMap); err != nil {
-		return infer.ReadResponse[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]{}, nil
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildAzureMicrosoftConfig(req.State.AzureMicrosoftConnectorArgs))
 	}
 
 	groups := GetStringPtr(configMap, "groups")
@@ -553,13 +626,17 @@ func (c *AzureMicrosoftConnector) Read(ctx context.Context, req infer.ReadReques
 		Name:         found.Name,
 		Tenant:       GetString(configMap, "tenant"),
 		ClientId:     GetString(configMap, "clientID"),
-		ClientSecret: GetString(configMap, "clientSecret"),
+		ClientSecret: readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
 		RedirectUri:  GetString(configMap, "redirectURI"),
 		Groups:       groups,
 	}
 
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
 	state := AzureMicrosoftConnectorState{
 		AzureMicrosoftConnectorArgs: args,
+		CallbackUrl:                 dexCallbackURL(ctx),
+		RawConfigOut:                redactedRawConfig(found.Config),
 	}
 
 	return infer.ReadResponse[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]{
@@ -577,8 +654,12 @@ func (c *AzureMicrosoftConnector) Update(ctx context.Context, req infer.UpdateRe
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, err
+		}
 		state := AzureMicrosoftConnectorState{
 			AzureMicrosoftConnectorArgs: args,
+			CallbackUrl:                 dexCallbackURL(ctx),
 		}
 		return infer.UpdateResponse[AzureMicrosoftConnectorState]{
 			Output: state,
@@ -587,33 +668,23 @@ func (c *AzureMicrosoftConnector) Update(ctx context.Context, req infer.UpdateRe
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	if args.ConnectorId != oldState.ConnectorId {
-		return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, fmt.Errorf("connectorId cannot be changed")
-	}
-	if args.Tenant != oldState.Tenant {
-		return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, fmt.Errorf("tenant cannot be changed (would require replace)")
-	}
-
-	microsoftConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-		"tenant":       args.Tenant,
+	if err := checkImmutableFields("AzureMicrosoftConnector", oldState.AzureMicrosoftConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, err
 	}
 
-	if args.Groups != nil {
-		microsoftConfig["groups"] = *args.Groups
-	}
-
-	configBytes, err := json.Marshal(microsoftConfig)
+	configBytes, err := json.Marshal(buildAzureMicrosoftConfig(args))
 	if err != nil {
 		return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, fmt.Errorf("failed to marshal Microsoft config: %w", err)
 	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[AzureMicrosoftConnectorState]{}, err
+	}
 
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
@@ -628,6 +699,7 @@ func (c *AzureMicrosoftConnector) Update(ctx context.Context, req infer.UpdateRe
 
 	state := AzureMicrosoftConnectorState{
 		AzureMicrosoftConnectorArgs: args,
+		CallbackUrl:                 dexCallbackURL(ctx),
 	}
 
 	return infer.UpdateResponse[AzureMicrosoftConnectorState]{
@@ -639,7 +711,7 @@ func (c *AzureMicrosoftConnector) Update(ctx context.Context, req infer.UpdateRe
 func (c *AzureMicrosoftConnector) Delete(ctx context.Context, req infer.DeleteRequest[AzureMicrosoftConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -649,7 +721,7 @@ func (c *AzureMicrosoftConnector) Delete(ctx context.Context, req infer.DeleteRe
 
 	// Note: Pulumi does not call Delete during preview, so no preview check needed
 
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
@@ -662,5 +734,25 @@ func (c *AzureMicrosoftConnector) Delete(ctx context.Context, req infer.DeleteRe
 		return infer.DeleteResponse{}, provider.WrapError("delete", "azure-microsoft-connector", deleteID, err)
 	}
 
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
 	return infer.DeleteResponse{}, nil
 }
+
+// buildAzureMicrosoftConfig builds the Dex "microsoft" connector config from args.
+func buildAzureMicrosoftConfig(args AzureMicrosoftConnectorArgs) map[string]any {
+	microsoftConfig := map[string]any{
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"tenant":       args.Tenant,
+	}
+
+	if args.Groups != nil {
+		microsoftConfig["groups"] = *args.Groups
+	}
+
+	return microsoftConfig
+}
@@ -5,10 +5,12 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
 	"github.com/pulumi/pulumi-go-provider/infer"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -16,19 +18,23 @@ import (
 
 // ClientArgs defines the inputs for a dex.Client resource.
 type ClientArgs struct {
-	ClientId     string   `pulumi:"clientId"`
-	Name         string   `pulumi:"name"`
-	Secret       *string  `pulumi:"secret,optional" provider:"secret"`
-	RedirectUris []string `pulumi:"redirectUris"`
-	TrustedPeers []string `pulumi:"trustedPeers,optional"`
-	Public       *bool    `pulumi:"public,optional"`
-	LogoUrl      *string  `pulumi:"logoUrl,optional"`
+	ClientId       string   `pulumi:"clientId"`
+	Name           string   `pulumi:"name"`
+	Secret         *string  `pulumi:"secret,optional" provider:"secret"`
+	RedirectUris   []string `pulumi:"redirectUris"`
+	TrustedPeers   []string `pulumi:"trustedPeers,optional"`
+	Public         *bool    `pulumi:"public,optional"`
+	LogoUrl        *string  `pulumi:"logoUrl,optional"`
+	RotateSecret   *bool    `pulumi:"rotateSecret,optional"`
+	TimeoutSeconds *int     `pulumi:"timeoutSeconds,optional"`
 }
 
 // ClientState defines the outputs/state for a dex.Client resource.
 type ClientState struct {
 	ClientArgs
-	CreatedAt *string `pulumi:"createdAt,optional"`
+	SecretResult *string `pulumi:"secretResult,optional" provider:"secret"`
+	CreatedAt    *string `pulumi:"createdAt,optional"`
+	UpdatedAt    *string `pulumi:"updatedAt,optional"`
 }
 
 // Client represents a Dex OAuth2 client resource.
@@ -48,11 +54,42 @@ func (c *ClientArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.TrustedPeers, "List of trusted peer client IDs that can exchange tokens with this client.")
 	a.Describe(&c.Public, "If true, this client is a public client (e.g., mobile app) and does not require a client secret.")
 	a.Describe(&c.LogoUrl, "URL to a logo image for the OAuth2 client. Used in consent screens.")
+	a.Describe(&c.RotateSecret, "Changing this from false (or unset) to true generates a new client secret during the next update. Dex cannot change a client's secret in place, so the client is deleted and recreated under the same ID. The new secret is available as the secret output. The secret stays stable while this remains true or is false. Cannot be combined with an explicit secret.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for ClientState.
 func (c *ClientState) Annotate(a infer.Annotator) {
-	a.Describe(&c.CreatedAt, "Timestamp when the client was created (RFC3339 format).")
+	a.Describe(&c.SecretResult, "The client secret in effect, whether given as secret or generated by the provider. Reference this output downstream instead of passing the secret around in the program.")
+	a.Describe(&c.CreatedAt, "Timestamp when the client was created (RFC3339 format). Unset for imported clients, since Dex does not record creation time.")
+	a.Describe(&c.UpdatedAt, "Timestamp of the last update applied by the provider (RFC3339 format). Unset until the client is first updated.")
+}
+
+// Check validates Client inputs. With the provider's validateTrustedPeers set,
+// it also reports trusted peers that do not exist in Dex, so typos surface
+// during preview rather than as failed token exchanges. The check is skipped
+// when the provider has no Dex client.
+func (c *Client) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[ClientArgs], error) {
+	args, failures, err := infer.DefaultCheck[ClientArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[ClientArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if provider.PtrOr(cfg.ValidateTrustedPeers, false) && cfg.Client != nil {
+		unknown, err := unknownTrustedPeers(ctx, cfg, args.ClientId, args.TrustedPeers, args.TimeoutSeconds)
+		if err != nil {
+			p.GetLogger(ctx).Warningf("skipping trustedPeers check for client %q: failed to list clients: %v", args.ClientId, err)
+		}
+		for _, peer := range unknown {
+			failures = append(failures, p.CheckFailure{
+				Property: "trustedPeers",
+				Reason:   fmt.Sprintf("trusted peer %q is not an existing Dex client", peer),
+			})
+		}
+	}
+
+	return infer.CheckResponse[ClientArgs]{Inputs: args, Failures: failures}, nil
 }
 
 // Create creates a new OAuth2 client in Dex.
@@ -62,9 +99,14 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[ClientState]{}, err
+		}
 		// For preview, we just mirror the inputs into state and do NOT call Dex.
+		// A generated secret is not known until the client is created.
 		state := ClientState{
-			ClientArgs: args,
+			ClientArgs:   args,
+			SecretResult: args.Secret,
 		}
 		return infer.CreateResponse[ClientState]{
 			ID:     args.ClientId,
@@ -74,7 +116,15 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[ClientState]{}, fmt.Errorf("Dex client not configured")
+		return infer.CreateResponse[ClientState]{}, provider.ErrClientNotConfigured
+	}
+
+	if provider.PtrOr(args.RotateSecret, false) && args.Secret != nil && *args.Secret != "" {
+		return infer.CreateResponse[ClientState]{}, fmt.Errorf("client %q: rotateSecret cannot be used with an explicit secret", args.ClientId)
+	}
+
+	if err := validateTrustedPeers(ctx, cfg, args.ClientId, args.TrustedPeers, args.TimeoutSeconds); err != nil {
+		return infer.CreateResponse[ClientState]{}, err
 	}
 
 	// Generate secret if not provided
@@ -82,12 +132,11 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 	if args.Secret != nil && *args.Secret != "" {
 		secret = *args.Secret
 	} else {
-		// Generate a secure random secret (32 bytes = 256 bits, base64 encoded)
-		secretBytes := make([]byte, 32)
-		if _, err := rand.Read(secretBytes); err != nil {
-			return infer.CreateResponse[ClientState]{}, provider.WrapError("create", "client", args.ClientId, fmt.Errorf("failed to generate secret: %w", err))
+		generated, err := generateClientSecret()
+		if err != nil {
+			return infer.CreateResponse[ClientState]{}, provider.WrapError("create", "client", args.ClientId, err)
 		}
-		secret = base64.URLEncoding.EncodeToString(secretBytes)
+		secret = generated
 	}
 
 	// Build the Dex Client message
@@ -104,7 +153,7 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 	}
 
 	// Call Dex CreateClient
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateClient(createCtx, &api.CreateClientReq{
@@ -117,7 +166,7 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 	if resp.AlreadyExists {
 		// Resource already exists - read it and return it so Pulumi can track it
 		// This allows destroy to work properly even if the resource was created outside Pulumi
-		readCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+		readCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 		defer cancel()
 
 		getResp, err := cfg.Client.GetClient(readCtx, &api.GetClientReq{
@@ -130,14 +179,17 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 		// Build state from existing client
 		state := ClientState{
 			ClientArgs: ClientArgs{
-				ClientId:     getResp.Client.Id,
-				Name:         getResp.Client.Name,
-				Secret:       &getResp.Client.Secret,
-				RedirectUris: getResp.Client.RedirectUris,
-				TrustedPeers: getResp.Client.TrustedPeers,
-				Public:       &getResp.Client.Public,
-				LogoUrl:      &getResp.Client.LogoUrl,
+				ClientId:       getResp.Client.Id,
+				Name:           getResp.Client.Name,
+				Secret:         &getResp.Client.Secret,
+				RedirectUris:   getResp.Client.RedirectUris,
+				TrustedPeers:   getResp.Client.TrustedPeers,
+				Public:         &getResp.Client.Public,
+				LogoUrl:        &getResp.Client.LogoUrl,
+				RotateSecret:   args.RotateSecret,
+				TimeoutSeconds: args.TimeoutSeconds,
 			},
+			SecretResult: &getResp.Client.Secret,
 		}
 
 		return infer.CreateResponse[ClientState]{
@@ -150,15 +202,18 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 	now := time.Now().Format(time.RFC3339)
 	state := ClientState{
 		ClientArgs: ClientArgs{
-			ClientId:     args.ClientId,
-			Name:         args.Name,
-			Secret:       &secret,
-			RedirectUris: args.RedirectUris,
-			TrustedPeers: args.TrustedPeers,
-			Public:       args.Public,
-			LogoUrl:      args.LogoUrl,
+			ClientId:       args.ClientId,
+			Name:           args.Name,
+			Secret:         &secret,
+			RedirectUris:   args.RedirectUris,
+			TrustedPeers:   args.TrustedPeers,
+			Public:         args.Public,
+			LogoUrl:        args.LogoUrl,
+			RotateSecret:   args.RotateSecret,
+			TimeoutSeconds: args.TimeoutSeconds,
 		},
-		CreatedAt: &now,
+		SecretResult: &secret,
+		CreatedAt:    &now,
 	}
 
 	return infer.CreateResponse[ClientState]{
@@ -171,11 +226,11 @@ func (c *Client) Create(ctx context.Context, req infer.CreateRequest[ClientArgs]
 func (c *Client) Read(ctx context.Context, req infer.ReadRequest[ClientArgs, ClientState]) (infer.ReadResponse[ClientArgs, ClientState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[ClientArgs, ClientState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[ClientArgs, ClientState]{}, provider.ErrClientNotConfigured
 	}
 
 	// Call Dex GetClient
-	getCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	getCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.Inputs.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.GetClient(getCtx, &api.GetClientReq{
@@ -186,46 +241,86 @@ func (c *Client) Read(ctx context.Context, req infer.ReadRequest[ClientArgs, Cli
 			// Resource doesn't exist - return empty response to indicate deletion
 			return infer.ReadResponse[ClientArgs, ClientState]{}, nil
 		}
-		return infer.ReadResponse[ClientArgs, ClientState]{}, fmt.Errorf("failed to get Dex client: %w", err)
+		return infer.ReadResponse[ClientArgs, ClientState]{}, provider.WrapError("get", "client", req.ID, err)
 	}
 
 	if resp.Client == nil {
 		return infer.ReadResponse[ClientArgs, ClientState]{}, nil
 	}
 
-	client := resp.Client
+	inputs, state := clientReadResult(cfg, resp.Client, req.Inputs, req.State)
+	return infer.ReadResponse[ClientArgs, ClientState]{
+		ID:     resp.Client.Id,
+		Inputs: inputs,
+		State:  state,
+	}, nil
+}
 
+// clientReadResult builds Read's inputs and state from the client Dex
+// returned. inputArgs and prior are the program's inputs and the previous
+// state; both are empty on import.
+func clientReadResult(cfg provider.DexConfig, client *api.Client, inputArgs ClientArgs, prior ClientState) (ClientArgs, ClientState) {
 	// Build the state from Dex response
 	state := ClientState{
 		ClientArgs: ClientArgs{
-			ClientId:     client.Id,
-			Name:         client.Name,
-			Secret:       &client.Secret,
-			RedirectUris: client.RedirectUris,
-			TrustedPeers: client.TrustedPeers,
-			Public:       &client.Public,
-			LogoUrl:      PtrOrString(client.LogoUrl),
+			ClientId:       client.Id,
+			Name:           client.Name,
+			Secret:         &client.Secret,
+			RedirectUris:   client.RedirectUris,
+			TrustedPeers:   client.TrustedPeers,
+			Public:         &client.Public,
+			LogoUrl:        PtrOrString(client.LogoUrl),
+			RotateSecret:   inputArgs.RotateSecret,
+			TimeoutSeconds: inputArgs.TimeoutSeconds,
 		},
-		// Note: Dex API doesn't expose createdAt, so we keep the existing value if present
-		CreatedAt: req.State.CreatedAt,
+		// Note: Dex API doesn't expose createdAt/updatedAt, so we keep the existing values if present.
+		// On import there is no prior state, so both stay nil.
+		CreatedAt: prior.CreatedAt,
+		UpdatedAt: prior.UpdatedAt,
 	}
 
 	// Build inputs from the state (for normalization)
 	inputs := ClientArgs{
-		ClientId:     state.ClientId,
-		Name:         state.Name,
-		Secret:       state.Secret,
-		RedirectUris: state.RedirectUris,
-		TrustedPeers: state.TrustedPeers,
-		Public:       state.Public,
-		LogoUrl:      state.LogoUrl,
+		ClientId:       state.ClientId,
+		Name:           state.Name,
+		Secret:         state.Secret,
+		RedirectUris:   state.RedirectUris,
+		TrustedPeers:   state.TrustedPeers,
+		Public:         state.Public,
+		LogoUrl:        state.LogoUrl,
+		RotateSecret:   state.RotateSecret,
+		TimeoutSeconds: state.TimeoutSeconds,
 	}
 
-	return infer.ReadResponse[ClientArgs, ClientState]{
-		ID:     client.Id,
-		Inputs: inputs,
-		State:  state,
-	}, nil
+	// A secret the program did not specify is provider-managed: it stays in
+	// state only, so imported clients behave like ones created with a
+	// generated secret instead of pinning the live secret as an input.
+	if inputArgs.Secret == nil || *inputArgs.Secret == "" {
+		inputs.Secret = nil
+		// Some storage backends return public clients without their secret;
+		// keep the generated one rather than dropping it from state.
+		if client.Secret == "" && prior.Secret != nil {
+			state.Secret = prior.Secret
+		}
+	}
+	// With writeOnlySecrets the secret is not read back from Dex once it is
+	// in state, so it only ever comes from the program or generation.
+	if provider.PtrOr(cfg.WriteOnlySecrets, false) && prior.Secret != nil {
+		state.Secret = prior.Secret
+		if inputs.Secret != nil {
+			inputs.Secret = prior.Secret
+		}
+	}
+	state.SecretResult = state.Secret
+
+	return inputs, state
+}
+
+// Diff reports changes to clientId, secret, and public as replacements, since
+// UpdateClient cannot change them; other fields are updated in place.
+func (c *Client) Diff(ctx context.Context, req infer.DiffRequest[ClientArgs, ClientState]) (infer.DiffResponse, error) {
+	olds, news := comparableClientArgs(req.State.ClientArgs, req.Inputs)
+	return diffResource("Client", olds, news), nil
 }
 
 // Update updates an existing OAuth2 client in Dex.
@@ -236,8 +331,18 @@ func (c *Client) Update(ctx context.Context, req infer.UpdateRequest[ClientArgs,
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[ClientState]{}, err
+		}
 		state := ClientState{
-			ClientArgs: args,
+			ClientArgs:   args,
+			SecretResult: oldState.SecretResult,
+			CreatedAt:    oldState.CreatedAt,
+			UpdatedAt:    oldState.UpdatedAt,
+		}
+		if provider.PtrOr(args.RotateSecret, false) && !provider.PtrOr(oldState.RotateSecret, false) {
+			// The rotated secret is not known until the update runs.
+			state.SecretResult = nil
 		}
 		return infer.UpdateResponse[ClientState]{
 			Output: state,
@@ -246,12 +351,40 @@ func (c *Client) Update(ctx context.Context, req infer.UpdateRequest[ClientArgs,
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[ClientState]{}, fmt.Errorf("Dex client not configured")
+		return infer.UpdateResponse[ClientState]{}, provider.ErrClientNotConfigured
+	}
+
+	olds, news := comparableClientArgs(oldState.ClientArgs, args)
+	if err := checkImmutableFields("Client", olds, news); err != nil {
+		return infer.UpdateResponse[ClientState]{}, err
+	}
+
+	if err := validateTrustedPeers(ctx, cfg, args.ClientId, args.TrustedPeers, args.TimeoutSeconds); err != nil {
+		return infer.UpdateResponse[ClientState]{}, err
 	}
 
-	// Validate that clientId hasn't changed (it's immutable)
-	if args.ClientId != oldState.ClientId {
-		return infer.UpdateResponse[ClientState]{}, fmt.Errorf("clientId cannot be changed (was %q, got %q)", oldState.ClientId, args.ClientId)
+	now := time.Now().Format(time.RFC3339)
+
+	// Rotation only happens on the false -> true transition, so the secret
+	// stays stable on later updates while rotateSecret remains true.
+	if provider.PtrOr(args.RotateSecret, false) && !provider.PtrOr(oldState.RotateSecret, false) {
+		if args.Secret != nil && *args.Secret != "" {
+			return infer.UpdateResponse[ClientState]{}, fmt.Errorf("client %q: rotateSecret cannot be used with an explicit secret", args.ClientId)
+		}
+		secret, err := rotateClientSecret(ctx, cfg, args)
+		if err != nil {
+			return infer.UpdateResponse[ClientState]{}, err
+		}
+		state := ClientState{
+			ClientArgs: args,
+			CreatedAt:  oldState.CreatedAt,
+			UpdatedAt:  &now,
+		}
+		state.Secret = &secret
+		state.SecretResult = &secret
+		return infer.UpdateResponse[ClientState]{
+			Output: state,
+		}, nil
 	}
 
 	// Build the update request
@@ -265,39 +398,47 @@ func (c *Client) Update(ctx context.Context, req infer.UpdateRequest[ClientArgs,
 	}
 
 	// Call Dex UpdateClient
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.UpdateClient(updateCtx, updateReq)
 	if err != nil {
-		return infer.UpdateResponse[ClientState]{}, fmt.Errorf("failed to update Dex client: %w", err)
+		return infer.UpdateResponse[ClientState]{}, provider.WrapError("update", "client", args.ClientId, err)
 	}
 
-	// Build the updated state
-	// Keep the existing secret since it can't be updated via UpdateClient
-	state := ClientState{
+	return infer.UpdateResponse[ClientState]{
+		Output: updatedClientState(args, oldState, now),
+	}, nil
+}
+
+// updatedClientState builds the state after an in-place update. The secret
+// is unchanged: UpdateClient can't change it, so Diff plans a replacement for
+// a changed secret and checkImmutableFields rejects one that reaches Update.
+// An omitted secret keeps the generated one.
+func updatedClientState(args ClientArgs, oldState ClientState, updatedAt string) ClientState {
+	return ClientState{
 		ClientArgs: ClientArgs{
-			ClientId:     args.ClientId,
-			Name:         args.Name,
-			Secret:       oldState.Secret, // Keep existing secret
-			RedirectUris: args.RedirectUris,
-			TrustedPeers: args.TrustedPeers,
-			Public:       args.Public,
-			LogoUrl:      args.LogoUrl,
+			ClientId:       args.ClientId,
+			Name:           args.Name,
+			Secret:         oldState.Secret,
+			RedirectUris:   args.RedirectUris,
+			TrustedPeers:   args.TrustedPeers,
+			Public:         args.Public,
+			LogoUrl:        args.LogoUrl,
+			RotateSecret:   args.RotateSecret,
+			TimeoutSeconds: args.TimeoutSeconds,
 		},
-		CreatedAt: oldState.CreatedAt, // Preserve createdAt
+		SecretResult: oldState.Secret,
+		CreatedAt:    oldState.CreatedAt, // Preserve createdAt
+		UpdatedAt:    &updatedAt,
 	}
-
-	return infer.UpdateResponse[ClientState]{
-		Output: state,
-	}, nil
 }
 
 // Delete deletes an OAuth2 client from Dex.
 func (c *Client) Delete(ctx context.Context, req infer.DeleteRequest[ClientState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	// Use the ID from the request, or fall back to the state if available
@@ -312,7 +453,7 @@ func (c *Client) Delete(ctx context.Context, req infer.DeleteRequest[ClientState
 	// Note: Pulumi does not call Delete during preview, so no preview check needed
 
 	// Call Dex DeleteClient
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteClient(deleteCtx, &api.DeleteClientReq{
@@ -323,36 +464,129 @@ func (c *Client) Delete(ctx context.Context, req infer.DeleteRequest[ClientState
 			// Already deleted, treat as success
 			return infer.DeleteResponse{}, nil
 		}
-		return infer.DeleteResponse{}, fmt.Errorf("failed to delete Dex client %q: %w", deleteID, err)
+		return infer.DeleteResponse{}, provider.WrapError("delete", "client", deleteID, err)
+	}
+
+	// Verify the delete actually happened by checking if the client still exists.
+	// This helps catch cases where DeleteClient returns success but doesn't actually delete.
+	// Use ListClients instead of GetClient for more reliable verification.
+	err = verifyDeleted(ctx, cfg, "client", deleteID, func(ctx context.Context) (bool, error) {
+		listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+		defer cancel()
+		listResp, err := cfg.Client.ListClients(listCtx, &api.ListClientReq{})
+		if err != nil {
+			return false, provider.WrapError("list", "clients", deleteID, err)
+		}
+		return findByID(listResp.Clients, deleteID) != nil, nil
+	})
+	if err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	// Client not found in list - delete was successful
+	return infer.DeleteResponse{}, nil
+}
+
+// comparableClientArgs prepares old and new client args for comparison. An
+// unset secret means the provider-managed secret in state is kept, so it
+// compares equal to it, and an unset public flag compares equal to false.
+func comparableClientArgs(olds, news ClientArgs) (ClientArgs, ClientArgs) {
+	if news.Secret == nil || *news.Secret == "" {
+		news.Secret = olds.Secret
+	}
+	notPublic := false
+	if olds.Public == nil {
+		olds.Public = &notPublic
+	}
+	if news.Public == nil {
+		news.Public = &notPublic
+	}
+	return olds, news
+}
+
+// rotateClientSecret replaces the client with a fresh generated secret and
+// returns it. Dex has no RPC to change a secret, so the client is deleted and
+// created again from args under the same ID.
+func rotateClientSecret(ctx context.Context, cfg provider.DexConfig, args ClientArgs) (string, error) {
+	secret, err := generateClientSecret()
+	if err != nil {
+		return "", provider.WrapError("rotate secret of", "client", args.ClientId, err)
+	}
+
+	rpcCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	if _, err := cfg.Client.DeleteClient(rpcCtx, &api.DeleteClientReq{Id: args.ClientId}); err != nil && status.Code(err) != codes.NotFound {
+		return "", provider.WrapError("rotate secret of", "client", args.ClientId, err)
+	}
+
+	_, err = cfg.Client.CreateClient(rpcCtx, &api.CreateClientReq{
+		Client: &api.Client{
+			Id:           args.ClientId,
+			Secret:       secret,
+			RedirectUris: args.RedirectUris,
+			TrustedPeers: args.TrustedPeers,
+			Public:       provider.PtrOr(args.Public, false),
+			Name:         args.Name,
+			LogoUrl:      provider.PtrOr(args.LogoUrl, ""),
+		},
+	})
+	if err != nil {
+		return "", provider.WrapError("rotate secret of", "client", args.ClientId, fmt.Errorf("client was deleted but could not be recreated: %w", err))
+	}
+	return secret, nil
+}
+
+// validateTrustedPeers ensures every trusted peer refers to a client that exists in Dex.
+// A client listing itself is always accepted, since it may not exist yet during Create.
+func validateTrustedPeers(ctx context.Context, cfg provider.DexConfig, clientID string, peers []string, timeoutSeconds *int) error {
+	unknown, err := unknownTrustedPeers(ctx, cfg, clientID, peers, timeoutSeconds)
+	if err != nil {
+		return provider.WrapError("list", "clients", clientID, fmt.Errorf("failed to validate trustedPeers: %w", err))
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("trustedPeers for client %q reference unknown client IDs: %s", clientID, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// unknownTrustedPeers returns the peers that are neither clientID nor an
+// existing client in Dex. Empty peers, e.g. unknown during preview, are skipped.
+func unknownTrustedPeers(ctx context.Context, cfg provider.DexConfig, clientID string, peers []string, timeoutSeconds *int) ([]string, error) {
+	if len(peers) == 0 {
+		return nil, nil
 	}
 
-	// Verify the delete actually happened by checking if the client still exists
-	// This helps catch cases where DeleteClient returns success but doesn't actually delete
-	// Use ListClients instead of GetClient for more reliable verification
-	// Add a small delay to allow Dex to process the delete
-	time.Sleep(200 * time.Millisecond)
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
 
-	listCtx, listCancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer listCancel()
+	listResp, err := cfg.Client.ListClients(listCtx, &api.ListClientReq{})
+	if err != nil {
+		return nil, err
+	}
 
-	listResp, listErr := cfg.Client.ListClients(listCtx, &api.ListClientReq{})
-	if listErr != nil {
-		// Can't verify, but delete call succeeded - return error to be safe
-		// This ensures we don't silently ignore verification failures
-		return infer.DeleteResponse{}, fmt.Errorf("delete reported success but verification failed (ListClients error): %w", listErr)
+	known := make(map[string]bool, len(listResp.Clients)+1)
+	known[clientID] = true
+	for _, cl := range listResp.Clients {
+		known[cl.Id] = true
 	}
 
-	// Check if the client still exists in the list
-	for _, client := range listResp.Clients {
-		if client.Id == deleteID {
-			// Client still exists - the delete didn't work
-			// This is a critical error - the delete call succeeded but the resource still exists
-			return infer.DeleteResponse{}, fmt.Errorf("delete reported success but client %q still exists in Dex (found in ListClients response with %d total clients)", deleteID, len(listResp.Clients))
+	var unknown []string
+	for _, peer := range peers {
+		if peer != "" && !known[peer] {
+			unknown = append(unknown, peer)
 		}
 	}
+	return unknown, nil
+}
 
-	// Client not found in list - delete was successful
-	return infer.DeleteResponse{}, nil
+// generateClientSecret returns a secure random secret (32 bytes = 256 bits, base64 encoded).
+func generateClientSecret() (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(secretBytes), nil
 }
 
 // PtrOrString returns the value pointed to by p, or nil if p is empty or nil.
@@ -0,0 +1,128 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+)
+
+func TestClientTimestamps(t *testing.T) {
+	createdAt := "2026-01-02T03:04:05Z"
+	args := ClientArgs{
+		ClientId:     "app",
+		Name:         "App",
+		RedirectUris: []string{"https://app.example.com/callback"},
+	}
+	secret := "generated"
+	created := ClientState{
+		ClientArgs:   args,
+		SecretResult: &secret,
+		CreatedAt:    &createdAt,
+	}
+	created.Secret = &secret
+	live := &api.Client{
+		Id:           "app",
+		Name:         "App",
+		Secret:       secret,
+		RedirectUris: args.RedirectUris,
+	}
+
+	_, read := clientReadResult(provider.DexConfig{}, live, args, created)
+	if read.CreatedAt == nil || *read.CreatedAt != createdAt {
+		t.Errorf("createdAt after read = %v, want %q", read.CreatedAt, createdAt)
+	}
+	if read.UpdatedAt != nil {
+		t.Errorf("updatedAt after read = %q, want unset before the first update", *read.UpdatedAt)
+	}
+
+	first := updatedClientState(args, read, "2026-02-01T00:00:00Z")
+	second := updatedClientState(args, first, "2026-03-01T00:00:00Z")
+	if second.CreatedAt == nil || *second.CreatedAt != createdAt {
+		t.Errorf("createdAt after update = %v, want %q", second.CreatedAt, createdAt)
+	}
+	if first.UpdatedAt == nil || second.UpdatedAt == nil || *first.UpdatedAt == *second.UpdatedAt {
+		t.Errorf("updatedAt did not change between updates: %v, %v", first.UpdatedAt, second.UpdatedAt)
+	}
+
+	_, reread := clientReadResult(provider.DexConfig{}, live, args, second)
+	if reread.UpdatedAt == nil || *reread.UpdatedAt != *second.UpdatedAt {
+		t.Errorf("updatedAt after read = %v, want %q", reread.UpdatedAt, *second.UpdatedAt)
+	}
+}
+
+func TestClientImportHasNoTimestamps(t *testing.T) {
+	live := &api.Client{Id: "app", Name: "App", Secret: "s"}
+
+	_, state := clientReadResult(provider.DexConfig{}, live, ClientArgs{}, ClientState{})
+	if state.CreatedAt != nil || state.UpdatedAt != nil {
+		t.Errorf("imported client timestamps = %v, %v, want both unset", state.CreatedAt, state.UpdatedAt)
+	}
+}
+
+func TestValidateTrustedPeers(t *testing.T) {
+	cfg := provider.DexConfig{Client: &fakeDex{clients: []*api.Client{{Id: "web"}, {Id: "cli"}}}}
+
+	tests := []struct {
+		name    string
+		peers   []string
+		wantErr string
+	}{
+		{name: "existing peers", peers: []string{"web", "cli"}},
+		{name: "self reference", peers: []string{"app"}},
+		{name: "unknown peer", peers: []string{"web", "mobile"}, wantErr: `trustedPeers for client "app" reference unknown client IDs: mobile`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTrustedPeers(context.Background(), cfg, "app", tt.peers, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateTrustedPeers() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("validateTrustedPeers() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClientUnsetSecretStaysUnset(t *testing.T) {
+	live := &api.Client{
+		Id:           "app",
+		Name:         "App",
+		Secret:       "generated-by-dex",
+		RedirectUris: []string{"https://app.example.com/callback"},
+	}
+	// The program does not set a secret.
+	program := ClientArgs{
+		ClientId:     "app",
+		Name:         "App",
+		RedirectUris: []string{"https://app.example.com/callback"},
+	}
+
+	// Import has neither inputs nor a previous state.
+	inputs, state := clientReadResult(provider.DexConfig{}, live, ClientArgs{}, ClientState{})
+	if inputs.Secret != nil {
+		t.Errorf("imported secret input = %q, want unset", *inputs.Secret)
+	}
+	if state.Secret == nil || *state.Secret != live.Secret {
+		t.Errorf("imported secret state = %v, want the live secret", state.Secret)
+	}
+	olds, news := comparableClientArgs(state.ClientArgs, program)
+	if diff := diffResource("Client", olds, news); diff.HasChanges {
+		t.Errorf("program after import shows changes: %+v", diff.DetailedDiff)
+	}
+
+	// A later refresh keeps the secret out of the inputs.
+	inputs, state = clientReadResult(provider.DexConfig{}, live, program, state)
+	if inputs.Secret != nil {
+		t.Errorf("refreshed secret input = %q, want unset", *inputs.Secret)
+	}
+	olds, news = comparableClientArgs(state.ClientArgs, program)
+	if diff := diffResource("Client", olds, news); diff.HasChanges {
+		t.Errorf("program after refresh shows changes: %+v", diff.DetailedDiff)
+	}
+}
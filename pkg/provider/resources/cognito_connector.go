@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"time"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
@@ -32,11 +31,17 @@ type CognitoOidcConnectorArgs struct {
 	Scopes         []string       `pulumi:"scopes,optional"`
 	UserNameSource *string        `pulumi:"userNameSource,optional"` // "email" | "sub"
 	ExtraOidc      map[string]any `pulumi:"extraOidc,optional"`
+
+	OverrideClaimMapping *bool             `pulumi:"overrideClaimMapping,optional"`
+	ClaimMapping         *OIDCClaimMapping `pulumi:"claimMapping,optional"`
+	TimeoutSeconds       *int              `pulumi:"timeoutSeconds,optional"`
 }
 
 // CognitoOidcConnectorState defines outputs for CognitoOidcConnector.
 type CognitoOidcConnectorState struct {
 	CognitoOidcConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
 }
 
 // CognitoOidcConnector manages an AWS Cognito connector using Dex's generic OIDC connector.
@@ -56,14 +61,19 @@ func (c *CognitoOidcConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.ClientId, "Cognito app client ID.")
 	a.Describe(&c.ClientSecret, "Cognito app client secret.")
 	a.Describe(&c.RedirectUri, "Redirect URI registered in Cognito. Must match Dex's callback URL.")
-	a.Describe(&c.Scopes, "OIDC scopes to request from Cognito. Defaults to ['openid', 'email', 'profile'] if not specified.")
+	a.Describe(&c.Scopes, "OIDC scopes to request from Cognito. Defaults to ['openid', 'email', 'profile'] if not specified. Custom resource-server scopes such as 'https://api.example.com/orders.read' are accepted.")
 	a.Describe(&c.UserNameSource, "Source for the username claim. Valid values: 'email' or 'sub' (subject).")
 	a.Describe(&c.ExtraOidc, "Additional OIDC configuration fields as key-value pairs for advanced scenarios.")
+	a.Describe(&c.OverrideClaimMapping, "If true, Dex uses claimMapping even when the standard claims are present in the ID token.")
+	a.Describe(&c.ClaimMapping, "Mapping of nonstandard upstream claims to Dex user attributes (e.g. Cognito's 'cognito:groups' as groups).")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for CognitoOidcConnectorState.
 func (c *CognitoOidcConnectorState) Annotate(a infer.Annotator) {
 	// CognitoOidcConnectorState embeds CognitoOidcConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
 }
 
 // Check validates inputs.
@@ -73,6 +83,8 @@ func (c *CognitoOidcConnector) Check(ctx context.Context, req infer.CheckRequest
 		return infer.CheckResponse[CognitoOidcConnectorArgs]{Failures: failures}, err
 	}
 
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
 	// Validate region format (basic check)
 	if args.Region != "" {
 		regionRegex := regexp.MustCompile(`^[a-z0-9-]+$`)
@@ -95,10 +107,11 @@ func (c *CognitoOidcConnector) Check(ctx context.Context, req infer.CheckRequest
 		}
 	}
 
-	// Apply defaults
-	if len(args.Scopes) == 0 {
-		args.Scopes = []string{"openid", "email", "profile"}
-	}
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+	failures = append(failures, checkOpenIDScope(ctx, cfg, "scopes", args.Scopes)...)
+
+	applyCognitoOidcDefaults(cfg, &args)
 
 	return infer.CheckResponse[CognitoOidcConnectorArgs]{
 		Inputs:   args,
@@ -106,6 +119,11 @@ func (c *CognitoOidcConnector) Check(ctx context.Context, req infer.CheckRequest
 	}, nil
 }
 
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *CognitoOidcConnector) Diff(ctx context.Context, req infer.DiffRequest[CognitoOidcConnectorArgs, CognitoOidcConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("CognitoOidcConnector", req.State.CognitoOidcConnectorArgs, req.Inputs), nil
+}
+
 // Create creates a new Cognito OIDC connector.
 func (c *CognitoOidcConnector) Create(ctx context.Context, req infer.CreateRequest[CognitoOidcConnectorArgs]) (infer.CreateResponse[CognitoOidcConnectorState], error) {
 	args := req.Inputs
@@ -113,8 +131,12 @@ func (c *CognitoOidcConnector) Create(ctx context.Context, req infer.CreateReque
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[CognitoOidcConnectorState]{}, err
+		}
 		state := CognitoOidcConnectorState{
 			CognitoOidcConnectorArgs: args,
+			CallbackUrl:              dexCallbackURL(ctx),
 		}
 		return infer.CreateResponse[CognitoOidcConnectorState]{
 			ID:     args.ConnectorId,
@@ -124,33 +146,10 @@ func (c *CognitoOidcConnector) Create(ctx context.Context, req infer.CreateReque
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[CognitoOidcConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.CreateResponse[CognitoOidcConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	// Derive issuer from region and userPoolId
-	issuer := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s", args.Region, args.UserPoolId)
-
-	// Derive userNameKey from userNameSource
-	userNameKey := "email" // default
-	if args.UserNameSource != nil {
-		userNameKey = *args.UserNameSource
-	}
-
-	// Build OIDC config
-	oidcConfig := map[string]any{
-		"issuer":       issuer,
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-		"scopes":       args.Scopes,
-		"userNameKey":  userNameKey,
-	}
-
-	for k, v := range args.ExtraOidc {
-		oidcConfig[k] = v
-	}
-
-	configBytes, err := json.Marshal(oidcConfig)
+	configBytes, err := json.Marshal(buildCognitoOidcConfig(args))
 	if err != nil {
 		return infer.CreateResponse[CognitoOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
 	}
@@ -162,7 +161,7 @@ func (c *CognitoOidcConnector) Create(ctx context.Context, req infer.CreateReque
 		Config: configBytes,
 	}
 
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
@@ -173,11 +172,15 @@ func (c *CognitoOidcConnector) Create(ctx context.Context, req infer.CreateReque
 	}
 
 	if resp.AlreadyExists {
-		return infer.CreateResponse[CognitoOidcConnectorState]{}, fmt.Errorf("connector with id %q already exists", args.ConnectorId)
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[CognitoOidcConnectorState]{}, err
+		}
 	}
 
 	state := CognitoOidcConnectorState{
 		CognitoOidcConnectorArgs: args,
+		CallbackUrl:              dexCallbackURL(ctx),
 	}
 
 	return infer.CreateResponse[CognitoOidcConnectorState]{
@@ -190,23 +193,12 @@ func (c *CognitoOidcConnector) Create(ctx context.Context, req infer.CreateReque
 func (c *CognitoOidcConnector) Read(ctx context.Context, req infer.ReadRequest[CognitoOidcConnectorArgs, CognitoOidcConnectorState]) (infer.ReadResponse[CognitoOidcConnectorArgs, CognitoOidcConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[CognitoOidcConnectorArgs, CognitoOidcConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[CognitoOidcConnectorArgs, CognitoOidcConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	listCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[CognitoOidcConnectorArgs, CognitoOidcConnectorState]{}, fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, conn := range listResp.Connectors {
-		if conn.Id == req.ID {
-			found = conn
-			break
-		}
+		return infer.ReadResponse[CognitoOidcConnectorArgs, CognitoOidcConnectorState]{}, err
 	}
 
 	if found == nil {
@@ -214,8 +206,16 @@ func (c *CognitoOidcConnector) Read(ctx context.Context, req infer.ReadRequest[C
 	}
 
 	var configMap map[string]any
-	if err := json.Unmarshal(found.Config, &configMap); err != nil {
-		return infer.ReadResponse[CognitoOidcConnectorArgs, CognitoOidcConnectorState]{}, nil
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildCognitoOidcConfig(req.State.CognitoOidcConnectorArgs))
 	}
 
 	// Extract region and userPoolId from issuer
@@ -233,10 +233,12 @@ func (c *CognitoOidcConnector) Read(ctx context.Context, req infer.ReadRequest[C
 		}
 	}
 
+	// The default userNameKey is reported as unset unless the program set it
+	// explicitly, so it does not diff.
 	userNameKey, _ := configMap["userNameKey"].(string)
 	userNameSource := &userNameKey
-	if userNameKey == "email" {
-		// This is the default
+	if userNameKey == "" || (userNameKey == "email" && req.Inputs.UserNameSource == nil) {
+		userNameSource = nil
 	}
 
 	scopes, _ := configMap["scopes"].([]any)
@@ -253,14 +255,22 @@ func (c *CognitoOidcConnector) Read(ctx context.Context, req infer.ReadRequest[C
 		Region:         region,
 		UserPoolId:     userPoolId,
 		ClientId:       GetString(configMap, "clientID"),
-		ClientSecret:   GetString(configMap, "clientSecret"),
+		ClientSecret:   readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
 		RedirectUri:    GetString(configMap, "redirectURI"),
 		Scopes:         scopesStr,
 		UserNameSource: userNameSource,
+
+		OverrideClaimMapping: GetBoolPtr(configMap, "overrideClaimMapping"),
+		ClaimMapping:         decodeClaimMapping(configMap["claimMapping"]),
+		ExtraOidc:            readExtraOidc(configMap, req.Inputs.ExtraOidc),
 	}
 
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
 	state := CognitoOidcConnectorState{
 		CognitoOidcConnectorArgs: args,
+		CallbackUrl:              dexCallbackURL(ctx),
+		RawConfigOut:             redactedRawConfig(found.Config),
 	}
 
 	return infer.ReadResponse[CognitoOidcConnectorArgs, CognitoOidcConnectorState]{
@@ -278,8 +288,12 @@ func (c *CognitoOidcConnector) Update(ctx context.Context, req infer.UpdateReque
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[CognitoOidcConnectorState]{}, err
+		}
 		state := CognitoOidcConnectorState{
 			CognitoOidcConnectorArgs: args,
+			CallbackUrl:              dexCallbackURL(ctx),
 		}
 		return infer.UpdateResponse[CognitoOidcConnectorState]{
 			Output: state,
@@ -288,41 +302,23 @@ func (c *CognitoOidcConnector) Update(ctx context.Context, req infer.UpdateReque
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[CognitoOidcConnectorState]{}, fmt.Errorf("Dex client not configured")
-	}
-
-	if args.ConnectorId != oldState.ConnectorId {
-		return infer.UpdateResponse[CognitoOidcConnectorState]{}, fmt.Errorf("connectorId cannot be changed")
-	}
-	if args.Region != oldState.Region || args.UserPoolId != oldState.UserPoolId {
-		return infer.UpdateResponse[CognitoOidcConnectorState]{}, fmt.Errorf("region and userPoolId cannot be changed (would require replace)")
-	}
-
-	issuer := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s", args.Region, args.UserPoolId)
-	userNameKey := "email"
-	if args.UserNameSource != nil {
-		userNameKey = *args.UserNameSource
+		return infer.UpdateResponse[CognitoOidcConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	oidcConfig := map[string]any{
-		"issuer":       issuer,
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-		"scopes":       args.Scopes,
-		"userNameKey":  userNameKey,
+	if err := checkImmutableFields("CognitoOidcConnector", oldState.CognitoOidcConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[CognitoOidcConnectorState]{}, err
 	}
 
-	for k, v := range args.ExtraOidc {
-		oidcConfig[k] = v
-	}
-
-	configBytes, err := json.Marshal(oidcConfig)
+	configBytes, err := json.Marshal(buildCognitoOidcConfig(args))
 	if err != nil {
 		return infer.UpdateResponse[CognitoOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
 	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[CognitoOidcConnectorState]{}, err
+	}
 
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
@@ -337,6 +333,7 @@ func (c *CognitoOidcConnector) Update(ctx context.Context, req infer.UpdateReque
 
 	state := CognitoOidcConnectorState{
 		CognitoOidcConnectorArgs: args,
+		CallbackUrl:              dexCallbackURL(ctx),
 	}
 
 	return infer.UpdateResponse[CognitoOidcConnectorState]{
@@ -348,7 +345,7 @@ func (c *CognitoOidcConnector) Update(ctx context.Context, req infer.UpdateReque
 func (c *CognitoOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest[CognitoOidcConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -358,7 +355,7 @@ func (c *CognitoOidcConnector) Delete(ctx context.Context, req infer.DeleteReque
 
 	// Note: Pulumi does not call Delete during preview, so no preview check needed
 
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
@@ -371,5 +368,48 @@ func (c *CognitoOidcConnector) Delete(ctx context.Context, req infer.DeleteReque
 		return infer.DeleteResponse{}, provider.WrapError("delete", "cognito-oidc-connector", deleteID, err)
 	}
 
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
 	return infer.DeleteResponse{}, nil
 }
+
+// applyCognitoOidcDefaults fills in defaults for optional CognitoOidcConnector inputs.
+// Unset scopes default to the provider's defaultOidcScopes, if set.
+func applyCognitoOidcDefaults(cfg provider.DexConfig, args *CognitoOidcConnectorArgs) {
+	args.Scopes = defaultOidcScopes(cfg, args.Scopes, "openid", "email", "profile")
+}
+
+// buildCognitoOidcConfig builds the Dex "oidc" connector config for a Cognito user pool.
+// The issuer is derived from region and userPoolId, and userNameKey from userNameSource.
+func buildCognitoOidcConfig(args CognitoOidcConnectorArgs) map[string]any {
+	issuer := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s", args.Region, args.UserPoolId)
+
+	userNameKey := "email" // default
+	if args.UserNameSource != nil {
+		userNameKey = *args.UserNameSource
+	}
+
+	oidcConfig := map[string]any{
+		"issuer":       issuer,
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"scopes":       args.Scopes,
+		"userNameKey":  userNameKey,
+	}
+
+	if args.OverrideClaimMapping != nil {
+		oidcConfig["overrideClaimMapping"] = *args.OverrideClaimMapping
+	}
+	if cm := claimMappingConfig(args.ClaimMapping); cm != nil {
+		oidcConfig["claimMapping"] = cm
+	}
+
+	for k, v := range args.ExtraOidc {
+		oidcConfig[k] = v
+	}
+
+	return oidcConfig
+}
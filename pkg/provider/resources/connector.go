@@ -1,13 +1,16 @@
 package resources
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
+	"reflect"
+	"sort"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
 	"github.com/pulumi/pulumi-go-provider/infer"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -15,16 +18,20 @@ import (
 
 // ConnectorArgs defines the inputs for a dex.Connector resource.
 type ConnectorArgs struct {
-	ConnectorId string      `pulumi:"connectorId"`
-	Type        string      `pulumi:"type"`
-	Name        string      `pulumi:"name"`
-	OIDCConfig  *OIDCConfig `pulumi:"oidcConfig,optional"`
-	RawConfig   *string     `pulumi:"rawConfig,optional"`
+	ConnectorId    string      `pulumi:"connectorId"`
+	Type           string      `pulumi:"type"`
+	Name           string      `pulumi:"name"`
+	OIDCConfig     *OIDCConfig `pulumi:"oidcConfig,optional"`
+	RawConfig      *string     `pulumi:"rawConfig,optional"`
+	Enabled        *bool       `pulumi:"enabled,optional"`
+	TimeoutSeconds *int        `pulumi:"timeoutSeconds,optional"`
 }
 
 // ConnectorState defines the outputs/state for a dex.Connector resource.
 type ConnectorState struct {
 	ConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
 }
 
 // OIDCConfig mirrors Dex's OIDC connector JSON configuration.
@@ -34,19 +41,67 @@ type OIDCConfig struct {
 	Issuer                    string            `pulumi:"issuer" json:"issuer"`
 	ClientId                  string            `pulumi:"clientId" json:"clientId"` // Match pulumi tag for decoder
 	ClientSecret              string            `pulumi:"clientSecret" json:"clientSecret" provider:"secret"`
-	RedirectUri               string            `pulumi:"redirectUri" json:"redirectUri"` // Match pulumi tag for decoder
+	RedirectUri               string            `pulumi:"redirectUri,optional" json:"redirectUri"` // Match pulumi tag for decoder
 	Scopes                    []string          `pulumi:"scopes,optional" json:"scopes,omitempty"`
 	InsecureSkipEmailVerified *bool             `pulumi:"insecureSkipEmailVerified,optional" json:"insecureSkipEmailVerified,omitempty"`
 	InsecureIssuer            *bool             `pulumi:"insecureIssuer,optional" json:"insecureIssuer,omitempty"`
+	InsecureEnableGroups      *bool             `pulumi:"insecureEnableGroups,optional" json:"insecureEnableGroups,omitempty"`
+	OverrideClaimMapping      *bool             `pulumi:"overrideClaimMapping,optional" json:"overrideClaimMapping,omitempty"`
+	GetUserInfo               *bool             `pulumi:"getUserInfo,optional" json:"getUserInfo,omitempty"`
+	PromptType                *string           `pulumi:"promptType,optional" json:"promptType,omitempty"`
+	AcrValues                 []string          `pulumi:"acrValues,optional" json:"acrValues,omitempty"`
+	BasicAuthUnsupported      *bool             `pulumi:"basicAuthUnsupported,optional" json:"basicAuthUnsupported,omitempty"`
 	UserNameKey               *string           `pulumi:"userNameKey,optional" json:"userNameKey,omitempty"`
 	ClaimMapping              *OIDCClaimMapping `pulumi:"claimMapping,optional" json:"claimMapping,omitempty"`
 	Extra                     map[string]any    `pulumi:"extra,optional" json:"-"`
 }
 
 // OIDCClaimMapping represents claim mapping configuration.
+// JSON tags match the keys of Dex's claimMapping object.
 type OIDCClaimMapping struct {
-	EmailKey  *string `pulumi:"emailKey,optional" json:"email,omitempty"`
-	GroupsKey *string `pulumi:"groupsKey,optional" json:"groups,omitempty"`
+	EmailKey             *string `pulumi:"emailKey,optional" json:"email,omitempty"`
+	GroupsKey            *string `pulumi:"groupsKey,optional" json:"groups,omitempty"`
+	PreferredUsernameKey *string `pulumi:"preferredUsernameKey,optional" json:"preferred_username,omitempty"`
+	NameKey              *string `pulumi:"nameKey,optional" json:"name,omitempty"`
+}
+
+// claimMappingConfig converts an OIDCClaimMapping into Dex's claimMapping object.
+// It returns nil when no keys are set, so the key can be omitted entirely.
+func claimMappingConfig(cm *OIDCClaimMapping) map[string]any {
+	if cm == nil {
+		return nil
+	}
+	out := map[string]any{}
+	if cm.EmailKey != nil {
+		out["email"] = *cm.EmailKey
+	}
+	if cm.GroupsKey != nil {
+		out["groups"] = *cm.GroupsKey
+	}
+	if cm.PreferredUsernameKey != nil {
+		out["preferred_username"] = *cm.PreferredUsernameKey
+	}
+	if cm.NameKey != nil {
+		out["name"] = *cm.NameKey
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// decodeClaimMapping converts Dex's claimMapping object back into an OIDCClaimMapping.
+func decodeClaimMapping(v any) *OIDCClaimMapping {
+	m, ok := v.(map[string]any)
+	if !ok || len(m) == 0 {
+		return nil
+	}
+	return &OIDCClaimMapping{
+		EmailKey:             GetStringPtr(m, "email"),
+		GroupsKey:            GetStringPtr(m, "groups"),
+		PreferredUsernameKey: GetStringPtr(m, "preferred_username"),
+		NameKey:              GetStringPtr(m, "name"),
+	}
 }
 
 // Connector represents a Dex connector resource (generic).
@@ -54,7 +109,8 @@ type Connector struct{}
 
 // Annotate provides schema metadata for the Connector resource.
 func (c *Connector) Annotate(a infer.Annotator) {
-	a.Describe(c, "Manages a generic connector (upstream identity provider) in Dex. Use this resource for connectors not covered by specific connector types, or when you need full control over the connector configuration.")
+	a.Describe(c, "Manages a generic connector (upstream identity provider) in Dex. Use this resource for connectors not covered by specific connector types, or when you need full control over the connector configuration. "+
+		"Dex has no setting to disable a connector, so a disabled Connector is removed from Dex and created again when re-enabled.")
 }
 
 // Annotate provides schema metadata for ConnectorArgs.
@@ -64,6 +120,8 @@ func (c *ConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
 	a.Describe(&c.OIDCConfig, "OIDC-specific configuration. Use this for OIDC-based connectors.")
 	a.Describe(&c.RawConfig, "Raw JSON configuration for the connector. Use this for advanced configurations or connector types not directly supported. If provided, this takes precedence over OIDCConfig.")
+	a.Describe(&c.Enabled, "Whether the connector exists in Dex. Defaults to true. When false, the connector is deleted from Dex but its configuration stays managed, and setting it back to true recreates it. Only supported on dex.Connector itself.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for OIDCConfig.
@@ -71,10 +129,16 @@ func (c *OIDCConfig) Annotate(a infer.Annotator) {
 	a.Describe(&c.Issuer, "The OIDC issuer URL (e.g., 'https://accounts.google.com').")
 	a.Describe(&c.ClientId, "The OIDC client ID.")
 	a.Describe(&c.ClientSecret, "The OIDC client secret.")
-	a.Describe(&c.RedirectUri, "The redirect URI registered with the OIDC provider. Must match Dex's callback URL.")
-	a.Describe(&c.Scopes, "List of OIDC scopes to request (e.g., 'openid', 'profile', 'email'). Defaults to ['openid', 'profile', 'email'] if not specified.")
+	a.Describe(&c.RedirectUri, "The redirect URI registered with the OIDC provider. Must match Dex's callback URL. Required, except in a ConnectorSet whose defaults set it.")
+	a.Describe(&c.Scopes, "List of OIDC scopes to request (e.g., 'openid', 'profile', 'email'). Defaults to ['openid', 'profile', 'email'] if not specified. Each scope may contain any printable ASCII character except space, double quote, and backslash (RFC 6749).")
 	a.Describe(&c.InsecureSkipEmailVerified, "If true, skip verification of the 'email_verified' claim. Not recommended for production.")
 	a.Describe(&c.InsecureIssuer, "If true, skip verification of the issuer URL. Not recommended for production.")
+	a.Describe(&c.InsecureEnableGroups, "If true, request the groups claim from the upstream provider and pass it through. Dex calls this insecure because upstream groups are trusted as is.")
+	a.Describe(&c.OverrideClaimMapping, "If true, claimMapping takes precedence over the standard OIDC claims instead of only filling in missing ones.")
+	a.Describe(&c.GetUserInfo, "If true, fetch additional claims from the provider's UserInfo endpoint after login.")
+	a.Describe(&c.PromptType, "OAuth prompt parameter sent to the provider. Valid values: 'consent' (Dex's default), 'login', 'select_account', 'none', or '' to send no prompt.")
+	a.Describe(&c.AcrValues, "Authentication Context Class Reference values requested from the provider.")
+	a.Describe(&c.BasicAuthUnsupported, "If true, send the client secret in the request body instead of HTTP basic auth, for providers that do not support the latter.")
 	a.Describe(&c.UserNameKey, "The claim key to use as the username (e.g., 'preferred_username', 'email', 'sub').")
 	a.Describe(&c.ClaimMapping, "Mapping of OIDC claims to Dex user attributes.")
 	a.Describe(&c.Extra, "Additional OIDC configuration fields as key-value pairs.")
@@ -84,11 +148,79 @@ func (c *OIDCConfig) Annotate(a infer.Annotator) {
 func (c *OIDCClaimMapping) Annotate(a infer.Annotator) {
 	a.Describe(&c.EmailKey, "The OIDC claim key that contains the user's email address.")
 	a.Describe(&c.GroupsKey, "The OIDC claim key that contains the user's group memberships.")
+	a.Describe(&c.PreferredUsernameKey, "The OIDC claim key that contains the user's preferred username.")
+	a.Describe(&c.NameKey, "The OIDC claim key that contains the user's display name.")
 }
 
 // Annotate provides schema metadata for ConnectorState.
 func (c *ConnectorState) Annotate(a infer.Annotator) {
 	// ConnectorState embeds ConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// Check validates inputs. camelCase keys in rawConfig that Dex spells
+// differently are rejected, or rewritten when the provider's
+// normalizeRawConfigKeys flag is set.
+func (c *Connector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[ConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[ConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[ConnectorArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	// Unknown types are only warned about, since the registry may lag behind Dex.
+	if types, source := provider.SupportedConnectorTypes(ctx, cfg.Client); args.Type != "" && !provider.IsSupportedConnectorType(types, args.Type) {
+		failures = append(failures, checkWarning(ctx, cfg, "type",
+			fmt.Sprintf("connector %q has type %q, which is not in the %s list of supported connector types", args.ConnectorId, args.Type, source))...)
+	}
+
+	if args.RawConfig != nil && *args.RawConfig != "" {
+		normalized, keyFailures := checkRawConfigKeyCase(*args.RawConfig, provider.PtrOr(cfg.NormalizeRawConfigKeys, false))
+		failures = append(failures, keyFailures...)
+		args.RawConfig = &normalized
+	}
+
+	failures = append(failures, checkConnectorReferences(ctx, cfg, args)...)
+	failures = append(failures, checkRawConfigUsernamePrompt(args)...)
+	if args.OIDCConfig != nil {
+		if args.OIDCConfig.RedirectUri == "" {
+			failures = append(failures, p.CheckFailure{Property: "oidcConfig.redirectUri", Reason: "redirectUri is required"})
+		}
+		failures = append(failures, validateRedirectURI(ctx, cfg, "oidcConfig.redirectUri", args.OIDCConfig.RedirectUri)...)
+		failures = append(failures, checkOpenIDScope(ctx, cfg, "oidcConfig.scopes", args.OIDCConfig.Scopes)...)
+		if pt := args.OIDCConfig.PromptType; pt != nil {
+			switch *pt {
+			case "consent", "login", "select_account", "none", "":
+				// An empty promptType makes Dex send no prompt parameter.
+			default:
+				failures = append(failures, p.CheckFailure{
+					Property: "oidcConfig.promptType",
+					Reason:   fmt.Sprintf("must be one of: consent, login, select_account, none, or empty; got %q", *pt),
+				})
+			}
+		}
+	}
+
+	// Invalid configs are reported by Create; only size-check what would be sent.
+	if configBytes, err := buildConnectorConfigBytes(args); err == nil {
+		property := "rawConfig"
+		if args.OIDCConfig != nil {
+			property = "oidcConfig"
+		}
+		failures = append(failures, checkConfigSize(ctx, cfg, property, configBytes)...)
+	}
+
+	if args.Enabled == nil {
+		defaultEnabled := true
+		args.Enabled = &defaultEnabled
+	}
+
+	return infer.CheckResponse[ConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
 }
 
 // Create creates a new connector in Dex.
@@ -98,8 +230,12 @@ func (c *Connector) Create(ctx context.Context, req infer.CreateRequest[Connecto
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[ConnectorState]{}, err
+		}
 		state := ConnectorState{
 			ConnectorArgs: args,
+			CallbackUrl:   dexCallbackURL(ctx),
 		}
 		return infer.CreateResponse[ConnectorState]{
 			ID:     args.ConnectorId,
@@ -109,7 +245,7 @@ func (c *Connector) Create(ctx context.Context, req infer.CreateRequest[Connecto
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[ConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.CreateResponse[ConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
 	if err := validateConnectorArgs(args); err != nil {
@@ -121,6 +257,19 @@ func (c *Connector) Create(ctx context.Context, req infer.CreateRequest[Connecto
 		return infer.CreateResponse[ConnectorState]{}, err
 	}
 
+	state := ConnectorState{
+		ConnectorArgs: args,
+		CallbackUrl:   dexCallbackURL(ctx),
+	}
+
+	// A disabled connector is tracked in state only.
+	if !provider.PtrOr(args.Enabled, true) {
+		return infer.CreateResponse[ConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
 	conn := &api.Connector{
 		Id:     args.ConnectorId,
 		Type:   args.Type,
@@ -128,7 +277,7 @@ func (c *Connector) Create(ctx context.Context, req infer.CreateRequest[Connecto
 		Config: configBytes,
 	}
 
-	callCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateConnector(callCtx, &api.CreateConnectorReq{
@@ -139,46 +288,10 @@ func (c *Connector) Create(ctx context.Context, req infer.CreateRequest[Connecto
 	}
 
 	if resp.AlreadyExists {
-		// Resource already exists - read it and return it so Pulumi can track it
-		// This allows destroy to work properly even if the resource was created outside Pulumi
-		readCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-		defer cancel()
-
-		listResp, err := cfg.Client.ListConnectors(readCtx, &api.ListConnectorReq{})
-		if err != nil {
-			return infer.CreateResponse[ConnectorState]{}, fmt.Errorf("connector already exists but failed to list connectors: %w", err)
-		}
-
-		var found *api.Connector
-		for _, con := range listResp.Connectors {
-			if con.Id == args.ConnectorId {
-				found = con
-				break
-			}
-		}
-
-		if found == nil {
-			return infer.CreateResponse[ConnectorState]{}, fmt.Errorf("connector already exists but not found in list")
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, conn, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[ConnectorState]{}, err
 		}
-
-		// Decode the existing connector
-		existingArgs, _, err := decodeConnector(found)
-		if err != nil {
-			return infer.CreateResponse[ConnectorState]{}, fmt.Errorf("failed to decode existing connector: %w", err)
-		}
-
-		state := ConnectorState{
-			ConnectorArgs: existingArgs,
-		}
-
-		return infer.CreateResponse[ConnectorState]{
-			ID:     args.ConnectorId,
-			Output: state,
-		}, nil
-	}
-
-	state := ConnectorState{
-		ConnectorArgs: args,
 	}
 
 	return infer.CreateResponse[ConnectorState]{
@@ -191,35 +304,82 @@ func (c *Connector) Create(ctx context.Context, req infer.CreateRequest[Connecto
 func (c *Connector) Read(ctx context.Context, req infer.ReadRequest[ConnectorArgs, ConnectorState]) (infer.ReadResponse[ConnectorArgs, ConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[ConnectorArgs, ConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[ConnectorArgs, ConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	// Dex API doesn't expose GetConnector; we list and filter by ID.
-	callCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(callCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[ConnectorArgs, ConnectorState]{}, fmt.Errorf("failed to list Dex connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, con := range listResp.Connectors {
-		if con.Id == req.ID {
-			found = con
-			break
-		}
+		return infer.ReadResponse[ConnectorArgs, ConnectorState]{}, err
 	}
 
 	if found == nil {
+		// A disabled connector is expected to be absent and is still managed.
+		if req.State.ConnectorId != "" && !provider.PtrOr(req.State.Enabled, true) {
+			return infer.ReadResponse[ConnectorArgs, ConnectorState]{
+				ID:     req.ID,
+				Inputs: req.Inputs,
+				State:  req.State,
+			}, nil
+		}
 		// Connector not found => resource should be deleted.
 		return infer.ReadResponse[ConnectorArgs, ConnectorState]{}, nil
 	}
 
-	args, state, err := decodeConnector(found)
+	decoded := found
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		// Keys in ignoreConfigKeys are not managed here; read them back as
+		// previously applied so external changes do not show up as a diff.
+		decoded = &api.Connector{
+			Id:     found.Id,
+			Type:   found.Type,
+			Name:   found.Name,
+			Config: restoreIgnoredConnectorConfig(cfg.IgnoreConfigKeys, found.Config, req.State.ConnectorArgs),
+		}
+	}
+
+	args, state, err := decodeConnector(decoded)
 	if err != nil {
 		return infer.ReadResponse[ConnectorArgs, ConnectorState]{}, err
 	}
+	if req.Inputs.OIDCConfig != nil && args.OIDCConfig != nil {
+		// Update keeps keys set outside Pulumi (see preserveUnmanagedConfigKeys),
+		// so they are not drift; only extra keys the program sets are read back.
+		for k := range args.OIDCConfig.Extra {
+			if _, ok := req.Inputs.OIDCConfig.Extra[k]; !ok {
+				delete(args.OIDCConfig.Extra, k)
+			}
+		}
+		if len(args.OIDCConfig.Extra) == 0 {
+			args.OIDCConfig.Extra = nil
+		}
+		if req.State.OIDCConfig != nil {
+			args.OIDCConfig.ClientSecret = readSecret(cfg, args.OIDCConfig.ClientSecret, req.State.OIDCConfig.ClientSecret, req.Inputs.OIDCConfig.ClientSecret)
+			if state.OIDCConfig != nil {
+				state.OIDCConfig.ClientSecret = args.OIDCConfig.ClientSecret
+			}
+		}
+	}
+	if args.RawConfig != nil && req.State.RawConfig != nil && provider.PtrOr(cfg.WriteOnlySecrets, false) {
+		var live, applied map[string]any
+		if json.Unmarshal([]byte(*args.RawConfig), &live) == nil && json.Unmarshal([]byte(*req.State.RawConfig), &applied) == nil {
+			restoreSecretConfigKeys(cfg, live, applied)
+			if data, err := json.Marshal(live); err == nil {
+				if normalized, err := normalizeConfigJSON(data); err == nil {
+					rc := string(normalized)
+					args.RawConfig = &rc
+					state.RawConfig = &rc
+				}
+			}
+		}
+	}
+	// The connector exists in Dex, so it is enabled whatever state says.
+	enabled := true
+	args.Enabled = &enabled
+	state.Enabled = &enabled
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+	state.TimeoutSeconds = req.Inputs.TimeoutSeconds
+	state.RawConfigOut = redactedRawConfig(found.Config)
+	state.CallbackUrl = dexCallbackURL(ctx)
 
 	return infer.ReadResponse[ConnectorArgs, ConnectorState]{
 		ID:     found.Id,
@@ -228,6 +388,51 @@ func (c *Connector) Read(ctx context.Context, req infer.ReadRequest[ConnectorArg
 	}, nil
 }
 
+// Diff compares the effective Dex config rather than the raw inputs, so that
+// switching between rawConfig and oidcConfig with semantically equal content
+// (e.g. migrating from raw to typed config) does not produce an update.
+func (c *Connector) Diff(ctx context.Context, req infer.DiffRequest[ConnectorArgs, ConnectorState]) (infer.DiffResponse, error) {
+	return diffConnector(req.State.ConnectorArgs, req.Inputs), nil
+}
+
+// diffConnector computes Connector's diff between the previous and the new
+// inputs.
+func diffConnector(olds, news ConnectorArgs) infer.DiffResponse {
+	diff := map[string]p.PropertyDiff{}
+
+	if olds.ConnectorId != news.ConnectorId {
+		diff["connectorId"] = p.PropertyDiff{Kind: fieldDiffKind("Connector", "connectorId")}
+	}
+	if olds.Type != news.Type {
+		diff["type"] = p.PropertyDiff{Kind: fieldDiffKind("Connector", "type")}
+	}
+	if olds.Name != news.Name {
+		diff["name"] = p.PropertyDiff{Kind: fieldDiffKind("Connector", "name")}
+	}
+	if provider.PtrOr(olds.Enabled, true) != provider.PtrOr(news.Enabled, true) {
+		diff["enabled"] = p.PropertyDiff{Kind: p.Update}
+	}
+	if !reflect.DeepEqual(olds.TimeoutSeconds, news.TimeoutSeconds) {
+		diff["timeoutSeconds"] = p.PropertyDiff{Kind: p.Update}
+	}
+	// Ignored keys are not stripped here: Read already carries their previous
+	// values over, so only a change in the program shows up.
+	if !connectorConfigsEqual(olds, news) {
+		if news.OIDCConfig != nil {
+			diff["oidcConfig"] = p.PropertyDiff{Kind: p.Update}
+		} else {
+			diff["rawConfig"] = p.PropertyDiff{Kind: p.Update}
+		}
+	}
+
+	// Dex rejects a second connector with the same ID, so replace must delete first.
+	return infer.DiffResponse{
+		DeleteBeforeReplace: hasReplacement(diff),
+		HasChanges:          len(diff) > 0,
+		DetailedDiff:        diff,
+	}
+}
+
 // Update updates an existing connector in Dex.
 func (c *Connector) Update(ctx context.Context, req infer.UpdateRequest[ConnectorArgs, ConnectorState]) (infer.UpdateResponse[ConnectorState], error) {
 	args := req.Inputs
@@ -236,29 +441,72 @@ func (c *Connector) Update(ctx context.Context, req infer.UpdateRequest[Connecto
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	// This check MUST be first, before any other operations or config checks
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[ConnectorState]{}, err
+		}
 		state := ConnectorState{
 			ConnectorArgs: args,
+			CallbackUrl:   dexCallbackURL(ctx),
 		}
 		return infer.UpdateResponse[ConnectorState]{Output: state}, nil
 	}
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[ConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.UpdateResponse[ConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	if args.ConnectorId != old.ConnectorId {
-		return infer.UpdateResponse[ConnectorState]{}, fmt.Errorf("connectorId cannot be changed (was %q, got %q)", old.ConnectorId, args.ConnectorId)
+	if err := checkImmutableFields("Connector", old.ConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[ConnectorState]{}, err
 	}
 
 	if err := validateConnectorArgs(args); err != nil {
 		return infer.UpdateResponse[ConnectorState]{}, err
 	}
 
+	state := ConnectorState{
+		ConnectorArgs: args,
+		CallbackUrl:   dexCallbackURL(ctx),
+	}
+
+	wasEnabled := provider.PtrOr(old.Enabled, true)
+	enabled := provider.PtrOr(args.Enabled, true)
+	switch {
+	case !enabled && wasEnabled:
+		if err := deleteConnector(ctx, cfg, args.ConnectorId, args.TimeoutSeconds); err != nil {
+			return infer.UpdateResponse[ConnectorState]{}, err
+		}
+		return infer.UpdateResponse[ConnectorState]{Output: state}, nil
+	case !enabled:
+		// Still disabled; the new configuration is applied when re-enabled.
+		return infer.UpdateResponse[ConnectorState]{Output: state}, nil
+	case !wasEnabled:
+		if err := createConnector(ctx, cfg, args); err != nil {
+			return infer.UpdateResponse[ConnectorState]{}, err
+		}
+		return infer.UpdateResponse[ConnectorState]{Output: state}, nil
+	}
+
 	configBytes, err := buildConnectorConfigBytes(args)
 	if err != nil {
 		return infer.UpdateResponse[ConnectorState]{}, err
 	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[ConnectorState]{}, err
+	}
+	if args.OIDCConfig != nil {
+		// Only typed fields and extra are built from oidcConfig; keep keys
+		// set in Dex by other means rather than wiping them.
+		current, err := findConnectorById(ctx, cfg, args.ConnectorId, args.TimeoutSeconds)
+		if err != nil {
+			return infer.UpdateResponse[ConnectorState]{}, err
+		}
+		if current != nil {
+			previous, _ := buildConnectorConfigBytes(old.ConnectorArgs)
+			configBytes = preserveUnmanagedConfigKeys(configBytes, current.Config, previous)
+		}
+	}
 
 	updateReq := &api.UpdateConnectorReq{
 		Id:        args.ConnectorId,
@@ -267,7 +515,7 @@ func (c *Connector) Update(ctx context.Context, req infer.UpdateRequest[Connecto
 		NewConfig: configBytes,
 	}
 
-	callCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	callCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err = cfg.Client.UpdateConnector(callCtx, updateReq)
@@ -275,10 +523,6 @@ func (c *Connector) Update(ctx context.Context, req infer.UpdateRequest[Connecto
 		return infer.UpdateResponse[ConnectorState]{}, provider.WrapError("update", "connector", args.ConnectorId, err)
 	}
 
-	state := ConnectorState{
-		ConnectorArgs: args,
-	}
-
 	return infer.UpdateResponse[ConnectorState]{Output: state}, nil
 }
 
@@ -286,7 +530,7 @@ func (c *Connector) Update(ctx context.Context, req infer.UpdateRequest[Connecto
 func (c *Connector) Delete(ctx context.Context, req infer.DeleteRequest[ConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -299,21 +543,62 @@ func (c *Connector) Delete(ctx context.Context, req infer.DeleteRequest[Connecto
 
 	// Note: Pulumi does not call Delete during preview, so no preview check needed
 
-	callCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	// A disabled connector was already removed from Dex.
+	if !provider.PtrOr(req.State.Enabled, true) {
+		return infer.DeleteResponse{}, nil
+	}
+
+	return infer.DeleteResponse{}, deleteConnector(ctx, cfg, deleteID, req.State.TimeoutSeconds)
+}
+
+// createConnector creates the connector described by args in Dex, as when a
+// disabled connector is enabled again. An AlreadyExists response is accepted
+// when the existing connector matches.
+func createConnector(ctx context.Context, cfg provider.DexConfig, args ConnectorArgs) error {
+	configBytes, err := buildConnectorConfigBytes(args)
+	if err != nil {
+		return err
+	}
+
+	conn := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   args.Type,
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
-	_, err := cfg.Client.DeleteConnector(callCtx, &api.DeleteConnectorReq{
-		Id: deleteID,
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: conn,
+	})
+	if err != nil {
+		return provider.WrapError("create", "connector", args.ConnectorId, err)
+	}
+	if resp.AlreadyExists {
+		return matchExistingConnector(ctx, cfg, conn, args.TimeoutSeconds)
+	}
+	return nil
+}
+
+// deleteConnector deletes the connector with the given ID from Dex.
+// A connector that is already gone is not an error.
+func deleteConnector(ctx context.Context, cfg provider.DexConfig, id string, timeoutSeconds *int) error {
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: id,
 	})
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
 			// Already deleted; treat as success.
-			return infer.DeleteResponse{}, nil
+			return nil
 		}
-		return infer.DeleteResponse{}, provider.WrapError("delete", "connector", deleteID, err)
+		return provider.WrapError("delete", "connector", id, err)
 	}
-
-	return infer.DeleteResponse{}, nil
+	return verifyConnectorDeleted(ctx, cfg, id, timeoutSeconds)
 }
 
 // validateConnectorArgs enforces high-level invariants for connectors.
@@ -336,9 +621,51 @@ func validateConnectorArgs(args ConnectorArgs) error {
 	if args.Type != "oidc" && oidcSet {
 		return fmt.Errorf("oidcConfig is only valid when type == \"oidc\"")
 	}
+	if oidcSet && args.OIDCConfig.RedirectUri == "" {
+		return fmt.Errorf("oidcConfig.redirectUri is required")
+	}
+	if oidcSet {
+		if failures := validateScopes("oidcConfig.scopes", args.OIDCConfig.Scopes); len(failures) > 0 {
+			return fmt.Errorf("%s: %s", failures[0].Property, failures[0].Reason)
+		}
+	}
 	return nil
 }
 
+// preserveUnmanagedConfigKeys copies into desired the keys of stored, the
+// config currently in Dex, that neither desired nor previous has. previous is
+// the config built from the prior inputs, so keys the program used to set and
+// has since removed are still dropped, while keys set outside Pulumi survive.
+// desired is returned unchanged if any of the configs is not a JSON object.
+func preserveUnmanagedConfigKeys(desired, stored, previous []byte) []byte {
+	var want, have, prev map[string]any
+	if json.Unmarshal(desired, &want) != nil || json.Unmarshal(trimConfigBytes(stored), &have) != nil {
+		return desired
+	}
+	if len(previous) > 0 && json.Unmarshal(previous, &prev) != nil {
+		return desired
+	}
+	added := false
+	for k, v := range have {
+		if _, ok := want[k]; ok {
+			continue
+		}
+		if _, ok := prev[k]; ok {
+			continue
+		}
+		want[k] = v
+		added = true
+	}
+	if !added {
+		return desired
+	}
+	out, err := json.Marshal(want)
+	if err != nil {
+		return desired
+	}
+	return out
+}
+
 // buildConnectorConfigBytes produces the JSON config bytes to send to Dex.
 func buildConnectorConfigBytes(args ConnectorArgs) ([]byte, error) {
 	if args.OIDCConfig != nil {
@@ -372,11 +699,64 @@ func buildConnectorConfigBytes(args ConnectorArgs) ([]byte, error) {
 	}
 
 	// Raw JSON path.
-	var raw json.RawMessage
-	if err := json.Unmarshal([]byte(provider.PtrOr(args.RawConfig, "")), &raw); err != nil {
+	out, err := normalizeConfigJSON([]byte(provider.PtrOr(args.RawConfig, "")))
+	if err != nil {
 		return nil, fmt.Errorf("rawConfig must be valid JSON: %w", err)
 	}
-	return []byte(provider.PtrOr(args.RawConfig, "")), nil
+	return out, nil
+}
+
+// normalizeConfigJSON re-encodes a JSON document with sorted keys and no
+// insignificant whitespace, so configs that differ only in formatting or key
+// order produce identical bytes. Numbers are kept verbatim rather than going
+// through float64.
+func normalizeConfigJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return json.Marshal(v)
+}
+
+// canonicalConnectorConfig returns the effective Dex config for args decoded into
+// a generic JSON value, so that key order and formatting no longer matter.
+// Server-added defaults and the given ignored keys are dropped.
+func canonicalConnectorConfig(args ConnectorArgs, ignoreKeys ...string) (any, error) {
+	configBytes, err := buildConnectorConfigBytes(args)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(configBytes, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode connector config: %w", err)
+	}
+	if m, ok := out.(map[string]any); ok {
+		provider.StripServerDefaults(args.Type, m)
+		for _, key := range ignoreKeys {
+			delete(m, key)
+		}
+	}
+	return out, nil
+}
+
+// connectorConfigsEqual reports whether two sets of connector args produce the
+// same effective Dex config, regardless of whether it came from rawConfig or
+// oidcConfig. Configs that cannot be built are treated as different.
+func connectorConfigsEqual(a, b ConnectorArgs) bool {
+	ca, err := canonicalConnectorConfig(a)
+	if err != nil {
+		return false
+	}
+	cb, err := canonicalConnectorConfig(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(ca, cb)
 }
 
 // decodeConnector converts a Dex Connector into ConnectorArgs/State.
@@ -386,11 +766,12 @@ func decodeConnector(con *api.Connector) (ConnectorArgs, ConnectorState, error)
 		Type:        con.Type,
 		Name:        con.Name,
 	}
+	config := stripConnectorDefaults(con.Type, trimConfigBytes(con.Config))
 
 	// Try to parse as OIDC config when type == "oidc".
-	if con.Type == "oidc" && len(con.Config) > 0 {
+	if con.Type == "oidc" && len(config) > 0 {
 		var base map[string]any
-		if err := json.Unmarshal(con.Config, &base); err == nil {
+		if err := json.Unmarshal(config, &base); err == nil {
 			// Attempt to map known fields into OIDCConfig.
 			oidc := &OIDCConfig{}
 			// Marshal back into JSON and unmarshal into typed struct for known fields.
@@ -415,7 +796,15 @@ func decodeConnector(con *api.Connector) (ConnectorArgs, ConnectorState, error)
 			delete(base, "scopes")
 			delete(base, "insecureSkipEmailVerified")
 			delete(base, "insecureIssuer")
+			delete(base, "insecureEnableGroups")
+			delete(base, "overrideClaimMapping")
+			delete(base, "getUserInfo")
+			delete(base, "promptType")
+			delete(base, "acrValues")
+			delete(base, "basicAuthUnsupported")
 			delete(base, "userNameKey")
+			// claimMapping (including preferred_username/name) is decoded into
+			// OIDCClaimMapping above, so none of its keys belong in Extra.
 			delete(base, "claimMapping")
 
 			if len(base) > 0 {
@@ -425,11 +814,16 @@ func decodeConnector(con *api.Connector) (ConnectorArgs, ConnectorState, error)
 			args.OIDCConfig = oidc
 		} else {
 			// Fall back to rawConfig if JSON parsing fails.
-			rc := string(con.Config)
+			rc := string(config)
 			args.RawConfig = &rc
 		}
-	} else if len(con.Config) > 0 {
-		rc := string(con.Config)
+	} else if len(config) > 0 {
+		// Normalize so the refreshed rawConfig matches what
+		// buildConnectorConfigBytes produces for semantically equal input.
+		if normalized, err := normalizeConfigJSON(config); err == nil {
+			config = normalized
+		}
+		rc := string(config)
 		args.RawConfig = &rc
 	}
 
@@ -438,3 +832,146 @@ func decodeConnector(con *api.Connector) (ConnectorArgs, ConnectorState, error)
 	}
 	return args, state, nil
 }
+
+// dexConfigKeyCase maps commonly mistyped camelCase config keys to the casing
+// Dex expects. Dex silently ignores the mistyped form.
+var dexConfigKeyCase = map[string]string{
+	"clientId":     "clientID",
+	"redirectUri":  "redirectURI",
+	"redirectUrl":  "redirectURI",
+	"redirectURL":  "redirectURI",
+	"rootCa":       "rootCA",
+	"rootCas":      "rootCAs",
+	"bindPw":       "bindPW",
+	"baseUrl":      "baseURL",
+	"useLoginAsId": "useLoginAsID",
+}
+
+// checkRawConfigKeyCase looks for top-level rawConfig keys listed in
+// dexConfigKeyCase. With normalize set they are renamed and the rewritten
+// config is returned; otherwise each one is reported as a failure and raw is
+// returned unchanged. Configs that are not JSON objects are left to Create's
+// validation.
+func checkRawConfigKeyCase(raw string, normalize bool) (string, []p.CheckFailure) {
+	var config map[string]any
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		return raw, nil
+	}
+
+	var miscased []string
+	for key := range config {
+		if _, ok := dexConfigKeyCase[key]; ok {
+			miscased = append(miscased, key)
+		}
+	}
+	if len(miscased) == 0 {
+		return raw, nil
+	}
+	sort.Strings(miscased)
+
+	var failures []p.CheckFailure
+	for _, key := range miscased {
+		want := dexConfigKeyCase[key]
+		if !normalize {
+			failures = append(failures, p.CheckFailure{
+				Property: "rawConfig",
+				Reason:   fmt.Sprintf("key %q is not recognized by Dex; use %q (or set the provider's normalizeRawConfigKeys to rewrite it)", key, want),
+			})
+			continue
+		}
+		if _, ok := config[want]; ok {
+			failures = append(failures, p.CheckFailure{
+				Property: "rawConfig",
+				Reason:   fmt.Sprintf("both %q and %q are set; remove %q", key, want, key),
+			})
+			continue
+		}
+		config[want] = config[key]
+		delete(config, key)
+	}
+	if !normalize || len(failures) > 0 {
+		return raw, failures
+	}
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		return raw, []p.CheckFailure{{Property: "rawConfig", Reason: fmt.Sprintf("failed to normalize keys: %v", err)}}
+	}
+	return string(out), nil
+}
+
+// stripConnectorDefaults removes server-added default keys from a connector's
+// config bytes. The original bytes are returned when nothing was stripped or
+// the config is not a JSON object.
+func stripConnectorDefaults(connectorType string, config []byte) []byte {
+	var m map[string]any
+	if err := json.Unmarshal(config, &m); err != nil {
+		return config
+	}
+	if !provider.StripServerDefaults(connectorType, m) {
+		return config
+	}
+	out, err := json.Marshal(m)
+	if err != nil {
+		return config
+	}
+	return out
+}
+
+// findConnectorById returns the connector with the given ID, or nil if Dex has
+// none. Dex API doesn't expose GetConnector, so this lists and filters; the
+// list is shared between reads through cfg's short-lived connector cache.
+func findConnectorById(ctx context.Context, cfg provider.DexConfig, id string, timeoutSeconds *int) (*api.Connector, error) {
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+
+	con, err := cfg.FindConnector(listCtx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connectors: %w", err)
+	}
+	return con, nil
+}
+
+// verifyConnectorDeleted confirms via ListConnectors that a connector Dex
+// reported as deleted is gone; see verifyDeleted.
+func verifyConnectorDeleted(ctx context.Context, cfg provider.DexConfig, id string, timeoutSeconds *int) error {
+	return verifyDeleted(ctx, cfg, "connector", id, func(ctx context.Context) (bool, error) {
+		con, err := findConnectorById(ctx, cfg, id, timeoutSeconds)
+		return con != nil, err
+	})
+}
+
+// matchExistingConnector handles an AlreadyExists response from CreateConnector.
+// A concurrent or retried create may have already written the same connector;
+// in that case the create is treated as idempotent and nil is returned. An error
+// is returned only when the existing connector differs from want.
+func matchExistingConnector(ctx context.Context, cfg provider.DexConfig, want *api.Connector, timeoutSeconds *int) error {
+	found, err := findConnectorById(ctx, cfg, want.Id, timeoutSeconds)
+	if err != nil {
+		return fmt.Errorf("connector with id %q already exists but %w", want.Id, err)
+	}
+	if found == nil {
+		return fmt.Errorf("connector with id %q already exists but not found in list", want.Id)
+	}
+
+	// Compare the configs as decodeConnector reads them, so defaults Dex
+	// filled in on its own do not count as a difference.
+	foundConfig := stripConnectorDefaults(found.Type, trimConfigBytes(found.Config))
+	wantConfig := stripConnectorDefaults(want.Type, trimConfigBytes(want.Config))
+	if found.Type != want.Type || found.Name != want.Name || !jsonBytesEqual(foundConfig, wantConfig) {
+		return fmt.Errorf("connector with id %q %w with a different configuration", want.Id, provider.ErrAlreadyExists)
+	}
+	return nil
+}
+
+// jsonBytesEqual reports whether two JSON documents decode to the same value.
+func jsonBytesEqual(a, b []byte) bool {
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
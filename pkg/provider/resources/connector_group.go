@@ -0,0 +1,336 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// ConnectorGroup - Primary SSO connector plus an emergency local connector
+// ============================================================================
+
+// ConnectorGroupFallback describes the local connector kept as a break-glass login.
+type ConnectorGroupFallback struct {
+	ConnectorId string `pulumi:"connectorId"`
+	Name        string `pulumi:"name"`
+}
+
+// ConnectorGroupArgs defines inputs for ConnectorGroup.
+type ConnectorGroupArgs struct {
+	Primary        ConnectorArgs          `pulumi:"primary"`
+	Fallback       ConnectorGroupFallback `pulumi:"fallback"`
+	RetainFallback *bool                  `pulumi:"retainFallback,optional"`
+	TimeoutSeconds *int                   `pulumi:"timeoutSeconds,optional"`
+}
+
+// ConnectorGroupState defines outputs for ConnectorGroup.
+type ConnectorGroupState struct {
+	ConnectorGroupArgs
+	PrimaryConnectorId  string `pulumi:"primaryConnectorId"`
+	FallbackConnectorId string `pulumi:"fallbackConnectorId"`
+	Healthy             bool   `pulumi:"healthy"`
+	HealthSummary       string `pulumi:"healthSummary"`
+}
+
+// ConnectorGroup provisions a primary connector together with a local fallback connector.
+type ConnectorGroup struct{}
+
+// Annotate provides schema metadata.
+func (r *ConnectorGroup) Annotate(a infer.Annotator) {
+	a.Describe(r, "Provisions a primary SSO connector together with an emergency local (password database) connector. "+
+		"The fallback is recreated on update if it has gone missing and, unless retainFallback is false, is left in place when the group is destroyed.")
+}
+
+// Annotate provides schema metadata for ConnectorGroupArgs.
+func (r *ConnectorGroupArgs) Annotate(a infer.Annotator) {
+	a.Describe(&r.Primary, "The primary connector. Takes the same inputs as Connector; its type must not be 'local'.")
+	a.Describe(&r.Fallback, "The local fallback connector.")
+	a.Describe(&r.RetainFallback, "If true (the default), destroying the group deletes only the primary connector and keeps the fallback.")
+	a.Describe(&r.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for ConnectorGroupFallback.
+func (r *ConnectorGroupFallback) Annotate(a infer.Annotator) {
+	a.Describe(&r.ConnectorId, "Unique identifier for the fallback local connector.")
+	a.Describe(&r.Name, "Human-readable name for the fallback connector, displayed to users during login.")
+}
+
+// Annotate provides schema metadata for ConnectorGroupState.
+func (r *ConnectorGroupState) Annotate(a infer.Annotator) {
+	a.Describe(&r.PrimaryConnectorId, "ID of the primary connector.")
+	a.Describe(&r.FallbackConnectorId, "ID of the fallback connector.")
+	a.Describe(&r.Healthy, "True when both connectors were present in Dex at the last create, update, or refresh.")
+	a.Describe(&r.HealthSummary, "Human-readable presence summary for both connectors.")
+}
+
+// Check validates inputs.
+func (r *ConnectorGroup) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[ConnectorGroupArgs], error) {
+	args, failures, err := infer.DefaultCheck[ConnectorGroupArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[ConnectorGroupArgs]{Failures: failures}, err
+	}
+
+	failures = append(failures, checkConnectorGroupArgs(args)...)
+
+	if args.RetainFallback == nil {
+		defaultRetain := true
+		args.RetainFallback = &defaultRetain
+	}
+
+	return infer.CheckResponse[ConnectorGroupArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// checkConnectorGroupArgs validates the primary and fallback connectors of a group.
+func checkConnectorGroupArgs(args ConnectorGroupArgs) []p.CheckFailure {
+	var failures []p.CheckFailure
+	if err := validateConnectorArgs(args.Primary); err != nil {
+		failures = append(failures, p.CheckFailure{Property: "primary", Reason: err.Error()})
+	}
+	if args.Primary.Type == "local" {
+		failures = append(failures, p.CheckFailure{
+			Property: "primary.type",
+			Reason:   "the primary connector must not be a local connector; use fallback for that",
+		})
+	}
+	if args.Fallback.ConnectorId == "" {
+		failures = append(failures, p.CheckFailure{Property: "fallback.connectorId", Reason: "connectorId is required"})
+	}
+	if args.Fallback.ConnectorId != "" && args.Fallback.ConnectorId == args.Primary.ConnectorId {
+		failures = append(failures, p.CheckFailure{
+			Property: "fallback.connectorId",
+			Reason:   "must differ from primary.connectorId",
+		})
+	}
+	return failures
+}
+
+// Create provisions the fallback connector first, then the primary.
+func (r *ConnectorGroup) Create(ctx context.Context, req infer.CreateRequest[ConnectorGroupArgs]) (infer.CreateResponse[ConnectorGroupState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[ConnectorGroupState]{}, err
+		}
+		return infer.CreateResponse[ConnectorGroupState]{
+			ID:     args.Primary.ConnectorId,
+			Output: connectorGroupState(args, true, true),
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[ConnectorGroupState]{}, provider.ErrClientNotConfigured
+	}
+
+	// The fallback goes first so there is never a moment with only the primary.
+	if err := ensureFallbackConnector(ctx, cfg, args.Fallback, args.TimeoutSeconds); err != nil {
+		return infer.CreateResponse[ConnectorGroupState]{}, err
+	}
+
+	configBytes, err := buildConnectorConfigBytes(args.Primary)
+	if err != nil {
+		return infer.CreateResponse[ConnectorGroupState]{}, err
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	primary := &api.Connector{
+		Id:     args.Primary.ConnectorId,
+		Type:   args.Primary.Type,
+		Name:   args.Primary.Name,
+		Config: configBytes,
+	}
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{Connector: primary})
+	if err != nil {
+		return infer.CreateResponse[ConnectorGroupState]{}, provider.WrapError("create", "connector-group-primary", args.Primary.ConnectorId, err)
+	}
+	if resp.AlreadyExists {
+		if err := matchExistingConnector(ctx, cfg, primary, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[ConnectorGroupState]{}, err
+		}
+	}
+
+	return infer.CreateResponse[ConnectorGroupState]{
+		ID:     args.Primary.ConnectorId,
+		Output: connectorGroupState(args, true, true),
+	}, nil
+}
+
+// Read refreshes both connectors and the health summary.
+func (r *ConnectorGroup) Read(ctx context.Context, req infer.ReadRequest[ConnectorGroupArgs, ConnectorGroupState]) (infer.ReadResponse[ConnectorGroupArgs, ConnectorGroupState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[ConnectorGroupArgs, ConnectorGroupState]{}, provider.ErrClientNotConfigured
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.Inputs.TimeoutSeconds))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	if err != nil {
+		return infer.ReadResponse[ConnectorGroupArgs, ConnectorGroupState]{}, fmt.Errorf("failed to list connectors: %w", err)
+	}
+
+	args := req.State.ConnectorGroupArgs
+	existing := indexByID(listResp.Connectors)
+	primary, fallback := existing[req.ID], existing[args.Fallback.ConnectorId]
+	if primary == nil {
+		// The primary anchors the group; without it the resource is gone.
+		return infer.ReadResponse[ConnectorGroupArgs, ConnectorGroupState]{}, nil
+	}
+
+	primaryArgs, _, err := decodeConnector(primary)
+	if err != nil {
+		return infer.ReadResponse[ConnectorGroupArgs, ConnectorGroupState]{}, err
+	}
+	args.Primary = primaryArgs
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+	if fallback != nil {
+		args.Fallback.Name = fallback.Name
+	}
+
+	return infer.ReadResponse[ConnectorGroupArgs, ConnectorGroupState]{
+		ID:     primary.Id,
+		Inputs: args,
+		State:  connectorGroupState(args, true, fallback != nil),
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (r *ConnectorGroup) Diff(ctx context.Context, req infer.DiffRequest[ConnectorGroupArgs, ConnectorGroupState]) (infer.DiffResponse, error) {
+	return diffResource("ConnectorGroup", req.State.ConnectorGroupArgs, req.Inputs), nil
+}
+
+// Update updates the primary connector and makes sure the fallback exists.
+func (r *ConnectorGroup) Update(ctx context.Context, req infer.UpdateRequest[ConnectorGroupArgs, ConnectorGroupState]) (infer.UpdateResponse[ConnectorGroupState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[ConnectorGroupState]{}, err
+		}
+		return infer.UpdateResponse[ConnectorGroupState]{Output: connectorGroupState(args, true, true)}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[ConnectorGroupState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("ConnectorGroup", oldState.ConnectorGroupArgs, args); err != nil {
+		return infer.UpdateResponse[ConnectorGroupState]{}, err
+	}
+
+	if err := ensureFallbackConnector(ctx, cfg, args.Fallback, args.TimeoutSeconds); err != nil {
+		return infer.UpdateResponse[ConnectorGroupState]{}, err
+	}
+
+	configBytes, err := buildConnectorConfigBytes(args.Primary)
+	if err != nil {
+		return infer.UpdateResponse[ConnectorGroupState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.Primary.ConnectorId,
+		NewType:   args.Primary.Type,
+		NewName:   args.Primary.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[ConnectorGroupState]{}, provider.WrapError("update", "connector-group-primary", args.Primary.ConnectorId, err)
+	}
+
+	return infer.UpdateResponse[ConnectorGroupState]{Output: connectorGroupState(args, true, true)}, nil
+}
+
+// Delete removes the primary connector, and the fallback only when retainFallback is false.
+func (r *ConnectorGroup) Delete(ctx context.Context, req infer.DeleteRequest[ConnectorGroupState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	ids := []string{req.ID}
+	if !provider.PtrOr(req.State.RetainFallback, true) {
+		ids = append(ids, req.State.Fallback.ConnectorId)
+	} else {
+		p.GetLogger(ctx).Infof("retaining fallback connector %q", req.State.Fallback.ConnectorId)
+	}
+
+	for _, id := range ids {
+		deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+		_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{Id: id})
+		cancel()
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return infer.DeleteResponse{}, provider.WrapError("delete", "connector-group", id, err)
+		}
+		if err := verifyConnectorDeleted(ctx, cfg, id, req.State.TimeoutSeconds); err != nil {
+			return infer.DeleteResponse{}, err
+		}
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// ensureFallbackConnector creates the local fallback connector if it does not exist.
+func ensureFallbackConnector(ctx context.Context, cfg provider.DexConfig, fallback ConnectorGroupFallback, timeoutSeconds *int) error {
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: &api.Connector{
+			Id:     fallback.ConnectorId,
+			Type:   "local",
+			Name:   fallback.Name,
+			Config: []byte("{}"),
+		},
+	})
+	if err != nil {
+		return provider.WrapError("create", "connector-group-fallback", fallback.ConnectorId, err)
+	}
+	// AlreadyExists is fine: the fallback is shared and may predate the group.
+	return nil
+}
+
+// connectorGroupState assembles the output state and health summary.
+func connectorGroupState(args ConnectorGroupArgs, primaryPresent, fallbackPresent bool) ConnectorGroupState {
+	presence := func(present bool) string {
+		if present {
+			return "present"
+		}
+		return "missing"
+	}
+	summary := []string{
+		fmt.Sprintf("primary %q: %s", args.Primary.ConnectorId, presence(primaryPresent)),
+		fmt.Sprintf("fallback %q: %s", args.Fallback.ConnectorId, presence(fallbackPresent)),
+	}
+
+	return ConnectorGroupState{
+		ConnectorGroupArgs:  args,
+		PrimaryConnectorId:  args.Primary.ConnectorId,
+		FallbackConnectorId: args.Fallback.ConnectorId,
+		Healthy:             primaryPresent && fallbackPresent,
+		HealthSummary:       strings.Join(summary, "; "),
+	}
+}
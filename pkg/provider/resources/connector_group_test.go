@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+func TestConnectorGroupIDChangeReplaces(t *testing.T) {
+	config := `{"issuer":"https://idp.example.com"}`
+	olds := ConnectorGroupArgs{
+		Primary:  ConnectorArgs{ConnectorId: "sso", Type: "oidc", Name: "SSO", RawConfig: &config},
+		Fallback: ConnectorGroupFallback{ConnectorId: "local", Name: "Emergency login"},
+	}
+	diffGroup := func(news ConnectorGroupArgs) infer.DiffResponse {
+		resp, err := (&ConnectorGroup{}).Diff(context.Background(), infer.DiffRequest[ConnectorGroupArgs, ConnectorGroupState]{
+			ID:     "sso",
+			State:  ConnectorGroupState{ConnectorGroupArgs: olds},
+			Inputs: news,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	renamedPrimary := olds
+	renamedPrimary.Primary.ConnectorId = "sso2"
+	renamedFallback := olds
+	renamedFallback.Fallback.ConnectorId = "local2"
+	retitled := olds
+	retitled.Primary.Name = "Company SSO"
+
+	tests := []struct {
+		name        string
+		news        ConnectorGroupArgs
+		property    string
+		wantKind    p.DiffKind
+		wantReplace bool
+	}{
+		{"primary connectorId", renamedPrimary, "primary.connectorId", p.UpdateReplace, true},
+		{"fallback connectorId", renamedFallback, "fallback.connectorId", p.UpdateReplace, true},
+		{"primary name", retitled, "primary.name", p.Update, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffGroup(tt.news)
+			if got := diff.DetailedDiff[tt.property].Kind; got != tt.wantKind {
+				t.Errorf("%s diff kind = %q, want %q (diff %+v)", tt.property, got, tt.wantKind, diff.DetailedDiff)
+			}
+			if diff.DeleteBeforeReplace != tt.wantReplace {
+				t.Errorf("DeleteBeforeReplace = %v, want %v", diff.DeleteBeforeReplace, tt.wantReplace)
+			}
+			if err := checkImmutableFields("ConnectorGroup", olds, tt.news); (err != nil) != tt.wantReplace {
+				t.Errorf("checkImmutableFields() = %v, want error %v", err, tt.wantReplace)
+			}
+		})
+	}
+}
+
+func TestCheckConnectorGroupArgs(t *testing.T) {
+	config := `{"issuer":"https://idp.example.com"}`
+	primary := ConnectorArgs{ConnectorId: "sso", Type: "oidc", Name: "SSO", RawConfig: &config}
+
+	tests := []struct {
+		name      string
+		args      ConnectorGroupArgs
+		wantProps []string
+	}{
+		{
+			name: "valid",
+			args: ConnectorGroupArgs{Primary: primary, Fallback: ConnectorGroupFallback{ConnectorId: "local", Name: "Local"}},
+		},
+		{
+			name:      "same IDs",
+			args:      ConnectorGroupArgs{Primary: primary, Fallback: ConnectorGroupFallback{ConnectorId: "sso", Name: "Local"}},
+			wantProps: []string{"fallback.connectorId"},
+		},
+		{
+			name:      "both IDs empty",
+			args:      ConnectorGroupArgs{Primary: ConnectorArgs{Type: "oidc", Name: "SSO", RawConfig: &config}, Fallback: ConnectorGroupFallback{Name: "Local"}},
+			wantProps: []string{"primary", "fallback.connectorId"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkConnectorGroupArgs(tt.args)
+			var props []string
+			for _, f := range failures {
+				props = append(props, f.Property)
+			}
+			if !reflect.DeepEqual(props, tt.wantProps) {
+				t.Errorf("failures = %+v, want properties %v", failures, tt.wantProps)
+			}
+		})
+	}
+}
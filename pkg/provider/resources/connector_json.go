@@ -0,0 +1,352 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// ConnectorJSON - Any connector type with a free-form config object
+// ============================================================================
+
+// ConnectorJSONArgs defines inputs for ConnectorJSON.
+type ConnectorJSONArgs struct {
+	ConnectorId    string         `pulumi:"connectorId"`
+	Type           string         `pulumi:"type"`
+	Name           string         `pulumi:"name"`
+	Config         map[string]any `pulumi:"config" provider:"secret"`
+	TimeoutSeconds *int           `pulumi:"timeoutSeconds,optional"`
+}
+
+// ConnectorJSONState defines outputs for ConnectorJSON.
+type ConnectorJSONState struct {
+	ConnectorJSONArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
+}
+
+// ConnectorJSON manages a connector of any type from a config object that is
+// sent to Dex as is.
+type ConnectorJSON struct{}
+
+// Annotate provides schema metadata.
+func (c *ConnectorJSON) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages a Dex connector of any type from a config object that is sent to Dex unchanged. "+
+		"It sits between the typed connector resources and dex.Connector's rawConfig: keys use Dex's own spelling (e.g. 'clientID'), "+
+		"and for known types the keys Dex requires are checked during preview.")
+}
+
+// Annotate provides schema metadata for ConnectorJSONArgs.
+func (c *ConnectorJSONArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the connector.")
+	a.Describe(&c.Type, "Dex connector type, e.g. 'oidc', 'github', 'saml'.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.Config, "Connector config as Dex expects it, with Dex's key spelling. Stored as a secret, since it usually holds a client secret.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for ConnectorJSONState.
+func (c *ConnectorJSONState) Annotate(a infer.Annotator) {
+	// ConnectorJSONState embeds ConnectorJSONArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// connectorRequiredKeys lists, per connector type, the config keys Dex
+// requires. Types not listed here are sent without key checks.
+var connectorRequiredKeys = map[string][]string{
+	"oidc":      {"issuer", "clientID", "clientSecret", "redirectURI"},
+	"github":    {"clientID", "clientSecret", "redirectURI"},
+	"gitlab":    {"clientID", "clientSecret", "redirectURI"},
+	"google":    {"clientID", "clientSecret", "redirectURI"},
+	"microsoft": {"clientID", "clientSecret", "redirectURI"},
+	"oauth":     {"clientID", "clientSecret", "redirectURI", "tokenURL", "authorizationURL", "userInfoURL"},
+	"ldap":      {"host", "userSearch"},
+	"saml":      {"ssoURL", "redirectURI"},
+}
+
+// Check validates inputs.
+func (c *ConnectorJSON) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[ConnectorJSONArgs], error) {
+	args, failures, err := infer.DefaultCheck[ConnectorJSONArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[ConnectorJSONArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	// Unknown types are only warned about, since the registry may lag behind Dex.
+	if types, source := provider.SupportedConnectorTypes(ctx, cfg.Client); args.Type != "" && !provider.IsSupportedConnectorType(types, args.Type) {
+		failures = append(failures, checkWarning(ctx, cfg, "type",
+			fmt.Sprintf("connector %q has type %q, which is not in the %s list of supported connector types", args.ConnectorId, args.Type, source))...)
+	}
+
+	failures = append(failures, validateRequiredConfigKeys(args.Type, args.Config)...)
+	if redirect, ok := args.Config["redirectURI"].(string); ok {
+		failures = append(failures, validateRedirectURI(ctx, cfg, "config.redirectURI", redirect)...)
+	}
+
+	if configBytes, err := json.Marshal(args.Config); err == nil {
+		failures = append(failures, checkConfigSize(ctx, cfg, "config", configBytes)...)
+	}
+
+	return infer.CheckResponse[ConnectorJSONArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// validateRequiredConfigKeys reports every key in connectorRequiredKeys for
+// connectorType that is missing from config or set to an empty value.
+func validateRequiredConfigKeys(connectorType string, config map[string]any) []p.CheckFailure {
+	var failures []p.CheckFailure
+	for _, key := range connectorRequiredKeys[connectorType] {
+		v := config[key]
+		if s, isString := v.(string); v == nil || (isString && s == "") {
+			failures = append(failures, p.CheckFailure{
+				Property: "config." + key,
+				Reason:   fmt.Sprintf("%q is required for %s connectors", key, connectorType),
+			})
+		}
+	}
+	return failures
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *ConnectorJSON) Diff(ctx context.Context, req infer.DiffRequest[ConnectorJSONArgs, ConnectorJSONState]) (infer.DiffResponse, error) {
+	return diffResource("ConnectorJSON", req.State.ConnectorJSONArgs, req.Inputs), nil
+}
+
+// Create creates a new connector.
+func (c *ConnectorJSON) Create(ctx context.Context, req infer.CreateRequest[ConnectorJSONArgs]) (infer.CreateResponse[ConnectorJSONState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[ConnectorJSONState]{}, err
+		}
+		state := ConnectorJSONState{
+			ConnectorJSONArgs: args,
+			CallbackUrl:       dexCallbackURL(ctx),
+		}
+		return infer.CreateResponse[ConnectorJSONState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[ConnectorJSONState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := marshalConnectorJSON(args.Config)
+	if err != nil {
+		return infer.CreateResponse[ConnectorJSONState]{}, err
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   args.Type,
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[ConnectorJSONState]{}, provider.WrapError("create", "connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[ConnectorJSONState]{}, err
+		}
+	}
+
+	state := ConnectorJSONState{
+		ConnectorJSONArgs: args,
+		CallbackUrl:       dexCallbackURL(ctx),
+	}
+
+	return infer.CreateResponse[ConnectorJSONState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing connector. The config is returned exactly as
+// Dex stores it, without the key translation of the typed resources.
+func (c *ConnectorJSON) Read(ctx context.Context, req infer.ReadRequest[ConnectorJSONArgs, ConnectorJSONState]) (infer.ReadResponse[ConnectorJSONArgs, ConnectorJSONState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[ConnectorJSONArgs, ConnectorJSONState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[ConnectorJSONArgs, ConnectorJSONState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[ConnectorJSONArgs, ConnectorJSONState]{}, nil
+	}
+
+	configMap := map[string]any{}
+	if trimmed := trimConfigBytes(found.Config); len(trimmed) > 0 {
+		if err := json.Unmarshal(trimmed, &configMap); err != nil {
+			// The connector still exists, so keep the previous state rather than
+			// reporting it as deleted.
+			return unparseableConnectorRead(ctx, req, found.Id, err)
+		}
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, req.State.Config)
+	}
+	if req.State.ConnectorId != "" {
+		restoreSecretConfigKeys(cfg, configMap, req.State.Config)
+	}
+
+	args := ConnectorJSONArgs{
+		ConnectorId: found.Id,
+		Type:        found.Type,
+		Name:        found.Name,
+		Config:      configMap,
+	}
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := ConnectorJSONState{
+		ConnectorJSONArgs: args,
+		CallbackUrl:       dexCallbackURL(ctx),
+		RawConfigOut:      redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[ConnectorJSONArgs, ConnectorJSONState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing connector.
+func (c *ConnectorJSON) Update(ctx context.Context, req infer.UpdateRequest[ConnectorJSONArgs, ConnectorJSONState]) (infer.UpdateResponse[ConnectorJSONState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[ConnectorJSONState]{}, err
+		}
+		state := ConnectorJSONState{
+			ConnectorJSONArgs: args,
+			CallbackUrl:       dexCallbackURL(ctx),
+		}
+		return infer.UpdateResponse[ConnectorJSONState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[ConnectorJSONState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("ConnectorJSON", oldState.ConnectorJSONArgs, args); err != nil {
+		return infer.UpdateResponse[ConnectorJSONState]{}, err
+	}
+
+	configBytes, err := marshalConnectorJSON(args.Config)
+	if err != nil {
+		return infer.UpdateResponse[ConnectorJSONState]{}, err
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[ConnectorJSONState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   args.Type,
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[ConnectorJSONState]{}, provider.WrapError("update", "connector", args.ConnectorId, err)
+	}
+
+	state := ConnectorJSONState{
+		ConnectorJSONArgs: args,
+		CallbackUrl:       dexCallbackURL(ctx),
+	}
+
+	return infer.UpdateResponse[ConnectorJSONState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes a connector.
+func (c *ConnectorJSON) Delete(ctx context.Context, req infer.DeleteRequest[ConnectorJSONState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// marshalConnectorJSON encodes a ConnectorJSON config for Dex. A nil config
+// is sent as an empty object, which is what config-less types such as "local"
+// expect.
+func marshalConnectorJSON(config map[string]any) ([]byte, error) {
+	if config == nil {
+		return []byte("{}"), nil
+	}
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal connector config: %w", err)
+	}
+	return configBytes, nil
+}
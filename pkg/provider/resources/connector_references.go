@@ -0,0 +1,76 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// connectorReferenceKeys lists, per connector type, the rawConfig keys whose
+// value names another connector that this one delegates to.
+var connectorReferenceKeys = map[string][]string{
+	"authproxy": {"delegateConnector"},
+	"oauth":     {"delegateConnector"},
+}
+
+// checkConnectorReferences reports a failure for every connector ID referenced
+// from args' rawConfig that does not exist in Dex. It is skipped when Dex is not
+// reachable (e.g. an unconfigured provider) so that Check never fails for
+// reasons unrelated to the inputs.
+func checkConnectorReferences(ctx context.Context, cfg provider.DexConfig, args ConnectorArgs) []p.CheckFailure {
+	keys := connectorReferenceKeys[args.Type]
+	if len(keys) == 0 || args.RawConfig == nil || *args.RawConfig == "" || cfg.Client == nil {
+		return nil
+	}
+
+	var config map[string]any
+	if err := json.Unmarshal([]byte(*args.RawConfig), &config); err != nil {
+		return nil
+	}
+
+	refs := map[string]string{}
+	for _, key := range keys {
+		if id, ok := config[key].(string); ok && id != "" {
+			refs[key] = id
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	if err != nil {
+		p.GetLogger(ctx).Warningf("skipping connector reference check for %q: failed to list connectors: %v", args.ConnectorId, err)
+		return nil
+	}
+
+	existing := indexByID(listResp.Connectors)
+
+	var failures []p.CheckFailure
+	for _, key := range keys {
+		id, ok := refs[key]
+		if !ok {
+			continue
+		}
+		switch {
+		case id == args.ConnectorId:
+			failures = append(failures, p.CheckFailure{
+				Property: "rawConfig",
+				Reason:   fmt.Sprintf("%s must not reference the connector itself", key),
+			})
+		case existing[id] == nil:
+			failures = append(failures, p.CheckFailure{
+				Property: "rawConfig",
+				Reason:   fmt.Sprintf("%s references connector %q, which does not exist in Dex; if it is created in the same program, build rawConfig from its connectorId output", key, id),
+			})
+		}
+	}
+	return failures
+}
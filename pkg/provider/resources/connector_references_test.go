@@ -0,0 +1,43 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+)
+
+func TestCheckConnectorReferences(t *testing.T) {
+	cfg := provider.DexConfig{Client: &fakeDex{connectors: []*api.Connector{{Id: "corp-oidc", Type: "oidc"}}}}
+	args := func(connectorType, config string) ConnectorArgs {
+		return ConnectorArgs{ConnectorId: "proxy", Type: connectorType, Name: "Proxy", RawConfig: &config}
+	}
+
+	tests := []struct {
+		name      string
+		args      ConnectorArgs
+		wantFails int
+	}{
+		{name: "existing delegate", args: args("authproxy", `{"delegateConnector":"corp-oidc"}`)},
+		{name: "dangling delegate", args: args("authproxy", `{"delegateConnector":"missing"}`), wantFails: 1},
+		{name: "oauth dangling delegate", args: args("oauth", `{"delegateConnector":"missing"}`), wantFails: 1},
+		{name: "self reference", args: args("oauth", `{"delegateConnector":"proxy"}`), wantFails: 1},
+		{name: "no delegate", args: args("authproxy", `{"userHeader":"X-Remote-User"}`)},
+		{name: "type without references", args: args("ldap", `{"delegateConnector":"missing"}`)},
+		{name: "invalid JSON is left to other checks", args: args("authproxy", `{"delegateConnector":`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := checkConnectorReferences(context.Background(), cfg, tt.args)
+			if len(failures) != tt.wantFails {
+				t.Errorf("failures = %+v, want %d", failures, tt.wantFails)
+			}
+		})
+	}
+
+	// Without a configured Dex client the check is skipped.
+	if failures := checkConnectorReferences(context.Background(), provider.DexConfig{}, args("authproxy", `{"delegateConnector":"missing"}`)); len(failures) != 0 {
+		t.Errorf("failures without a client = %+v, want none", failures)
+	}
+}
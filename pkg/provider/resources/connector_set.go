@@ -0,0 +1,450 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// ConnectorSet - Many connectors managed together with shared defaults
+// ============================================================================
+
+// ConnectorSetDefaults holds settings applied to every connector in the set
+// that does not set them itself.
+type ConnectorSetDefaults struct {
+	RedirectUri *string  `pulumi:"redirectUri,optional"`
+	Scopes      []string `pulumi:"scopes,optional"`
+}
+
+// ConnectorSetArgs defines inputs for ConnectorSet.
+type ConnectorSetArgs struct {
+	Connectors     []ConnectorArgs       `pulumi:"connectors"`
+	Defaults       *ConnectorSetDefaults `pulumi:"defaults,optional"`
+	TimeoutSeconds *int                  `pulumi:"timeoutSeconds,optional"`
+}
+
+// ConnectorSetStatus reports what the last operation did to one connector.
+type ConnectorSetStatus struct {
+	ConnectorId string `pulumi:"connectorId"`
+	Status      string `pulumi:"status"`
+}
+
+// ConnectorSetState defines outputs for ConnectorSet.
+type ConnectorSetState struct {
+	ConnectorSetArgs
+	Statuses []ConnectorSetStatus `pulumi:"statuses"`
+}
+
+// Statuses reported per connector.
+const (
+	connectorSetCreated   = "created"
+	connectorSetAdopted   = "adopted"
+	connectorSetUpdated   = "updated"
+	connectorSetUnchanged = "unchanged"
+	connectorSetPresent   = "present"
+	connectorSetMissing   = "missing"
+)
+
+// ConnectorSet manages a list of connectors as one resource.
+type ConnectorSet struct{}
+
+// Annotate provides schema metadata.
+func (r *ConnectorSet) Annotate(a infer.Annotator) {
+	a.Describe(r, "Manages a list of connectors together, with a shared redirect URI and OIDC scopes. "+
+		"Connectors added to the list are created, removed ones are deleted, and changed ones are updated in place. "+
+		"The resource ID is the Pulumi resource name.")
+}
+
+// Annotate provides schema metadata for ConnectorSetArgs.
+func (r *ConnectorSetArgs) Annotate(a infer.Annotator) {
+	a.Describe(&r.Connectors, "The connectors to manage. Each takes the same inputs as Connector; connector IDs must be unique.")
+	a.Describe(&r.Defaults, "Settings applied to every connector that does not set them itself.")
+	a.Describe(&r.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for ConnectorSetDefaults.
+func (r *ConnectorSetDefaults) Annotate(a infer.Annotator) {
+	a.Describe(&r.RedirectUri, "Dex callback URL used as oidcConfig.redirectUri, or as redirectURI in a rawConfig that has none.")
+	a.Describe(&r.Scopes, "Scopes used as oidcConfig.scopes when a connector sets none.")
+}
+
+// Annotate provides schema metadata for ConnectorSetStatus.
+func (r *ConnectorSetStatus) Annotate(a infer.Annotator) {
+	a.Describe(&r.ConnectorId, "ID of the connector.")
+	a.Describe(&r.Status, "One of 'created', 'adopted', 'updated', or 'unchanged' after create or update, and 'present' or 'missing' after refresh.")
+}
+
+// Annotate provides schema metadata for ConnectorSetState.
+func (r *ConnectorSetState) Annotate(a infer.Annotator) {
+	a.Describe(&r.Statuses, "Per-connector status from the last create, update, or refresh, in the order of connectors.")
+}
+
+// Check applies the shared defaults and validates every connector.
+func (r *ConnectorSet) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[ConnectorSetArgs], error) {
+	args, failures, err := infer.DefaultCheck[ConnectorSetArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[ConnectorSetArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	if args.Defaults != nil {
+		failures = append(failures, validateRedirectURI(ctx, cfg, "defaults.redirectUri", provider.PtrOr(args.Defaults.RedirectUri, ""))...)
+		failures = append(failures, validateScopes("defaults.scopes", args.Defaults.Scopes)...)
+	}
+	applyConnectorSetDefaults(&args)
+
+	seen := map[string]bool{}
+	for i, con := range args.Connectors {
+		property := fmt.Sprintf("connectors[%d]", i)
+		if seen[con.ConnectorId] {
+			failures = append(failures, p.CheckFailure{
+				Property: property + ".connectorId",
+				Reason:   fmt.Sprintf("duplicate connector ID %q", con.ConnectorId),
+			})
+		}
+		seen[con.ConnectorId] = true
+
+		if err := validateConnectorArgs(con); err != nil {
+			failures = append(failures, p.CheckFailure{Property: property, Reason: err.Error()})
+		}
+		if con.Enabled != nil {
+			failures = append(failures, p.CheckFailure{Property: property + ".enabled", Reason: "enabled is only supported on dex.Connector; remove the connector from the set instead"})
+		}
+		if con.OIDCConfig != nil {
+			failures = append(failures, checkOpenIDScope(ctx, cfg, property+".oidcConfig.scopes", con.OIDCConfig.Scopes)...)
+		}
+	}
+
+	return infer.CheckResponse[ConnectorSetArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Create creates every connector in the set.
+func (r *ConnectorSet) Create(ctx context.Context, req infer.CreateRequest[ConnectorSetArgs]) (infer.CreateResponse[ConnectorSetState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[ConnectorSetState]{}, err
+		}
+		return infer.CreateResponse[ConnectorSetState]{
+			ID:     req.Name,
+			Output: connectorSetState(args, uniformStatuses(args.Connectors, connectorSetCreated)),
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[ConnectorSetState]{}, provider.ErrClientNotConfigured
+	}
+
+	var created []string
+	statuses := make([]string, 0, len(args.Connectors))
+	for _, con := range args.Connectors {
+		result, err := createSetConnector(ctx, cfg, con, args.TimeoutSeconds)
+		if err != nil {
+			// Roll back what was created so a retried Create starts clean.
+			rollbackSetConnectors(ctx, cfg, created, args.TimeoutSeconds)
+			return infer.CreateResponse[ConnectorSetState]{}, err
+		}
+		if result == connectorSetCreated {
+			created = append(created, con.ConnectorId)
+		}
+		statuses = append(statuses, result)
+	}
+
+	return infer.CreateResponse[ConnectorSetState]{
+		ID:     req.Name,
+		Output: connectorSetState(args, statuses),
+	}, nil
+}
+
+// Read refreshes the connectors of the set. Connectors missing from Dex are
+// dropped from the inputs, so the next update creates them again.
+func (r *ConnectorSet) Read(ctx context.Context, req infer.ReadRequest[ConnectorSetArgs, ConnectorSetState]) (infer.ReadResponse[ConnectorSetArgs, ConnectorSetState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[ConnectorSetArgs, ConnectorSetState]{}, provider.ErrClientNotConfigured
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.Inputs.TimeoutSeconds))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	if err != nil {
+		return infer.ReadResponse[ConnectorSetArgs, ConnectorSetState]{}, fmt.Errorf("failed to list connectors: %w", err)
+	}
+	existing := indexByID(listResp.Connectors)
+
+	args := req.State.ConnectorSetArgs
+	args.Defaults = req.Inputs.Defaults
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	var connectors []ConnectorArgs
+	var statuses []ConnectorSetStatus
+	for _, want := range req.State.Connectors {
+		found, ok := existing[want.ConnectorId]
+		if !ok {
+			statuses = append(statuses, ConnectorSetStatus{ConnectorId: want.ConnectorId, Status: connectorSetMissing})
+			continue
+		}
+		con, _, err := decodeConnector(found)
+		if err != nil {
+			return infer.ReadResponse[ConnectorSetArgs, ConnectorSetState]{}, err
+		}
+		con.TimeoutSeconds = want.TimeoutSeconds
+		connectors = append(connectors, con)
+		statuses = append(statuses, ConnectorSetStatus{ConnectorId: want.ConnectorId, Status: connectorSetPresent})
+	}
+	if len(connectors) == 0 {
+		// Nothing of the set is left in Dex; treat the resource as deleted.
+		return infer.ReadResponse[ConnectorSetArgs, ConnectorSetState]{}, nil
+	}
+	args.Connectors = connectors
+
+	return infer.ReadResponse[ConnectorSetArgs, ConnectorSetState]{
+		ID:     req.ID,
+		Inputs: args,
+		State:  ConnectorSetState{ConnectorSetArgs: args, Statuses: statuses},
+	}, nil
+}
+
+// Update creates added connectors, updates changed ones, and deletes the ones
+// removed from the set.
+func (r *ConnectorSet) Update(ctx context.Context, req infer.UpdateRequest[ConnectorSetArgs, ConnectorSetState]) (infer.UpdateResponse[ConnectorSetState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[ConnectorSetState]{}, err
+		}
+		return infer.UpdateResponse[ConnectorSetState]{
+			Output: connectorSetState(args, uniformStatuses(args.Connectors, connectorSetUpdated)),
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[ConnectorSetState]{}, provider.ErrClientNotConfigured
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	if err != nil {
+		return infer.UpdateResponse[ConnectorSetState]{}, fmt.Errorf("failed to list connectors: %w", err)
+	}
+	existing := indexByID(listResp.Connectors)
+	managed := map[string]bool{}
+	for _, con := range oldState.Connectors {
+		managed[con.ConnectorId] = true
+	}
+
+	wanted := map[string]bool{}
+	statuses := make([]string, 0, len(args.Connectors))
+	for _, con := range args.Connectors {
+		wanted[con.ConnectorId] = true
+
+		found, ok := existing[con.ConnectorId]
+		if !ok {
+			result, err := createSetConnector(ctx, cfg, con, args.TimeoutSeconds)
+			if err != nil {
+				return infer.UpdateResponse[ConnectorSetState]{}, err
+			}
+			statuses = append(statuses, result)
+			continue
+		}
+
+		configBytes, err := buildConnectorConfigBytes(con)
+		if err != nil {
+			return infer.UpdateResponse[ConnectorSetState]{}, err
+		}
+		if found.Type == con.Type && found.Name == con.Name && jsonBytesEqual(found.Config, configBytes) {
+			result := connectorSetUnchanged
+			if !managed[con.ConnectorId] {
+				result = connectorSetAdopted
+			}
+			statuses = append(statuses, result)
+			continue
+		}
+		if !managed[con.ConnectorId] {
+			// Only overwrite connectors the set already owns.
+			return infer.UpdateResponse[ConnectorSetState]{}, fmt.Errorf("connector with id %q %w with a different configuration; import it or delete it first", con.ConnectorId, provider.ErrAlreadyExists)
+		}
+
+		updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+		_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+			Id:        con.ConnectorId,
+			NewType:   con.Type,
+			NewName:   con.Name,
+			NewConfig: configBytes,
+		})
+		cancel()
+		if err != nil {
+			return infer.UpdateResponse[ConnectorSetState]{}, provider.WrapError("update", "connector-set", con.ConnectorId, err)
+		}
+		statuses = append(statuses, connectorSetUpdated)
+	}
+
+	var removed []string
+	for _, con := range oldState.Connectors {
+		if !wanted[con.ConnectorId] {
+			removed = append(removed, con.ConnectorId)
+		}
+	}
+	if err := deleteSetConnectors(ctx, cfg, removed, args.TimeoutSeconds); err != nil {
+		return infer.UpdateResponse[ConnectorSetState]{}, err
+	}
+
+	return infer.UpdateResponse[ConnectorSetState]{
+		Output: connectorSetState(args, statuses),
+	}, nil
+}
+
+// Delete deletes every connector in the set.
+func (r *ConnectorSet) Delete(ctx context.Context, req infer.DeleteRequest[ConnectorSetState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	ids := make([]string, 0, len(req.State.Connectors))
+	for _, con := range req.State.Connectors {
+		ids = append(ids, con.ConnectorId)
+	}
+	if err := deleteSetConnectors(ctx, cfg, ids, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// createSetConnector creates one connector of a set and returns its status.
+// An AlreadyExists response is accepted when the existing connector matches.
+func createSetConnector(ctx context.Context, cfg provider.DexConfig, con ConnectorArgs, timeoutSeconds *int) (string, error) {
+	configBytes, err := buildConnectorConfigBytes(con)
+	if err != nil {
+		return "", err
+	}
+
+	want := &api.Connector{
+		Id:     con.ConnectorId,
+		Type:   con.Type,
+		Name:   con.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{Connector: want})
+	if err != nil {
+		return "", provider.WrapError("create", "connector-set", con.ConnectorId, err)
+	}
+	if resp.AlreadyExists {
+		if err := matchExistingConnector(ctx, cfg, want, timeoutSeconds); err != nil {
+			return "", err
+		}
+		return connectorSetAdopted, nil
+	}
+	return connectorSetCreated, nil
+}
+
+// rollbackSetConnectors makes a best-effort attempt to remove partially created connectors.
+func rollbackSetConnectors(ctx context.Context, cfg provider.DexConfig, ids []string, timeoutSeconds *int) {
+	if err := deleteSetConnectors(ctx, cfg, ids, timeoutSeconds); err != nil {
+		p.GetLogger(ctx).Warningf("failed to roll back connector set: %v", err)
+	}
+}
+
+// deleteSetConnectors deletes the connectors with the given IDs. Connectors
+// that are already gone are skipped.
+func deleteSetConnectors(ctx context.Context, cfg provider.DexConfig, ids []string, timeoutSeconds *int) error {
+	for _, id := range ids {
+		deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+		_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{Id: id})
+		cancel()
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			return provider.WrapError("delete", "connector-set", id, err)
+		}
+		if err := verifyConnectorDeleted(ctx, cfg, id, timeoutSeconds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyConnectorSetDefaults fills in the shared defaults on every connector
+// that does not set them. A rawConfig only receives the redirect URI, and
+// only when it is a JSON object without a redirectURI key.
+func applyConnectorSetDefaults(args *ConnectorSetArgs) {
+	if args.Defaults == nil {
+		return
+	}
+	redirectURI := provider.PtrOr(args.Defaults.RedirectUri, "")
+	for i := range args.Connectors {
+		con := &args.Connectors[i]
+		if con.OIDCConfig != nil {
+			oidc := *con.OIDCConfig
+			if oidc.RedirectUri == "" {
+				oidc.RedirectUri = redirectURI
+			}
+			if len(oidc.Scopes) == 0 && len(args.Defaults.Scopes) > 0 {
+				oidc.Scopes = append([]string(nil), args.Defaults.Scopes...)
+			}
+			con.OIDCConfig = &oidc
+			continue
+		}
+		if redirectURI == "" || con.RawConfig == nil {
+			continue
+		}
+		var raw map[string]any
+		if err := json.Unmarshal([]byte(*con.RawConfig), &raw); err != nil || raw == nil {
+			continue
+		}
+		if _, ok := raw["redirectURI"]; ok {
+			continue
+		}
+		raw["redirectURI"] = redirectURI
+		if data, err := json.Marshal(raw); err == nil {
+			rc := string(data)
+			con.RawConfig = &rc
+		}
+	}
+}
+
+// connectorSetState assembles the output state, pairing statuses with
+// connectors by position.
+func connectorSetState(args ConnectorSetArgs, statuses []string) ConnectorSetState {
+	out := make([]ConnectorSetStatus, len(args.Connectors))
+	for i, con := range args.Connectors {
+		out[i] = ConnectorSetStatus{ConnectorId: con.ConnectorId, Status: statuses[i]}
+	}
+	return ConnectorSetState{ConnectorSetArgs: args, Statuses: out}
+}
+
+// uniformStatuses returns result once per connector, for previews.
+func uniformStatuses(connectors []ConnectorArgs, result string) []string {
+	out := make([]string, len(connectors))
+	for i := range out {
+		out[i] = result
+	}
+	return out
+}
@@ -0,0 +1,411 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+func TestDiffConnectorRawAndTypedConfig(t *testing.T) {
+	typed := ConnectorArgs{
+		ConnectorId: "oidc",
+		Type:        "oidc",
+		Name:        "OIDC",
+		OIDCConfig: &OIDCConfig{
+			Issuer:       "https://idp.example.com",
+			ClientId:     "client",
+			ClientSecret: "secret",
+			RedirectUri:  "https://dex.example.com/callback",
+			Scopes:       []string{"openid", "email"},
+		},
+	}
+	raw := func(config string) ConnectorArgs {
+		args := typed
+		args.OIDCConfig = nil
+		args.RawConfig = &config
+		return args
+	}
+
+	tests := []struct {
+		name        string
+		olds, news  ConnectorArgs
+		wantChanges bool
+	}{
+		{
+			name: "raw to typed",
+			olds: raw(`{
+				"scopes": ["openid", "email"],
+				"redirectURI": "https://dex.example.com/callback",
+				"issuer": "https://idp.example.com",
+				"clientSecret": "secret",
+				"clientID": "client"
+			}`),
+			news: typed,
+		},
+		{
+			name: "typed to raw",
+			olds: typed,
+			news: raw(`{"issuer":"https://idp.example.com","clientID":"client","clientSecret":"secret","redirectURI":"https://dex.example.com/callback","scopes":["openid","email"]}`),
+		},
+		{
+			name:        "raw to typed with a different issuer",
+			olds:        raw(`{"issuer":"https://other.example.com","clientID":"client","clientSecret":"secret","redirectURI":"https://dex.example.com/callback","scopes":["openid","email"]}`),
+			news:        typed,
+			wantChanges: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffConnector(tt.olds, tt.news)
+			if diff.HasChanges != tt.wantChanges {
+				t.Errorf("HasChanges = %v, want %v (diff %+v)", diff.HasChanges, tt.wantChanges, diff.DetailedDiff)
+			}
+		})
+	}
+}
+
+func TestCreateConnectorRace(t *testing.T) {
+	dex := &fakeDex{}
+	cfg := provider.DexConfig{Client: dex}
+	config := `{"issuer":"https://idp.example.com","clientID":"client"}`
+	args := ConnectorArgs{ConnectorId: "oidc", Type: "oidc", Name: "OIDC", RawConfig: &config}
+
+	// Both creates of a concurrent (or retried) apply succeed; the loser
+	// finds the winner's connector and accepts it because it matches.
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = createConnector(context.Background(), cfg, args)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("create %d = %v, want nil", i, err)
+		}
+	}
+	if len(dex.connectors) != 1 {
+		t.Fatalf("Dex has %d connectors, want 1", len(dex.connectors))
+	}
+
+	// Key order and whitespace do not count as a different config.
+	reordered := `{ "clientID": "client", "issuer": "https://idp.example.com" }`
+	same := args
+	same.RawConfig = &reordered
+	if err := createConnector(context.Background(), cfg, same); err != nil {
+		t.Errorf("create with matching config = %v, want nil", err)
+	}
+
+	otherConfig := `{"issuer":"https://other.example.com","clientID":"client"}`
+	other := args
+	other.RawConfig = &otherConfig
+	err := createConnector(context.Background(), cfg, other)
+	if !errors.Is(err, provider.ErrAlreadyExists) {
+		t.Errorf("create with differing config = %v, want ErrAlreadyExists", err)
+	}
+}
+
+func TestDecodeConnectorStripsServerDefaults(t *testing.T) {
+	args := ConnectorArgs{
+		ConnectorId: "gh",
+		Type:        "github",
+		Name:        "GitHub",
+	}
+	config := `{"clientID":"id","clientSecret":"secret"}`
+	args.RawConfig = &config
+
+	// Dex added loadAllGroups and useLoginAsID on its own.
+	stored := `{"clientID":"id","clientSecret":"secret","loadAllGroups":false,"useLoginAsID":false}`
+	read, _, err := decodeConnector(&api.Connector{Id: "gh", Type: "github", Name: "GitHub", Config: []byte(stored)})
+	if err != nil {
+		t.Fatalf("decodeConnector() = %v", err)
+	}
+	if want := `{"clientID":"id","clientSecret":"secret"}`; provider.PtrOr(read.RawConfig, "") != want {
+		t.Errorf("rawConfig = %s, want %s", provider.PtrOr(read.RawConfig, ""), want)
+	}
+	if diff := diffConnector(read, args); diff.HasChanges {
+		t.Errorf("server defaults caused a diff: %+v", diff.DetailedDiff)
+	}
+}
+
+func TestCheckRawConfigKeyCase(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		normalize bool
+		want      string
+		wantFails int
+	}{
+		{
+			name: "Dex casing",
+			raw:  `{"clientID":"id","redirectURI":"https://dex.example.com/callback"}`,
+			want: `{"clientID":"id","redirectURI":"https://dex.example.com/callback"}`,
+		},
+		{
+			name:      "camelCase rejected",
+			raw:       `{"clientId":"id","redirectUri":"https://dex.example.com/callback"}`,
+			want:      `{"clientId":"id","redirectUri":"https://dex.example.com/callback"}`,
+			wantFails: 2,
+		},
+		{
+			name:      "camelCase normalized",
+			raw:       `{"clientId":"id","redirectUrl":"https://dex.example.com/callback","bindPw":"pw"}`,
+			normalize: true,
+			want:      `{"bindPW":"pw","clientID":"id","redirectURI":"https://dex.example.com/callback"}`,
+		},
+		{
+			name:      "both spellings",
+			raw:       `{"clientId":"a","clientID":"b"}`,
+			normalize: true,
+			want:      `{"clientId":"a","clientID":"b"}`,
+			wantFails: 1,
+		},
+		{
+			name: "invalid JSON is left to other checks",
+			raw:  `{"clientId":`,
+			want: `{"clientId":`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, failures := checkRawConfigKeyCase(tt.raw, tt.normalize)
+			if got != tt.want {
+				t.Errorf("rawConfig = %s, want %s", got, tt.want)
+			}
+			if len(failures) != tt.wantFails {
+				t.Errorf("failures = %+v, want %d", failures, tt.wantFails)
+			}
+		})
+	}
+}
+
+func TestDecodeConnectorTrimsStoredConfig(t *testing.T) {
+	bom := "\xef\xbb\xbf"
+	tests := []struct {
+		name       string
+		conType    string
+		config     string
+		wantRaw    string
+		wantOIDC   bool
+		wantIssuer string
+	}{
+		{name: "oidc with leading whitespace", conType: "oidc", config: "\n  {\"issuer\":\"https://idp.example.com\"}\n", wantOIDC: true, wantIssuer: "https://idp.example.com"},
+		{name: "oidc with BOM", conType: "oidc", config: bom + `{"issuer":"https://idp.example.com"}`, wantOIDC: true, wantIssuer: "https://idp.example.com"},
+		{name: "raw with BOM and whitespace", conType: "ldap", config: bom + " {\"host\": \"ldap.example.com\"} \n", wantRaw: `{"host":"ldap.example.com"}`},
+		{name: "unparseable oidc falls back to rawConfig", conType: "oidc", config: `{"issuer":`, wantRaw: `{"issuer":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, _, err := decodeConnector(&api.Connector{Id: "c", Type: tt.conType, Name: "C", Config: []byte(tt.config)})
+			if err != nil {
+				t.Fatalf("decodeConnector() = %v", err)
+			}
+			if tt.wantOIDC {
+				if args.OIDCConfig == nil || args.OIDCConfig.Issuer != tt.wantIssuer {
+					t.Errorf("oidcConfig = %+v, want issuer %q", args.OIDCConfig, tt.wantIssuer)
+				}
+				return
+			}
+			if got := provider.PtrOr(args.RawConfig, ""); got != tt.wantRaw {
+				t.Errorf("rawConfig = %q, want %q", got, tt.wantRaw)
+			}
+		})
+	}
+}
+
+func TestAdoptExistingConnector(t *testing.T) {
+	// Dex added loadAllGroups and useLoginAsID when it stored the connector.
+	dex := &fakeDex{connectors: []*api.Connector{{
+		Id:     "gh",
+		Type:   "github",
+		Name:   "GitHub",
+		Config: []byte(`{"clientSecret":"secret","loadAllGroups":false,"clientID":"id","useLoginAsID":false}`),
+	}}}
+	cfg := provider.DexConfig{Client: dex}
+
+	want := &api.Connector{Id: "gh", Type: "github", Name: "GitHub", Config: []byte(`{"clientID":"id","clientSecret":"secret"}`)}
+	if err := adoptExistingConnector(context.Background(), cfg, want, nil); err != nil {
+		t.Errorf("adopt matching connector = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name string
+		want *api.Connector
+	}{
+		{"different config", &api.Connector{Id: "gh", Type: "github", Name: "GitHub", Config: []byte(`{"clientID":"other","clientSecret":"secret"}`)}},
+		{"different name", &api.Connector{Id: "gh", Type: "github", Name: "GitHub Enterprise", Config: want.Config}},
+		{"different type", &api.Connector{Id: "gh", Type: "oauth", Name: "GitHub", Config: want.Config}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := adoptExistingConnector(context.Background(), cfg, tt.want, nil)
+			if !errors.Is(err, provider.ErrAlreadyExists) {
+				t.Fatalf("adopt = %v, want ErrAlreadyExists", err)
+			}
+			if !strings.Contains(err.Error(), `"gh"`) {
+				t.Errorf("error %q does not name the connector", err)
+			}
+		})
+	}
+}
+
+func TestDiffConnectorTimeoutSeconds(t *testing.T) {
+	ten, thirty := 10, 30
+	config := `{"issuer":"https://idp.example.com"}`
+	base := ConnectorArgs{ConnectorId: "oidc", Type: "oidc", Name: "OIDC", RawConfig: &config}
+
+	tests := []struct {
+		name     string
+		old, new *int
+		wantDiff bool
+	}{
+		{name: "unset to set", old: nil, new: &ten, wantDiff: true},
+		{name: "set to unset", old: &ten, new: nil, wantDiff: true},
+		{name: "changed", old: &ten, new: &thirty, wantDiff: true},
+		{name: "unchanged", old: &ten, new: &ten},
+		{name: "both unset", old: nil, new: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			olds, news := base, base
+			olds.TimeoutSeconds = tt.old
+			news.TimeoutSeconds = tt.new
+			diff := diffConnector(olds, news)
+			d, ok := diff.DetailedDiff["timeoutSeconds"]
+			if ok != tt.wantDiff || diff.HasChanges != tt.wantDiff {
+				t.Fatalf("diff = %+v, want timeoutSeconds change %v", diff.DetailedDiff, tt.wantDiff)
+			}
+			if ok && d.Kind != p.Update {
+				t.Errorf("timeoutSeconds diff kind = %q, want %q", d.Kind, p.Update)
+			}
+			if diff.DeleteBeforeReplace {
+				t.Error("timeoutSeconds change forces a replacement")
+			}
+		})
+	}
+}
+
+func TestPreserveUnmanagedConfigKeysOnRename(t *testing.T) {
+	old := ConnectorArgs{
+		ConnectorId: "oidc",
+		Type:        "oidc",
+		Name:        "OIDC",
+		OIDCConfig: &OIDCConfig{
+			Issuer:       "https://idp.example.com",
+			ClientId:     "client",
+			ClientSecret: "secret",
+			RedirectUri:  "https://dex.example.com/callback",
+			Extra:        map[string]any{"hostedDomains": []any{"example.com"}},
+		},
+	}
+	previous, err := buildConnectorConfigBytes(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Dex holds the applied config plus a key set out of band.
+	var stored map[string]any
+	if err := json.Unmarshal(previous, &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored["providerDiscoveryOverrides"] = map[string]any{"tokenURL": "https://idp.example.com/token"}
+	storedBytes, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	renamed := old
+	renamed.Name = "Company SSO"
+	desired, err := buildConnectorConfigBytes(renamed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(preserveUnmanagedConfigKeys(desired, storedBytes, previous), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got["providerDiscoveryOverrides"], stored["providerDiscoveryOverrides"]) {
+		t.Errorf("out-of-band key = %v, want it kept", got["providerDiscoveryOverrides"])
+	}
+	if got["issuer"] != "https://idp.example.com" {
+		t.Errorf("issuer = %v, want the program's value", got["issuer"])
+	}
+
+	// A key the program removed from extra is dropped, not carried over.
+	cleared := renamed
+	oidc := *old.OIDCConfig
+	oidc.Extra = nil
+	cleared.OIDCConfig = &oidc
+	desired, err = buildConnectorConfigBytes(cleared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	if err := json.Unmarshal(preserveUnmanagedConfigKeys(desired, storedBytes, previous), &got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["hostedDomains"]; ok {
+		t.Error("hostedDomains removed from extra was kept")
+	}
+	if _, ok := got["providerDiscoveryOverrides"]; !ok {
+		t.Error("out-of-band key was dropped")
+	}
+}
+
+func TestDiffConnectorTypeChangeReplaces(t *testing.T) {
+	oidc := ConnectorArgs{
+		ConnectorId: "sso",
+		Type:        "oidc",
+		Name:        "SSO",
+		OIDCConfig: &OIDCConfig{
+			Issuer:       "https://idp.example.com",
+			ClientId:     "client",
+			ClientSecret: "secret",
+			RedirectUri:  "https://dex.example.com/callback",
+		},
+	}
+	oauthConfig := `{"clientID":"client","clientSecret":"secret","authorizationURL":"https://idp.example.com/authorize"}`
+	oauth := oidc
+	oauth.Type = "oauth"
+	oauth.OIDCConfig = nil
+	oauth.RawConfig = &oauthConfig
+
+	diff := diffConnector(oidc, oauth)
+	if got := diff.DetailedDiff["type"].Kind; got != p.UpdateReplace {
+		t.Errorf("type diff kind = %q, want %q", got, p.UpdateReplace)
+	}
+	if !diff.DeleteBeforeReplace {
+		t.Error("DeleteBeforeReplace = false, want true")
+	}
+	if err := checkImmutableFields("Connector", oidc, oauth); err == nil || !strings.Contains(err.Error(), "type") {
+		t.Errorf("checkImmutableFields() = %v, want an error naming type", err)
+	}
+
+	// Name and config changes stay in place.
+	renamed := oidc
+	renamed.Name = "Company SSO"
+	reissued := oidc
+	cfg := *oidc.OIDCConfig
+	cfg.Issuer = "https://other.example.com"
+	reissued.OIDCConfig = &cfg
+	for name, news := range map[string]ConnectorArgs{"name": renamed, "oidcConfig": reissued} {
+		diff := diffConnector(oidc, news)
+		if got := diff.DetailedDiff[name].Kind; got != p.Update {
+			t.Errorf("%s diff kind = %q, want %q", name, got, p.Update)
+		}
+		if diff.DeleteBeforeReplace {
+			t.Errorf("%s change forces a replacement", name)
+		}
+	}
+}
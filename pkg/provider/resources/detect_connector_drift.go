@@ -0,0 +1,123 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// detectConnectorDrift - Compares one connector's config in Dex with an expected config
+// ============================================================================
+
+// DetectConnectorDriftArgs defines inputs for the detectConnectorDrift function.
+type DetectConnectorDriftArgs struct {
+	ConnectorId string         `pulumi:"connectorId"`
+	Expected    map[string]any `pulumi:"expected"`
+}
+
+// DetectConnectorDriftResult defines outputs for the detectConnectorDrift function.
+type DetectConnectorDriftResult struct {
+	Found    bool           `pulumi:"found"`
+	Changes  []ConfigChange `pulumi:"changes"`
+	HasDrift bool           `pulumi:"hasDrift"`
+}
+
+// DetectConnectorDrift reports how a connector's config in Dex differs from an expected config.
+type DetectConnectorDrift struct{}
+
+// Annotate provides schema metadata.
+func (f *DetectConnectorDrift) Annotate(a infer.Annotator) {
+	a.Describe(f, "Compares the config of a connector in Dex with an expected config and returns the keys that differ, e.g. for audit pipelines that look for connectors edited outside Pulumi. "+
+		"Secret values (clientSecret, bindPW, serviceAccountJSON), server-added defaults, and the provider's ignoreConfigKeys are not compared, "+
+		"and commonly miscased keys such as clientId are read as their Dex spelling. Read-only.")
+}
+
+// Annotate provides schema metadata for DetectConnectorDriftArgs.
+func (a *DetectConnectorDriftArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.ConnectorId, "ID of the connector to check.")
+	an.Describe(&a.Expected, "The expected connector config, as Dex stores it.")
+}
+
+// Annotate provides schema metadata for DetectConnectorDriftResult.
+func (r *DetectConnectorDriftResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Found, "Whether the connector exists in Dex. A missing connector counts as drift.")
+	a.Describe(&r.Changes, "Differences from the live config (old) to the expected config (new), sorted by path, in the diffConnectorConfig format.")
+	a.Describe(&r.HasDrift, "Whether the connector is missing or any difference was found.")
+}
+
+// Invoke looks up the connector in Dex and diffs its config against the expected one.
+func (f *DetectConnectorDrift) Invoke(ctx context.Context, req infer.FunctionRequest[DetectConnectorDriftArgs]) (infer.FunctionResponse[DetectConnectorDriftResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[DetectConnectorDriftResult]{}, provider.ErrClientNotConfigured
+	}
+	if req.Input.ConnectorId == "" {
+		return infer.FunctionResponse[DetectConnectorDriftResult]{}, fmt.Errorf("connectorId is required")
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.Input.ConnectorId, nil)
+	if err != nil {
+		return infer.FunctionResponse[DetectConnectorDriftResult]{}, err
+	}
+	if found == nil {
+		return infer.FunctionResponse[DetectConnectorDriftResult]{
+			Output: DetectConnectorDriftResult{Changes: []ConfigChange{}, HasDrift: true},
+		}, nil
+	}
+
+	var live map[string]any
+	if err := json.Unmarshal(stripConnectorDefaults(found.Type, trimConfigBytes(found.Config)), &live); err != nil {
+		return infer.FunctionResponse[DetectConnectorDriftResult]{}, fmt.Errorf("connector %q has a config that is not a JSON object: %w", found.Id, err)
+	}
+	// Round-trip through JSON so numbers and nested values decode the same
+	// way on both sides.
+	var expected map[string]any
+	data, err := json.Marshal(req.Input.Expected)
+	if err != nil {
+		return infer.FunctionResponse[DetectConnectorDriftResult]{}, fmt.Errorf("failed to encode expected config: %w", err)
+	}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return infer.FunctionResponse[DetectConnectorDriftResult]{}, fmt.Errorf("failed to decode expected config: %w", err)
+	}
+
+	live = canonicalConfigKeyCase(live)
+	expected = canonicalConfigKeyCase(expected)
+	for _, config := range []map[string]any{live, expected} {
+		for key := range secretConfigKeys {
+			delete(config, key)
+		}
+		for _, key := range cfg.IgnoreConfigKeys {
+			delete(config, key)
+		}
+	}
+
+	changes := diffConfigMaps(live, expected)
+	return infer.FunctionResponse[DetectConnectorDriftResult]{
+		Output: DetectConnectorDriftResult{
+			Found:    true,
+			Changes:  changes,
+			HasDrift: len(changes) > 0,
+		},
+	}, nil
+}
+
+// canonicalConfigKeyCase returns a copy of config with top-level keys listed
+// in dexConfigKeyCase renamed to the casing Dex expects. A miscased key is
+// dropped when its Dex spelling is also set, since Dex ignores it.
+func canonicalConfigKeyCase(config map[string]any) map[string]any {
+	out := make(map[string]any, len(config))
+	for k, v := range config {
+		if want, ok := dexConfigKeyCase[k]; ok {
+			if _, set := config[want]; !set {
+				out[want] = v
+			}
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"context"
+	"sync"
+
+	api "github.com/dexidp/dex/api/v2"
+	"google.golang.org/grpc"
+)
+
+// fakeDex is an in-memory api.DexClient holding connectors and clients.
+// Methods that tests do not need are left to the embedded nil interface and
+// panic when called.
+type fakeDex struct {
+	api.DexClient
+
+	mu         sync.Mutex
+	connectors []*api.Connector
+	clients    []*api.Client
+}
+
+func (f *fakeDex) ListConnectors(ctx context.Context, in *api.ListConnectorReq, opts ...grpc.CallOption) (*api.ListConnectorResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]*api.Connector, len(f.connectors))
+	copy(out, f.connectors)
+	return &api.ListConnectorResp{Connectors: out}, nil
+}
+
+func (f *fakeDex) CreateConnector(ctx context.Context, in *api.CreateConnectorReq, opts ...grpc.CallOption) (*api.CreateConnectorResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, con := range f.connectors {
+		if con.Id == in.Connector.Id {
+			return &api.CreateConnectorResp{AlreadyExists: true}, nil
+		}
+	}
+	f.connectors = append(f.connectors, in.Connector)
+	return &api.CreateConnectorResp{}, nil
+}
+
+func (f *fakeDex) DeleteConnector(ctx context.Context, in *api.DeleteConnectorReq, opts ...grpc.CallOption) (*api.DeleteConnectorResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, con := range f.connectors {
+		if con.Id == in.Id {
+			f.connectors = append(f.connectors[:i], f.connectors[i+1:]...)
+			return &api.DeleteConnectorResp{}, nil
+		}
+	}
+	return &api.DeleteConnectorResp{NotFound: true}, nil
+}
+
+func (f *fakeDex) ListClients(ctx context.Context, in *api.ListClientReq, opts ...grpc.CallOption) (*api.ListClientResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]*api.ClientInfo, 0, len(f.clients))
+	for _, cl := range f.clients {
+		out = append(out, &api.ClientInfo{
+			Id:           cl.Id,
+			RedirectUris: cl.RedirectUris,
+			TrustedPeers: cl.TrustedPeers,
+			Public:       cl.Public,
+			Name:         cl.Name,
+			LogoUrl:      cl.LogoUrl,
+		})
+	}
+	return &api.ListClientResp{Clients: out}, nil
+}
+
+func (f *fakeDex) CreateClient(ctx context.Context, in *api.CreateClientReq, opts ...grpc.CallOption) (*api.CreateClientResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, cl := range f.clients {
+		if cl.Id == in.Client.Id {
+			return &api.CreateClientResp{AlreadyExists: true}, nil
+		}
+	}
+	f.clients = append(f.clients, in.Client)
+	return &api.CreateClientResp{Client: in.Client}, nil
+}
+
+func (f *fakeDex) UpdateClient(ctx context.Context, in *api.UpdateClientReq, opts ...grpc.CallOption) (*api.UpdateClientResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, cl := range f.clients {
+		if cl.Id == in.Id {
+			if in.Name != "" {
+				cl.Name = in.Name
+			}
+			if in.RedirectUris != nil {
+				cl.RedirectUris = in.RedirectUris
+			}
+			return &api.UpdateClientResp{}, nil
+		}
+	}
+	return &api.UpdateClientResp{NotFound: true}, nil
+}
+
+func (f *fakeDex) DeleteClient(ctx context.Context, in *api.DeleteClientReq, opts ...grpc.CallOption) (*api.DeleteClientResp, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, cl := range f.clients {
+		if cl.Id == in.Id {
+			f.clients = append(f.clients[:i], f.clients[i+1:]...)
+			return &api.DeleteClientResp{}, nil
+		}
+	}
+	return &api.DeleteClientResp{NotFound: true}, nil
+}
+
+// client returns the client with the given ID, or nil.
+func (f *fakeDex) client(id string) *api.Client {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, cl := range f.clients {
+		if cl.Id == id {
+			return cl
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// diffArgs compares two input structs field by field (keyed by their pulumi
+// tag) and reports changed fields as in-place updates, except for the fields
+// listed in replaceFields, which are reported as replacements.
+//
+// Nil and empty slices/maps are treated as equal, so an unset collection does
+// not diff against an empty one returned from Dex.
+//
+// Every resource in this provider is keyed on a user-chosen ID that Dex will
+// not allow twice, so replacements always delete the old object first.
+func diffArgs(olds, news any, replaceFields ...string) infer.DiffResponse {
+	replace := make(map[string]bool, len(replaceFields))
+	for _, f := range replaceFields {
+		replace[f] = true
+	}
+
+	diff := map[string]p.PropertyDiff{}
+	collectFieldDiffs(reflect.ValueOf(olds), reflect.ValueOf(news), "", replace, diff)
+
+	return infer.DiffResponse{
+		DeleteBeforeReplace: hasReplacement(diff),
+		HasChanges:          len(diff) > 0,
+		DetailedDiff:        diff,
+	}
+}
+
+// hasReplacement reports whether any property diff forces a replacement.
+func hasReplacement(diff map[string]p.PropertyDiff) bool {
+	for _, d := range diff {
+		switch d.Kind {
+		case p.AddReplace, p.DeleteReplace, p.UpdateReplace:
+			return true
+		}
+	}
+	return false
+}
+
+// collectFieldDiffs adds the changed fields of olds and news to diff, named
+// by their pulumi tags under prefix. A nested struct field with a replace
+// field below it, e.g. "connector.connectorId", is compared field by field.
+func collectFieldDiffs(olds, news reflect.Value, prefix string, replace map[string]bool, diff map[string]p.PropertyDiff) {
+	t := olds.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectFieldDiffs(olds.Field(i), news.Field(i), prefix, replace, diff)
+			continue
+		}
+		tag, _, _ := strings.Cut(field.Tag.Get("pulumi"), ",")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + tag
+		ov, nv := olds.Field(i), news.Field(i)
+		if field.Type.Kind() == reflect.Struct && hasNestedField(replace, name) {
+			collectFieldDiffs(ov, nv, name+".", replace, diff)
+			continue
+		}
+		if valuesEqual(ov, nv) {
+			continue
+		}
+		kind := p.Update
+		if replace[name] {
+			kind = p.UpdateReplace
+		}
+		diff[name] = p.PropertyDiff{Kind: kind}
+	}
+}
+
+// hasNestedField reports whether fields has a path below name.
+func hasNestedField(fields map[string]bool, name string) bool {
+	for f := range fields {
+		if strings.HasPrefix(f, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func valuesEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	if m, ok := a.Interface().(map[string]any); ok {
+		return freeformEqual(m, b.Interface().(map[string]any))
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// freeformEqual compares free-form maps such as extraOidc semantically: both
+// sides are round-tripped through JSON, so an integer from the program equals
+// the float64 Dex's config decodes to, and nested values compare by content.
+func freeformEqual(a, b map[string]any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return jsonBytesEqual(ja, jb)
+}
+
+// diffResource is diffArgs with the replacement fields taken from
+// provider.ImmutableFields for resource.
+func diffResource(resource string, olds, news any) infer.DiffResponse {
+	return diffArgs(olds, news, provider.ImmutableFields[resource]...)
+}
+
+// checkImmutableFields returns an error naming every field of resource listed
+// in provider.ImmutableFields whose value differs between olds and news. Diff
+// already turns such changes into replacements; this guards Update against
+// applying them in place regardless.
+func checkImmutableFields(resource string, olds, news any) error {
+	var changed []string
+	for name, d := range diffResource(resource, olds, news).DetailedDiff {
+		if d.Kind == p.UpdateReplace {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+	return fmt.Errorf("%s cannot be changed (would require replace)", strings.Join(changed, ", "))
+}
+
+// fieldDiffKind returns the diff kind for a changed property of resource:
+// a replacement when it is listed in provider.ImmutableFields, otherwise an
+// in-place update. It is for resources whose Diff is not built on diffArgs.
+func fieldDiffKind(resource, property string) p.DiffKind {
+	if provider.IsImmutableField(resource, property) {
+		return p.UpdateReplace
+	}
+	return p.Update
+}
+
+// readSecret returns the value of a secret field a Read reports as input.
+// With writeOnlySecrets, the value from Dex is not read back once the object
+// has state; the last-applied value is kept. When Dex omits the secret or
+// returns it redacted, there is nothing to compare, so the program's input
+// (or, on refresh without inputs, the value in state) is kept. Otherwise
+// Dex's value is used, so a secret changed in Dex shows up as drift, except
+// that while it still equals the value in state the previous input is kept,
+// so refresh does not record a change for a secret that was not changed in
+// Dex. Diff then only reports the secret when the program's input differs
+// from state.
+func readSecret(cfg provider.DexConfig, read, state, input string) string {
+	if provider.PtrOr(cfg.WriteOnlySecrets, false) && state != "" {
+		return state
+	}
+	if read == "" || isRedactedSecret(read) {
+		if input != "" {
+			return input
+		}
+		return state
+	}
+	if read == state && input != "" {
+		return input
+	}
+	return read
+}
+
+// isRedactedSecret reports whether a secret read from Dex is a placeholder
+// rather than the stored value, as returned by Dex builds or proxies that
+// mask secrets.
+func isRedactedSecret(s string) bool {
+	return s == redactedValue || strings.Trim(s, "*") == ""
+}
+
+// readSecretPtr is readSecret for optional secret fields.
+func readSecretPtr(cfg provider.DexConfig, read, state, input *string) *string {
+	s := readSecret(cfg, provider.PtrOr(read, ""), provider.PtrOr(state, ""), provider.PtrOr(input, ""))
+	if s == "" {
+		return read
+	}
+	return &s
+}
+
+// restoreSecretConfigKeys copies the secret keys of a connector config from
+// the last-applied config when writeOnlySecrets is set, so free-form configs
+// follow the same rule as readSecret.
+func restoreSecretConfigKeys(cfg provider.DexConfig, config, applied map[string]any) {
+	if !provider.PtrOr(cfg.WriteOnlySecrets, false) || applied == nil {
+		return
+	}
+	restoreIgnoredConfigKeys(mapKeys(secretConfigKeys), config, applied)
+}
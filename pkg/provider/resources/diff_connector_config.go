@@ -0,0 +1,172 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// diffConnectorConfig - Structured diff between two connector configs
+// ============================================================================
+
+// Kinds of ConfigChange.
+const (
+	configChangeAdded   = "added"
+	configChangeRemoved = "removed"
+	configChangeChanged = "changed"
+)
+
+// ConfigChange describes one difference between two connector configs.
+type ConfigChange struct {
+	Path string  `pulumi:"path"`
+	Kind string  `pulumi:"kind"`
+	Old  *string `pulumi:"old,optional"`
+	New  *string `pulumi:"new,optional"`
+}
+
+// DiffConnectorConfigArgs defines inputs for the diffConnectorConfig function.
+type DiffConnectorConfigArgs struct {
+	Old string `pulumi:"old"`
+	New string `pulumi:"new"`
+}
+
+// DiffConnectorConfigResult defines outputs for the diffConnectorConfig function.
+type DiffConnectorConfigResult struct {
+	Changes    []ConfigChange `pulumi:"changes"`
+	HasChanges bool           `pulumi:"hasChanges"`
+}
+
+// DiffConnectorConfig compares two connector config JSON documents.
+type DiffConnectorConfig struct{}
+
+// Annotate provides schema metadata.
+func (f *DiffConnectorConfig) Annotate(a infer.Annotator) {
+	a.Describe(f, "Compares two connector config JSON documents and returns the added, removed, and changed keys, with secret values redacted. Does not call Dex; useful for reviewing connector changes in CI.")
+}
+
+// Annotate provides schema metadata for ConfigChange.
+func (c *ConfigChange) Annotate(a infer.Annotator) {
+	a.Describe(&c.Path, "Path of the changed value, e.g. 'claimMapping.groups' or 'scopes[1]'.")
+	a.Describe(&c.Kind, "One of 'added', 'removed', or 'changed'.")
+	a.Describe(&c.Old, "Previous value as JSON. Unset for added keys.")
+	a.Describe(&c.New, "New value as JSON. Unset for removed keys.")
+}
+
+// Annotate provides schema metadata for DiffConnectorConfigArgs.
+func (a *DiffConnectorConfigArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.Old, "The original connector config JSON.")
+	an.Describe(&a.New, "The new connector config JSON.")
+}
+
+// Annotate provides schema metadata for DiffConnectorConfigResult.
+func (r *DiffConnectorConfigResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Changes, "The differences, sorted by path.")
+	a.Describe(&r.HasChanges, "Whether any difference was found.")
+}
+
+// Invoke parses both configs and diffs them.
+func (f *DiffConnectorConfig) Invoke(ctx context.Context, req infer.FunctionRequest[DiffConnectorConfigArgs]) (infer.FunctionResponse[DiffConnectorConfigResult], error) {
+	var olds, news map[string]any
+	if err := json.Unmarshal(trimConfigBytes([]byte(req.Input.Old)), &olds); err != nil {
+		return infer.FunctionResponse[DiffConnectorConfigResult]{}, fmt.Errorf("old must be a JSON object: %w", err)
+	}
+	if err := json.Unmarshal(trimConfigBytes([]byte(req.Input.New)), &news); err != nil {
+		return infer.FunctionResponse[DiffConnectorConfigResult]{}, fmt.Errorf("new must be a JSON object: %w", err)
+	}
+
+	changes := diffConfigMaps(olds, news)
+	return infer.FunctionResponse[DiffConnectorConfigResult]{
+		Output: DiffConnectorConfigResult{
+			Changes:    changes,
+			HasChanges: len(changes) > 0,
+		},
+	}, nil
+}
+
+// diffConfigMaps returns the differences between two decoded connector
+// configs, sorted by path. Nested maps and slices are compared element-wise.
+// Values under secret keys are reported as redacted, but their changes are
+// still listed.
+func diffConfigMaps(olds, news map[string]any) []ConfigChange {
+	changes := []ConfigChange{}
+	diffConfigValues("", RedactConfig(olds), RedactConfig(news), olds, news, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// diffConfigValues walks raw values to detect changes and reports the
+// corresponding redacted values.
+func diffConfigValues(path string, redactedOld, redactedNew, olds, news any, changes *[]ConfigChange) {
+	oldMap, oldIsMap := olds.(map[string]any)
+	newMap, newIsMap := news.(map[string]any)
+	if oldIsMap && newIsMap {
+		rOld, _ := redactedOld.(map[string]any)
+		rNew, _ := redactedNew.(map[string]any)
+		for k, ov := range oldMap {
+			p := joinConfigPath(path, k)
+			nv, ok := newMap[k]
+			if !ok {
+				*changes = append(*changes, ConfigChange{Path: p, Kind: configChangeRemoved, Old: configJSON(rOld[k])})
+				continue
+			}
+			diffConfigValues(p, rOld[k], rNew[k], ov, nv, changes)
+		}
+		for k := range newMap {
+			if _, ok := oldMap[k]; !ok {
+				*changes = append(*changes, ConfigChange{Path: joinConfigPath(path, k), Kind: configChangeAdded, New: configJSON(rNew[k])})
+			}
+		}
+		return
+	}
+
+	oldSlice, oldIsSlice := olds.([]any)
+	newSlice, newIsSlice := news.([]any)
+	if oldIsSlice && newIsSlice {
+		rOld, _ := redactedOld.([]any)
+		rNew, _ := redactedNew.([]any)
+		for i := 0; i < len(oldSlice) || i < len(newSlice); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(newSlice):
+				*changes = append(*changes, ConfigChange{Path: p, Kind: configChangeRemoved, Old: configJSON(rOld[i])})
+			case i >= len(oldSlice):
+				*changes = append(*changes, ConfigChange{Path: p, Kind: configChangeAdded, New: configJSON(rNew[i])})
+			default:
+				diffConfigValues(p, rOld[i], rNew[i], oldSlice[i], newSlice[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(olds, news) {
+		*changes = append(*changes, ConfigChange{
+			Path: path,
+			Kind: configChangeChanged,
+			Old:  configJSON(redactedOld),
+			New:  configJSON(redactedNew),
+		})
+	}
+}
+
+// joinConfigPath appends key to a dotted config path.
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// configJSON renders a decoded config value as JSON for display.
+func configJSON(v any) *string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	s := string(b)
+	return &s
+}
@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffConfigMaps(t *testing.T) {
+	str := func(s string) *string { return &s }
+	decode := func(s string) map[string]any {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			t.Fatalf("decode %s: %v", s, err)
+		}
+		return m
+	}
+
+	tests := []struct {
+		name string
+		olds string
+		news string
+		want []ConfigChange
+	}{
+		{
+			name: "equal",
+			olds: `{"issuer":"https://idp.example.com","scopes":["openid"]}`,
+			news: `{"scopes":["openid"],"issuer":"https://idp.example.com"}`,
+			want: []ConfigChange{},
+		},
+		{
+			name: "added, removed, and changed keys",
+			olds: `{"issuer":"https://a.example.com","getUserInfo":true}`,
+			news: `{"issuer":"https://b.example.com","userNameKey":"email"}`,
+			want: []ConfigChange{
+				{Path: "getUserInfo", Kind: configChangeRemoved, Old: str(`true`)},
+				{Path: "issuer", Kind: configChangeChanged, Old: str(`"https://a.example.com"`), New: str(`"https://b.example.com"`)},
+				{Path: "userNameKey", Kind: configChangeAdded, New: str(`"email"`)},
+			},
+		},
+		{
+			name: "nested maps",
+			olds: `{"claimMapping":{"groups":"groups","email":"mail"}}`,
+			news: `{"claimMapping":{"groups":"roles","email":"mail"}}`,
+			want: []ConfigChange{
+				{Path: "claimMapping.groups", Kind: configChangeChanged, Old: str(`"groups"`), New: str(`"roles"`)},
+			},
+		},
+		{
+			name: "slices",
+			olds: `{"scopes":["openid","email"]}`,
+			news: `{"scopes":["openid","profile","groups"]}`,
+			want: []ConfigChange{
+				{Path: "scopes[1]", Kind: configChangeChanged, Old: str(`"email"`), New: str(`"profile"`)},
+				{Path: "scopes[2]", Kind: configChangeAdded, New: str(`"groups"`)},
+			},
+		},
+		{
+			name: "maps inside slices",
+			olds: `{"orgs":[{"name":"acme","teams":["a"]}]}`,
+			news: `{"orgs":[{"name":"acme","teams":["b"]}]}`,
+			want: []ConfigChange{
+				{Path: "orgs[0].teams[0]", Kind: configChangeChanged, Old: str(`"a"`), New: str(`"b"`)},
+			},
+		},
+		{
+			name: "secrets are redacted but still reported",
+			olds: `{"clientSecret":"old"}`,
+			news: `{"clientSecret":"new"}`,
+			want: []ConfigChange{
+				{Path: "clientSecret", Kind: configChangeChanged, Old: str(`"[REDACTED]"`), New: str(`"[REDACTED]"`)},
+			},
+		},
+		{
+			name: "type change",
+			olds: `{"scopes":"openid"}`,
+			news: `{"scopes":["openid"]}`,
+			want: []ConfigChange{
+				{Path: "scopes", Kind: configChangeChanged, Old: str(`"openid"`), New: str(`["openid"]`)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffConfigMaps(decode(tt.olds), decode(tt.news))
+			if !reflect.DeepEqual(got, tt.want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(tt.want)
+				t.Errorf("diffConfigMaps() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
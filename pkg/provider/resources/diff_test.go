@@ -0,0 +1,174 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+func TestReplacementDeletesBeforeCreate(t *testing.T) {
+	secret := "s"
+	otherSecret := "t"
+	config := `{"issuer":"https://idp.example.com"}`
+	connector := ConnectorArgs{ConnectorId: "oidc", Type: "oidc", Name: "OIDC", RawConfig: &config}
+	client := ClientArgs{ClientId: "app", Name: "App", Secret: &secret}
+	github := GitHubConnectorArgs{ConnectorId: "gh", Name: "GitHub", ClientId: "id"}
+
+	renamedConnector := connector
+	renamedConnector.ConnectorId = "oidc2"
+	retypedConnector := connector
+	retypedConnector.Type = "oauth"
+	retitledConnector := connector
+	retitledConnector.Name = "Company SSO"
+	renamedClient := client
+	renamedClient.ClientId = "app2"
+	resecretedClient := client
+	resecretedClient.Secret = &otherSecret
+	retitledClient := client
+	retitledClient.Name = "My App"
+	renamedGitHub := github
+	renamedGitHub.ConnectorId = "gh2"
+
+	tests := []struct {
+		name        string
+		diff        infer.DiffResponse
+		property    string
+		wantKind    p.DiffKind
+		wantReplace bool
+	}{
+		{"connector connectorId", diffConnector(connector, renamedConnector), "connectorId", p.UpdateReplace, true},
+		{"connector type", diffConnector(connector, retypedConnector), "type", p.UpdateReplace, true},
+		{"connector name", diffConnector(connector, retitledConnector), "name", p.Update, false},
+		{"client clientId", diffResource("Client", client, renamedClient), "clientId", p.UpdateReplace, true},
+		{"client secret", diffResource("Client", client, resecretedClient), "secret", p.UpdateReplace, true},
+		{"client name", diffResource("Client", client, retitledClient), "name", p.Update, false},
+		{"typed connector connectorId", diffResource("GitHubConnector", github, renamedGitHub), "connectorId", p.UpdateReplace, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diff.DetailedDiff[tt.property].Kind; got != tt.wantKind {
+				t.Errorf("%s diff kind = %q, want %q", tt.property, got, tt.wantKind)
+			}
+			if tt.diff.DeleteBeforeReplace != tt.wantReplace {
+				t.Errorf("DeleteBeforeReplace = %v, want %v", tt.diff.DeleteBeforeReplace, tt.wantReplace)
+			}
+		})
+	}
+}
+
+func TestReadSecret(t *testing.T) {
+	writeOnly := true
+	tests := []struct {
+		name               string
+		writeOnly          bool
+		read, state, input string
+		want               string
+	}{
+		{name: "unchanged in Dex keeps input", read: "s1", state: "s1", input: "s2", want: "s2"},
+		{name: "changed in Dex is drift", read: "s3", state: "s1", input: "s1", want: "s3"},
+		{name: "omitted by Dex keeps input", read: "", state: "s1", input: "s2", want: "s2"},
+		{name: "redacted by Dex keeps input", read: redactedValue, state: "s1", input: "s2", want: "s2"},
+		{name: "masked by Dex keeps input", read: "********", state: "s1", input: "s2", want: "s2"},
+		{name: "redacted on refresh keeps state", read: redactedValue, state: "s1", want: "s1"},
+		{name: "import reads Dex", read: "s1", want: "s1"},
+		{name: "unset everywhere", want: ""},
+		{name: "writeOnlySecrets keeps state", writeOnly: true, read: "s3", state: "s1", input: "s2", want: "s1"},
+		{name: "writeOnlySecrets on import reads Dex", writeOnly: true, read: "s1", want: "s1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg provider.DexConfig
+			if tt.writeOnly {
+				cfg.WriteOnlySecrets = &writeOnly
+			}
+			if got := readSecret(cfg, tt.read, tt.state, tt.input); got != tt.want {
+				t.Errorf("readSecret(%q, %q, %q) = %q, want %q", tt.read, tt.state, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDerivedIssuerFieldsReplace(t *testing.T) {
+	ctx := context.Background()
+
+	azure := AzureOidcConnectorArgs{ConnectorId: "azure", Name: "Azure", TenantId: "tenant-a", ClientId: "id", ClientSecret: "s", RedirectUri: "https://dex.example.com/callback"}
+	azureDiff := func(news AzureOidcConnectorArgs) infer.DiffResponse {
+		resp, err := (&AzureOidcConnector{}).Diff(ctx, infer.DiffRequest[AzureOidcConnectorArgs, AzureOidcConnectorState]{
+			ID:     "azure",
+			State:  AzureOidcConnectorState{AzureOidcConnectorArgs: azure},
+			Inputs: news,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	microsoft := AzureMicrosoftConnectorArgs{ConnectorId: "microsoft", Name: "Microsoft", Tenant: "common", ClientId: "id", ClientSecret: "s", RedirectUri: "https://dex.example.com/callback"}
+	microsoftDiff := func(news AzureMicrosoftConnectorArgs) infer.DiffResponse {
+		resp, err := (&AzureMicrosoftConnector{}).Diff(ctx, infer.DiffRequest[AzureMicrosoftConnectorArgs, AzureMicrosoftConnectorState]{
+			ID:     "microsoft",
+			State:  AzureMicrosoftConnectorState{AzureMicrosoftConnectorArgs: microsoft},
+			Inputs: news,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	cognito := CognitoOidcConnectorArgs{ConnectorId: "cognito", Name: "Cognito", Region: "eu-west-1", UserPoolId: "eu-west-1_abc", ClientId: "id", ClientSecret: "s", RedirectUri: "https://dex.example.com/callback"}
+	cognitoDiff := func(news CognitoOidcConnectorArgs) infer.DiffResponse {
+		resp, err := (&CognitoOidcConnector{}).Diff(ctx, infer.DiffRequest[CognitoOidcConnectorArgs, CognitoOidcConnectorState]{
+			ID:     "cognito",
+			State:  CognitoOidcConnectorState{CognitoOidcConnectorArgs: cognito},
+			Inputs: news,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	newTenant := azure
+	newTenant.TenantId = "tenant-b"
+	renamedAzure := azure
+	renamedAzure.Name = "Entra ID"
+	newMicrosoftTenant := microsoft
+	newMicrosoftTenant.Tenant = "organizations"
+	newRegion := cognito
+	newRegion.Region = "us-east-1"
+	newPool := cognito
+	newPool.UserPoolId = "eu-west-1_def"
+	newCognitoClient := cognito
+	newCognitoClient.ClientId = "other"
+
+	tests := []struct {
+		name        string
+		diff        infer.DiffResponse
+		property    string
+		wantKind    p.DiffKind
+		wantReplace bool
+	}{
+		{"azure oidc tenantId", azureDiff(newTenant), "tenantId", p.UpdateReplace, true},
+		{"azure oidc name", azureDiff(renamedAzure), "name", p.Update, false},
+		{"azure microsoft tenant", microsoftDiff(newMicrosoftTenant), "tenant", p.UpdateReplace, true},
+		{"cognito region", cognitoDiff(newRegion), "region", p.UpdateReplace, true},
+		{"cognito userPoolId", cognitoDiff(newPool), "userPoolId", p.UpdateReplace, true},
+		{"cognito clientId", cognitoDiff(newCognitoClient), "clientId", p.Update, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.diff.DetailedDiff[tt.property].Kind; got != tt.wantKind {
+				t.Errorf("%s diff kind = %q, want %q", tt.property, got, tt.wantKind)
+			}
+			if len(tt.diff.DetailedDiff) != 1 {
+				t.Errorf("diff = %+v, want only %s", tt.diff.DetailedDiff, tt.property)
+			}
+			if tt.diff.DeleteBeforeReplace != tt.wantReplace {
+				t.Errorf("DeleteBeforeReplace = %v, want %v", tt.diff.DeleteBeforeReplace, tt.wantReplace)
+			}
+		})
+	}
+}
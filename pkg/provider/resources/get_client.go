@@ -0,0 +1,106 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// getClient - Reads an existing OAuth2 client by ID
+// ============================================================================
+
+// GetClientArgs defines inputs for the getClient function.
+type GetClientArgs struct {
+	ClientId      string `pulumi:"clientId"`
+	IncludeSecret *bool  `pulumi:"includeSecret,optional"`
+}
+
+// GetClientResult defines outputs for the getClient function.
+type GetClientResult struct {
+	ClientId     string   `pulumi:"clientId"`
+	Name         string   `pulumi:"name"`
+	RedirectUris []string `pulumi:"redirectUris"`
+	TrustedPeers []string `pulumi:"trustedPeers"`
+	Public       bool     `pulumi:"public"`
+	LogoUrl      string   `pulumi:"logoUrl"`
+	Secret       *string  `pulumi:"secret,optional" provider:"secret"`
+}
+
+// GetClient reads an OAuth2 client from Dex without managing it.
+type GetClient struct{}
+
+// Annotate provides schema metadata.
+func (f *GetClient) Annotate(a infer.Annotator) {
+	a.Describe(f, "Reads an OAuth2 client from Dex by ID without managing it, e.g. a static client from the Dex config file. Fails when the client does not exist.")
+}
+
+// Annotate provides schema metadata for GetClientArgs.
+func (a *GetClientArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.ClientId, "ID of the client to read.")
+	an.Describe(&a.IncludeSecret, "If true, the client secret is returned as a secret output. Defaults to false.")
+}
+
+// Annotate provides schema metadata for GetClientResult.
+func (r *GetClientResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.ClientId, "ID of the client.")
+	a.Describe(&r.Name, "Display name of the client.")
+	a.Describe(&r.RedirectUris, "Allowed redirect URIs.")
+	a.Describe(&r.TrustedPeers, "Client IDs allowed to mint tokens for this client.")
+	a.Describe(&r.Public, "Whether the client is public.")
+	a.Describe(&r.LogoUrl, "Logo URL, empty when unset.")
+	a.Describe(&r.Secret, "Client secret; only set when includeSecret is true.")
+}
+
+// Invoke calls GetClient on Dex.
+func (f *GetClient) Invoke(ctx context.Context, req infer.FunctionRequest[GetClientArgs]) (infer.FunctionResponse[GetClientResult], error) {
+	id := req.Input.ClientId
+	if id == "" {
+		return infer.FunctionResponse[GetClientResult]{}, fmt.Errorf("clientId is required")
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[GetClientResult]{}, provider.ErrClientNotConfigured
+	}
+
+	getCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	resp, err := cfg.Client.GetClient(getCtx, &api.GetClientReq{Id: id})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.FunctionResponse[GetClientResult]{}, fmt.Errorf("client with id %q not found in Dex", id)
+		}
+		return infer.FunctionResponse[GetClientResult]{}, provider.WrapError("get", "client", id, err)
+	}
+	if resp.Client == nil {
+		return infer.FunctionResponse[GetClientResult]{}, fmt.Errorf("client with id %q not found in Dex", id)
+	}
+	client := resp.Client
+
+	result := GetClientResult{
+		ClientId:     client.Id,
+		Name:         client.Name,
+		RedirectUris: client.RedirectUris,
+		TrustedPeers: client.TrustedPeers,
+		Public:       client.Public,
+		LogoUrl:      client.LogoUrl,
+	}
+	if result.RedirectUris == nil {
+		result.RedirectUris = []string{}
+	}
+	if result.TrustedPeers == nil {
+		result.TrustedPeers = []string{}
+	}
+	if provider.PtrOr(req.Input.IncludeSecret, false) {
+		result.Secret = &client.Secret
+	}
+
+	return infer.FunctionResponse[GetClientResult]{Output: result}, nil
+}
@@ -0,0 +1,137 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// getConnector - Looks up an existing connector by ID
+// ============================================================================
+
+// GetConnectorArgs defines inputs for the getConnector function.
+type GetConnectorArgs struct {
+	ConnectorId string `pulumi:"connectorId"`
+}
+
+// GetConnectorResult defines outputs for the getConnector function.
+type GetConnectorResult struct {
+	ConnectorId string         `pulumi:"connectorId"`
+	Type        string         `pulumi:"type"`
+	Name        string         `pulumi:"name"`
+	Config      map[string]any `pulumi:"config"`
+	ImportType  string         `pulumi:"importType"`
+}
+
+// GetConnector reads a connector from Dex without managing it.
+type GetConnector struct{}
+
+// Annotate provides schema metadata.
+func (f *GetConnector) Annotate(a infer.Annotator) {
+	a.Describe(f, "Looks up a connector in Dex by ID without managing it, e.g. one created by Helm or the Dex config file. Fails when the connector does not exist.")
+}
+
+// Annotate provides schema metadata for GetConnectorArgs.
+func (a *GetConnectorArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.ConnectorId, "ID of the connector to look up.")
+}
+
+// Annotate provides schema metadata for GetConnectorResult.
+func (r *GetConnectorResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.ConnectorId, "ID of the connector.")
+	a.Describe(&r.Type, "Connector type, e.g. 'oidc' or 'github'.")
+	a.Describe(&r.Name, "Display name shown on the Dex login page.")
+	a.Describe(&r.Config, "Connector config as stored in Dex, with secret values (clientSecret, bindPW, serviceAccountJSON) redacted.")
+	a.Describe(&r.ImportType, "Pulumi resource type to use with 'pulumi import' for this connector, e.g. 'dex:index:GitHubConnector'. Falls back to 'dex:index:Connector' for types without a dedicated resource.")
+}
+
+// Invoke lists the connectors in Dex and returns the one with the given ID.
+func (f *GetConnector) Invoke(ctx context.Context, req infer.FunctionRequest[GetConnectorArgs]) (infer.FunctionResponse[GetConnectorResult], error) {
+	id := req.Input.ConnectorId
+	if id == "" {
+		return infer.FunctionResponse[GetConnectorResult]{}, fmt.Errorf("connectorId is required")
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[GetConnectorResult]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, id, nil)
+	if err != nil {
+		return infer.FunctionResponse[GetConnectorResult]{}, err
+	}
+	if found == nil {
+		return infer.FunctionResponse[GetConnectorResult]{}, fmt.Errorf("connector with id %q not found in Dex", id)
+	}
+
+	config := map[string]any{}
+	if trimmed := trimConfigBytes(found.Config); len(trimmed) > 0 {
+		if err := json.Unmarshal(trimmed, &config); err != nil {
+			return infer.FunctionResponse[GetConnectorResult]{}, fmt.Errorf("connector %q has a config that is not a JSON object: %w", id, err)
+		}
+	}
+
+	return infer.FunctionResponse[GetConnectorResult]{
+		Output: GetConnectorResult{
+			ConnectorId: found.Id,
+			Type:        found.Type,
+			Name:        found.Name,
+			Config:      RedactConfig(config),
+			ImportType:  "dex:index:" + connectorImportType(found.Type, config),
+		},
+	}, nil
+}
+
+// connectorImportTypes maps Dex connector types to the resource that manages
+// them, for types that have a dedicated resource. "oidc" is resolved from the
+// issuer by connectorImportType.
+var connectorImportTypes = map[string]string{
+	"github":    "GitHubConnector",
+	"gitlab":    "GitLabConnector",
+	"google":    "GoogleConnector",
+	"ldap":      "LdapConnector",
+	"local":     "LocalConnector",
+	"microsoft": "AzureMicrosoftConnector",
+	"oauth":     "OAuthConnector",
+}
+
+// connectorImportType returns the resource type name to import a connector
+// as. OIDC connectors map to an opinionated resource only when their issuer has
+// the shape that resource derives, since its Read cannot represent any other
+// issuer; everything else falls back to the generic Connector.
+func connectorImportType(connectorType string, config map[string]any) string {
+	if name, ok := connectorImportTypes[connectorType]; ok {
+		return name
+	}
+	if connectorType != "oidc" {
+		return "Connector"
+	}
+	issuer := GetString(config, "issuer")
+	host := strings.TrimSuffix(strings.TrimPrefix(issuer, "https://"), "/")
+	switch {
+	case strings.HasPrefix(issuer, "https://login.microsoftonline.com/"):
+		return "AzureOidcConnector"
+	case strings.HasPrefix(issuer, "https://cognito-idp.") && strings.Contains(issuer, ".amazonaws.com/"):
+		return "CognitoOidcConnector"
+	case awsSsoIssuerRegex.MatchString(issuer):
+		return "AwsSsoOidcConnector"
+	case salesforceIssuerRegex.MatchString(issuer):
+		return "SalesforceOidcConnector"
+	case pingIssuerRegex.MatchString(issuer):
+		return "PingOidcConnector"
+	case keycloakIssuerRegex.MatchString(issuer):
+		return "KeycloakOidcConnector"
+	case strings.HasPrefix(issuer, "https://") && bareDomainRegex.MatchString(host) &&
+		(strings.HasSuffix(host, ".okta.com") || strings.HasSuffix(host, ".oktapreview.com")):
+		return "OktaOidcConnector"
+	case strings.HasPrefix(issuer, "https://") && bareDomainRegex.MatchString(host) && strings.HasSuffix(host, ".auth0.com"):
+		return "Auth0OidcConnector"
+	}
+	return "Connector"
+}
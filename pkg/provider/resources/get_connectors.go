@@ -0,0 +1,113 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// getConnectors - Lists the connectors in Dex
+// ============================================================================
+
+// GetConnectorsArgs defines inputs for the getConnectors function.
+type GetConnectorsArgs struct {
+	TypeFilter     *string `pulumi:"typeFilter,optional"`
+	IncludeSecrets *bool   `pulumi:"includeSecrets,optional"`
+}
+
+// GetConnectorsEntry is one connector returned by getConnectors.
+type GetConnectorsEntry struct {
+	ConnectorId       string         `pulumi:"connectorId"`
+	Type              string         `pulumi:"type"`
+	Name              string         `pulumi:"name"`
+	Config            map[string]any `pulumi:"config"`
+	ConfigWithSecrets map[string]any `pulumi:"configWithSecrets,optional" provider:"secret"`
+}
+
+// GetConnectorsResult defines outputs for the getConnectors function.
+type GetConnectorsResult struct {
+	Connectors []GetConnectorsEntry `pulumi:"connectors"`
+}
+
+// GetConnectors lists the connectors in Dex without managing them.
+type GetConnectors struct{}
+
+// Annotate provides schema metadata.
+func (f *GetConnectors) Annotate(a infer.Annotator) {
+	a.Describe(f, "Lists the connectors in Dex, optionally only those of one type, e.g. to audit connectors created outside Pulumi.")
+}
+
+// Annotate provides schema metadata for GetConnectorsArgs.
+func (a *GetConnectorsArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.TypeFilter, "If set, only connectors of this type (e.g. 'oidc') are returned.")
+	an.Describe(&a.IncludeSecrets, "If true, each connector's unredacted config is also returned, as the secret output configWithSecrets. Defaults to false.")
+}
+
+// Annotate provides schema metadata for GetConnectorsEntry.
+func (r *GetConnectorsEntry) Annotate(a infer.Annotator) {
+	a.Describe(&r.ConnectorId, "ID of the connector.")
+	a.Describe(&r.Type, "Connector type, e.g. 'oidc' or 'github'.")
+	a.Describe(&r.Name, "Display name shown on the Dex login page.")
+	a.Describe(&r.Config, "Connector config as stored in Dex, with secret values (clientSecret, bindPW, serviceAccountJSON) redacted. Unset when the stored config is not a JSON object.")
+	a.Describe(&r.ConfigWithSecrets, "Connector config as stored in Dex, including secret values; only set when includeSecrets is true.")
+}
+
+// Annotate provides schema metadata for GetConnectorsResult.
+func (r *GetConnectorsResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Connectors, "The connectors, in the order Dex lists them.")
+}
+
+// Invoke lists the connectors in Dex.
+func (f *GetConnectors) Invoke(ctx context.Context, req infer.FunctionRequest[GetConnectorsArgs]) (infer.FunctionResponse[GetConnectorsResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[GetConnectorsResult]{}, provider.ErrClientNotConfigured
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	if err != nil {
+		return infer.FunctionResponse[GetConnectorsResult]{}, fmt.Errorf("failed to list Dex connectors: %w", err)
+	}
+
+	typeFilter := provider.PtrOr(req.Input.TypeFilter, "")
+	includeSecrets := provider.PtrOr(req.Input.IncludeSecrets, false)
+
+	connectors := []GetConnectorsEntry{}
+	for _, con := range listResp.Connectors {
+		if typeFilter != "" && con.Type != typeFilter {
+			continue
+		}
+		entry := GetConnectorsEntry{
+			ConnectorId: con.Id,
+			Type:        con.Type,
+			Name:        con.Name,
+		}
+		config := map[string]any{}
+		if trimmed := trimConfigBytes(con.Config); len(trimmed) > 0 {
+			if err := json.Unmarshal(trimmed, &config); err != nil {
+				p.GetLogger(ctx).Warningf("connector %q has a config that is not a JSON object: %v", con.Id, err)
+				config = nil
+			}
+		}
+		if config != nil {
+			entry.Config = RedactConfig(config)
+			if includeSecrets {
+				entry.ConfigWithSecrets = config
+			}
+		}
+		connectors = append(connectors, entry)
+	}
+
+	return infer.FunctionResponse[GetConnectorsResult]{
+		Output: GetConnectorsResult{Connectors: connectors},
+	}, nil
+}
@@ -0,0 +1,60 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// getDexVersion - Reports the version of the Dex server
+// ============================================================================
+
+// GetDexVersionArgs defines inputs for the getDexVersion function.
+type GetDexVersionArgs struct{}
+
+// GetDexVersionResult defines outputs for the getDexVersion function.
+type GetDexVersionResult struct {
+	Server string `pulumi:"server"`
+	Api    int    `pulumi:"api"`
+}
+
+// GetDexVersion returns the Dex server and API versions.
+type GetDexVersion struct{}
+
+// Annotate provides schema metadata.
+func (f *GetDexVersion) Annotate(a infer.Annotator) {
+	a.Describe(f, "Returns the version of the Dex server and of its gRPC API, e.g. to assert on a minimum version or gate features.")
+}
+
+// Annotate provides schema metadata for GetDexVersionResult.
+func (r *GetDexVersionResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Server, "Semantic version of the Dex server.")
+	a.Describe(&r.Api, "Numeric API version. It increases every time a call is added to the API.")
+}
+
+// Invoke calls GetVersion on Dex.
+func (f *GetDexVersion) Invoke(ctx context.Context, req infer.FunctionRequest[GetDexVersionArgs]) (infer.FunctionResponse[GetDexVersionResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[GetDexVersionResult]{}, provider.ErrClientNotConfigured
+	}
+
+	versionCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	resp, err := cfg.Client.GetVersion(versionCtx, &api.VersionReq{})
+	if err != nil {
+		return infer.FunctionResponse[GetDexVersionResult]{}, fmt.Errorf("failed to get Dex version: %w", err)
+	}
+
+	return infer.FunctionResponse[GetDexVersionResult]{
+		Output: GetDexVersionResult{
+			Server: resp.Server,
+			Api:    int(resp.Api),
+		},
+	}, nil
+}
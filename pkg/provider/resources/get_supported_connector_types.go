@@ -0,0 +1,48 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// getSupportedConnectorTypes - Lists connector types accepted by Dex
+// ============================================================================
+
+// GetSupportedConnectorTypesArgs defines inputs for the getSupportedConnectorTypes function.
+type GetSupportedConnectorTypesArgs struct{}
+
+// GetSupportedConnectorTypesResult defines outputs for the getSupportedConnectorTypes function.
+type GetSupportedConnectorTypesResult struct {
+	Types  []string `pulumi:"types"`
+	Source string   `pulumi:"source"`
+}
+
+// GetSupportedConnectorTypes lists the connector types Dex accepts.
+type GetSupportedConnectorTypes struct{}
+
+// Annotate provides schema metadata.
+func (f *GetSupportedConnectorTypes) Annotate(a infer.Annotator) {
+	a.Describe(f, "Returns the connector types supported by Dex. Queries the server when it exposes its connector registry and otherwise falls back to the provider's static list.")
+}
+
+// Annotate provides schema metadata for GetSupportedConnectorTypesResult.
+func (r *GetSupportedConnectorTypesResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Types, "Supported connector types, sorted.")
+	a.Describe(&r.Source, "Where the list came from: 'server' or 'static'.")
+}
+
+// Invoke returns the supported connector types.
+func (f *GetSupportedConnectorTypes) Invoke(ctx context.Context, req infer.FunctionRequest[GetSupportedConnectorTypesArgs]) (infer.FunctionResponse[GetSupportedConnectorTypesResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	types, source := provider.SupportedConnectorTypes(ctx, cfg.Client)
+
+	return infer.FunctionResponse[GetSupportedConnectorTypesResult]{
+		Output: GetSupportedConnectorTypesResult{
+			Types:  types,
+			Source: source,
+		},
+	}, nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
+	"strings"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
@@ -38,11 +38,15 @@ type GitHubConnectorArgs struct {
 	PreferredEmailDomain *string     `pulumi:"preferredEmailDomain,optional"`
 	HostName             *string     `pulumi:"hostName,optional"` // For GitHub Enterprise
 	RootCA               *string     `pulumi:"rootCA,optional"`   // For GitHub Enterprise
+	RootCAData           *string     `pulumi:"rootCAData,optional"`
+	TimeoutSeconds       *int        `pulumi:"timeoutSeconds,optional"`
 }
 
 // GitHubConnectorState defines outputs for GitHubConnector.
 type GitHubConnectorState struct {
 	GitHubConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
 }
 
 // GitHubConnector manages a GitHub connector in Dex.
@@ -65,8 +69,10 @@ func (c *GitHubConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.TeamNameField, "Field to use for team names in group claims. Valid values: 'name', 'slug', or 'both'. Defaults to 'slug'.")
 	a.Describe(&c.UseLoginAsID, "If true, use GitHub login username as the user ID. Defaults to false.")
 	a.Describe(&c.PreferredEmailDomain, "Preferred email domain. If set, users with emails in this domain will be preferred.")
-	a.Describe(&c.HostName, "GitHub Enterprise hostname (e.g., 'github.example.com'). Leave empty for github.com.")
-	a.Describe(&c.RootCA, "Root CA certificate for GitHub Enterprise (PEM format). Required if using self-signed certificates.")
+	a.Describe(&c.HostName, "GitHub Enterprise hostname (e.g., 'github.example.com'). Leave unset for github.com. Setting, changing, or removing it forces a replacement, since it switches between github.com and an Enterprise instance.")
+	a.Describe(&c.RootCA, "Path on the Dex server to a PEM root CA file for GitHub Enterprise. Required if using self-signed certificates. Mutually exclusive with rootCAData.")
+	a.Describe(&c.RootCAData, "Inline PEM root CA certificate for GitHub Enterprise, for when files cannot be placed on the Dex server. Sent to Dex base64-encoded as rootCAData. Mutually exclusive with rootCA.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for GitHubOrg.
@@ -78,6 +84,8 @@ func (c *GitHubOrg) Annotate(a infer.Annotator) {
 // Annotate provides schema metadata for GitHubConnectorState.
 func (c *GitHubConnectorState) Annotate(a infer.Annotator) {
 	// GitHubConnectorState embeds GitHubConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
 }
 
 // Check validates inputs.
@@ -87,6 +95,8 @@ func (c *GitHubConnector) Check(ctx context.Context, req infer.CheckRequest) (in
 		return infer.CheckResponse[GitHubConnectorArgs]{Failures: failures}, err
 	}
 
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
 	// Validate teamNameField
 	if args.TeamNameField != nil {
 		valid := map[string]bool{"name": true, "slug": true, "both": true}
@@ -98,34 +108,55 @@ func (c *GitHubConnector) Check(ctx context.Context, req infer.CheckRequest) (in
 		}
 	}
 
-	// Apply defaults
-	if args.LoadAllGroups == nil {
-		defaultLoadAll := false
-		args.LoadAllGroups = &defaultLoadAll
+	// Validate orgs; Dex ignores team filters when loading all groups.
+	hasTeams := false
+	for i, org := range args.Orgs {
+		if strings.TrimSpace(org.Name) == "" {
+			failures = append(failures, p.CheckFailure{
+				Property: fmt.Sprintf("orgs[%d].name", i),
+				Reason:   "must not be empty",
+			})
+		}
+		if len(org.Teams) > 0 {
+			hasTeams = true
+		}
 	}
-	if args.TeamNameField == nil {
-		defaultTeamNameField := "slug"
-		args.TeamNameField = &defaultTeamNameField
-	}
-	if args.UseLoginAsID == nil {
-		defaultUseLogin := false
-		args.UseLoginAsID = &defaultUseLogin
+	if provider.PtrOr(args.LoadAllGroups, false) && hasTeams {
+		failures = append(failures, p.CheckFailure{
+			Property: "loadAllGroups",
+			Reason:   "loadAllGroups cannot be combined with orgs that list teams, since Dex would ignore the team filters; remove the teams or set loadAllGroups to false",
+		})
 	}
 
+	failures = append(failures, validateGitHubEnterprise(args)...)
+
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+
+	applyGitHubDefaults(&args)
+
 	return infer.CheckResponse[GitHubConnectorArgs]{
 		Inputs:   args,
 		Failures: failures,
 	}, nil
 }
 
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *GitHubConnector) Diff(ctx context.Context, req infer.DiffRequest[GitHubConnectorArgs, GitHubConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("GitHubConnector", req.State.GitHubConnectorArgs, req.Inputs), nil
+}
+
 // Create creates a new GitHub connector.
 func (c *GitHubConnector) Create(ctx context.Context, req infer.CreateRequest[GitHubConnectorArgs]) (infer.CreateResponse[GitHubConnectorState], error) {
 	args := req.Inputs
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[GitHubConnectorState]{}, err
+		}
 		state := GitHubConnectorState{
 			GitHubConnectorArgs: args,
+			CallbackUrl:         dexCallbackURL(ctx),
 		}
 		return infer.CreateResponse[GitHubConnectorState]{
 			ID:     args.ConnectorId,
@@ -135,47 +166,10 @@ func (c *GitHubConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[GitHubConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.CreateResponse[GitHubConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	// Build GitHub connector config
-	githubConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-	}
-
-	if len(args.Orgs) > 0 {
-		orgsConfig := make([]map[string]any, 0, len(args.Orgs))
-		for _, org := range args.Orgs {
-			orgConfig := map[string]any{"name": org.Name}
-			if len(org.Teams) > 0 {
-				orgConfig["teams"] = org.Teams
-			}
-			orgsConfig = append(orgsConfig, orgConfig)
-		}
-		githubConfig["orgs"] = orgsConfig
-	}
-	if args.LoadAllGroups != nil {
-		githubConfig["loadAllGroups"] = *args.LoadAllGroups
-	}
-	if args.TeamNameField != nil {
-		githubConfig["teamNameField"] = *args.TeamNameField
-	}
-	if args.UseLoginAsID != nil {
-		githubConfig["useLoginAsID"] = *args.UseLoginAsID
-	}
-	if args.PreferredEmailDomain != nil {
-		githubConfig["preferredEmailDomain"] = *args.PreferredEmailDomain
-	}
-	if args.HostName != nil {
-		githubConfig["hostName"] = *args.HostName
-	}
-	if args.RootCA != nil {
-		githubConfig["rootCA"] = *args.RootCA
-	}
-
-	configBytes, err := json.Marshal(githubConfig)
+	configBytes, err := json.Marshal(buildGitHubConfig(args))
 	if err != nil {
 		return infer.CreateResponse[GitHubConnectorState]{}, fmt.Errorf("failed to marshal GitHub config: %w", err)
 	}
@@ -187,7 +181,7 @@ func (c *GitHubConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 		Config: configBytes,
 	}
 
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
@@ -198,11 +192,15 @@ func (c *GitHubConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 	}
 
 	if resp.AlreadyExists {
-		return infer.CreateResponse[GitHubConnectorState]{}, fmt.Errorf("connector with id %q already exists", args.ConnectorId)
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[GitHubConnectorState]{}, err
+		}
 	}
 
 	state := GitHubConnectorState{
 		GitHubConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
 	}
 
 	return infer.CreateResponse[GitHubConnectorState]{
@@ -215,23 +213,12 @@ func (c *GitHubConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 func (c *GitHubConnector) Read(ctx context.Context, req infer.ReadRequest[GitHubConnectorArgs, GitHubConnectorState]) (infer.ReadResponse[GitHubConnectorArgs, GitHubConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[GitHubConnectorArgs, GitHubConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[GitHubConnectorArgs, GitHubConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	listCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[GitHubConnectorArgs, GitHubConnectorState]{}, fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, conn := range listResp.Connectors {
-		if conn.Id == req.ID {
-			found = conn
-			break
-		}
+		return infer.ReadResponse[GitHubConnectorArgs, GitHubConnectorState]{}, err
 	}
 
 	if found == nil {
@@ -239,47 +226,29 @@ func (c *GitHubConnector) Read(ctx context.Context, req infer.ReadRequest[GitHub
 	}
 
 	var configMap map[string]any
-	if err := json.Unmarshal(found.Config, &configMap); err != nil {
-		return infer.ReadResponse[GitHubConnectorArgs, GitHubConnectorState]{}, nil
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
 	}
 
-	// Parse orgs array
-	var orgs []GitHubOrg
-	if orgsVal, ok := configMap["orgs"].([]any); ok {
-		for _, o := range orgsVal {
-			if orgMap, ok := o.(map[string]any); ok {
-				org := GitHubOrg{
-					Name: GetString(orgMap, "name"),
-				}
-				if teamsVal, ok := orgMap["teams"].([]any); ok {
-					for _, t := range teamsVal {
-						if teamStr, ok := t.(string); ok {
-							org.Teams = append(org.Teams, teamStr)
-						}
-					}
-				}
-				orgs = append(orgs, org)
-			}
-		}
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildGitHubConfig(req.State.GitHubConnectorArgs))
 	}
 
-	args := GitHubConnectorArgs{
-		ConnectorId:          found.Id,
-		Name:                 found.Name,
-		ClientId:             GetString(configMap, "clientID"),
-		ClientSecret:         GetString(configMap, "clientSecret"),
-		RedirectUri:          GetString(configMap, "redirectURI"),
-		Orgs:                 orgs,
-		LoadAllGroups:        GetBoolPtr(configMap, "loadAllGroups"),
-		TeamNameField:        GetStringPtr(configMap, "teamNameField"),
-		UseLoginAsID:         GetBoolPtr(configMap, "useLoginAsID"),
-		PreferredEmailDomain: GetStringPtr(configMap, "preferredEmailDomain"),
-		HostName:             GetStringPtr(configMap, "hostName"),
-		RootCA:               GetStringPtr(configMap, "rootCA"),
-	}
+	args := decodeGitHubConfig(configMap)
+	args.ConnectorId = found.Id
+	args.Name = found.Name
+	args.ClientSecret = readSecret(cfg, args.ClientSecret, req.State.ClientSecret, req.Inputs.ClientSecret)
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
 
 	state := GitHubConnectorState{
 		GitHubConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
+		RawConfigOut:        redactedRawConfig(found.Config),
 	}
 
 	return infer.ReadResponse[GitHubConnectorArgs, GitHubConnectorState]{
@@ -296,8 +265,12 @@ func (c *GitHubConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[GitHubConnectorState]{}, err
+		}
 		state := GitHubConnectorState{
 			GitHubConnectorArgs: args,
+			CallbackUrl:         dexCallbackURL(ctx),
 		}
 		return infer.UpdateResponse[GitHubConnectorState]{
 			Output: state,
@@ -306,58 +279,23 @@ func (c *GitHubConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[GitHubConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.UpdateResponse[GitHubConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	if args.ConnectorId != oldState.ConnectorId {
-		return infer.UpdateResponse[GitHubConnectorState]{}, fmt.Errorf("connectorId cannot be changed")
-	}
-	if args.HostName != nil && oldState.HostName != nil && *args.HostName != *oldState.HostName {
-		return infer.UpdateResponse[GitHubConnectorState]{}, fmt.Errorf("hostName cannot be changed (would require replace)")
-	}
-
-	githubConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
+	if err := checkImmutableFields("GitHubConnector", oldState.GitHubConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[GitHubConnectorState]{}, err
 	}
 
-	if len(args.Orgs) > 0 {
-		orgsConfig := make([]map[string]any, 0, len(args.Orgs))
-		for _, org := range args.Orgs {
-			orgConfig := map[string]any{"name": org.Name}
-			if len(org.Teams) > 0 {
-				orgConfig["teams"] = org.Teams
-			}
-			orgsConfig = append(orgsConfig, orgConfig)
-		}
-		githubConfig["orgs"] = orgsConfig
-	}
-	if args.LoadAllGroups != nil {
-		githubConfig["loadAllGroups"] = *args.LoadAllGroups
-	}
-	if args.TeamNameField != nil {
-		githubConfig["teamNameField"] = *args.TeamNameField
-	}
-	if args.UseLoginAsID != nil {
-		githubConfig["useLoginAsID"] = *args.UseLoginAsID
-	}
-	if args.PreferredEmailDomain != nil {
-		githubConfig["preferredEmailDomain"] = *args.PreferredEmailDomain
-	}
-	if args.HostName != nil {
-		githubConfig["hostName"] = *args.HostName
-	}
-	if args.RootCA != nil {
-		githubConfig["rootCA"] = *args.RootCA
-	}
-
-	configBytes, err := json.Marshal(githubConfig)
+	configBytes, err := json.Marshal(buildGitHubConfig(args))
 	if err != nil {
 		return infer.UpdateResponse[GitHubConnectorState]{}, fmt.Errorf("failed to marshal GitHub config: %w", err)
 	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[GitHubConnectorState]{}, err
+	}
 
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
@@ -372,6 +310,7 @@ func (c *GitHubConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 
 	state := GitHubConnectorState{
 		GitHubConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
 	}
 
 	return infer.UpdateResponse[GitHubConnectorState]{
@@ -383,7 +322,7 @@ func (c *GitHubConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 func (c *GitHubConnector) Delete(ctx context.Context, req infer.DeleteRequest[GitHubConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -391,7 +330,7 @@ func (c *GitHubConnector) Delete(ctx context.Context, req infer.DeleteRequest[Gi
 		deleteID = req.State.ConnectorId
 	}
 
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
@@ -404,5 +343,146 @@ func (c *GitHubConnector) Delete(ctx context.Context, req infer.DeleteRequest[Gi
 		return infer.DeleteResponse{}, provider.WrapError("delete", "github-connector", deleteID, err)
 	}
 
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
 	return infer.DeleteResponse{}, nil
 }
+
+// applyGitHubDefaults fills in defaults for optional GitHubConnector inputs.
+func applyGitHubDefaults(args *GitHubConnectorArgs) {
+	if args.LoadAllGroups == nil {
+		defaultLoadAll := false
+		args.LoadAllGroups = &defaultLoadAll
+	}
+	if args.TeamNameField == nil {
+		defaultTeamNameField := "slug"
+		args.TeamNameField = &defaultTeamNameField
+	}
+	if args.UseLoginAsID == nil {
+		defaultUseLogin := false
+		args.UseLoginAsID = &defaultUseLogin
+	}
+}
+
+// buildGitHubConfig builds the Dex "github" connector config from args.
+func buildGitHubConfig(args GitHubConnectorArgs) map[string]any {
+	githubConfig := map[string]any{
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+	}
+
+	if len(args.Orgs) > 0 {
+		orgsConfig := make([]map[string]any, 0, len(args.Orgs))
+		for _, org := range args.Orgs {
+			orgConfig := map[string]any{"name": org.Name}
+			if len(org.Teams) > 0 {
+				orgConfig["teams"] = org.Teams
+			}
+			orgsConfig = append(orgsConfig, orgConfig)
+		}
+		githubConfig["orgs"] = orgsConfig
+	}
+	if args.LoadAllGroups != nil {
+		githubConfig["loadAllGroups"] = *args.LoadAllGroups
+	}
+	if args.TeamNameField != nil {
+		githubConfig["teamNameField"] = *args.TeamNameField
+	}
+	if args.UseLoginAsID != nil {
+		githubConfig["useLoginAsID"] = *args.UseLoginAsID
+	}
+	if args.PreferredEmailDomain != nil {
+		githubConfig["preferredEmailDomain"] = *args.PreferredEmailDomain
+	}
+	if args.HostName != nil {
+		githubConfig["hostName"] = *args.HostName
+	}
+	if args.RootCA != nil {
+		githubConfig["rootCA"] = *args.RootCA
+	}
+	if args.RootCAData != nil {
+		githubConfig["rootCAData"] = encodeRootCAData(*args.RootCAData)
+	}
+
+	return githubConfig
+}
+
+// validateGitHubEnterprise checks the GitHub Enterprise settings: hostName
+// must be a bare host, and rootCA or rootCAData require hostName.
+func validateGitHubEnterprise(args GitHubConnectorArgs) []p.CheckFailure {
+	var failures []p.CheckFailure
+	if args.HostName != nil {
+		if reason := validateGitHubHostName(*args.HostName); reason != "" {
+			failures = append(failures, p.CheckFailure{
+				Property: "hostName",
+				Reason:   reason,
+			})
+		}
+	}
+	if args.RootCA != nil && *args.RootCA != "" && (args.HostName == nil || *args.HostName == "") {
+		failures = append(failures, p.CheckFailure{
+			Property: "rootCA",
+			Reason:   "rootCA is only used for GitHub Enterprise and requires hostName to be set",
+		})
+	}
+	if args.RootCAData != nil && *args.RootCAData != "" && (args.HostName == nil || *args.HostName == "") {
+		failures = append(failures, p.CheckFailure{
+			Property: "rootCAData",
+			Reason:   "rootCAData is only used for GitHub Enterprise and requires hostName to be set",
+		})
+	}
+	failures = append(failures, validateRootCA(args.RootCA, args.RootCAData)...)
+	return failures
+}
+
+// decodeGitHubConfig converts a Dex "github" connector config into args.
+func decodeGitHubConfig(configMap map[string]any) GitHubConnectorArgs {
+	args := GitHubConnectorArgs{
+		ClientId:             GetString(configMap, "clientID"),
+		ClientSecret:         GetString(configMap, "clientSecret"),
+		RedirectUri:          GetString(configMap, "redirectURI"),
+		LoadAllGroups:        GetBoolPtr(configMap, "loadAllGroups"),
+		TeamNameField:        GetStringPtr(configMap, "teamNameField"),
+		UseLoginAsID:         GetBoolPtr(configMap, "useLoginAsID"),
+		PreferredEmailDomain: GetStringPtr(configMap, "preferredEmailDomain"),
+		HostName:             GetStringPtr(configMap, "hostName"),
+		RootCA:               GetStringPtr(configMap, "rootCA"), // decoded verbatim so PEM newlines round-trip
+		RootCAData:           decodeRootCAData(configMap),
+	}
+	if orgsVal, ok := configMap["orgs"].([]any); ok {
+		for _, o := range orgsVal {
+			if orgMap, ok := o.(map[string]any); ok {
+				org := GitHubOrg{
+					Name: GetString(orgMap, "name"),
+				}
+				if teamsVal, ok := orgMap["teams"].([]any); ok {
+					for _, t := range teamsVal {
+						if teamStr, ok := t.(string); ok {
+							org.Teams = append(org.Teams, teamStr)
+						}
+					}
+				}
+				args.Orgs = append(args.Orgs, org)
+			}
+		}
+	}
+	return args
+}
+
+// validateGitHubHostName checks that hostName is a bare host (optionally with a
+// port), as Dex builds the Enterprise API URL from it. It returns a failure
+// reason, or "" if the value is valid.
+func validateGitHubHostName(hostName string) string {
+	switch {
+	case hostName == "":
+		return "must not be empty; omit hostName for github.com"
+	case strings.Contains(hostName, "://"):
+		return fmt.Sprintf("must be a bare host without a scheme (e.g. 'github.example.com'), got %q", hostName)
+	case strings.ContainsAny(hostName, "/?# \t"):
+		return fmt.Sprintf("must be a bare host without a path or whitespace (e.g. 'github.example.com'), got %q", hostName)
+	}
+	return ""
+}
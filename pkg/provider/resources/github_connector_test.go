@@ -0,0 +1,115 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// testPEM is a syntactically valid PEM certificate block.
+const testPEM = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+func TestValidateGitHubEnterprise(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		args      GitHubConnectorArgs
+		wantProps []string
+	}{
+		{name: "github.com"},
+		{name: "enterprise host", args: GitHubConnectorArgs{HostName: str("github.example.com")}},
+		{name: "enterprise host with port", args: GitHubConnectorArgs{HostName: str("github.example.com:8443")}},
+		{name: "enterprise host with rootCA", args: GitHubConnectorArgs{HostName: str("github.example.com"), RootCA: str("/etc/dex/ca.pem")}},
+		{name: "scheme", args: GitHubConnectorArgs{HostName: str("https://github.example.com")}, wantProps: []string{"hostName"}},
+		{name: "path", args: GitHubConnectorArgs{HostName: str("github.example.com/api")}, wantProps: []string{"hostName"}},
+		{name: "empty host", args: GitHubConnectorArgs{HostName: str("")}, wantProps: []string{"hostName"}},
+		{name: "rootCA without hostName", args: GitHubConnectorArgs{RootCA: str("/etc/dex/ca.pem")}, wantProps: []string{"rootCA"}},
+		{name: "rootCAData without hostName", args: GitHubConnectorArgs{RootCAData: str(testPEM)}, wantProps: []string{"rootCAData"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := validateGitHubEnterprise(tt.args)
+			var props []string
+			for _, f := range failures {
+				props = append(props, f.Property)
+			}
+			if !reflect.DeepEqual(props, tt.wantProps) {
+				t.Errorf("failures = %+v, want properties %v", failures, tt.wantProps)
+			}
+		})
+	}
+}
+
+func TestGitHubEnterpriseSettingsRoundTrip(t *testing.T) {
+	hostName := "github.example.com"
+	rootCA := "/etc/dex/github-ca.pem\n"
+	args := GitHubConnectorArgs{
+		ClientId:     "client",
+		ClientSecret: "secret",
+		RedirectUri:  "https://dex.example.com/callback",
+		Orgs:         []GitHubOrg{{Name: "acme", Teams: []string{"admins"}}},
+		HostName:     &hostName,
+		RootCA:       &rootCA,
+	}
+	applyGitHubDefaults(&args)
+
+	got := decodeGitHubConfig(roundTripConfig(t, buildGitHubConfig(args)))
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("round trip changed args:\n got  %+v\n want %+v", got, args)
+	}
+	if got.RootCA == nil || *got.RootCA != rootCA {
+		t.Errorf("rootCA = %q, want %q with its trailing newline", provider.PtrOr(got.RootCA, ""), rootCA)
+	}
+
+	args.RootCA = nil
+	rootCAData := testPEM
+	args.RootCAData = &rootCAData
+	got = decodeGitHubConfig(roundTripConfig(t, buildGitHubConfig(args)))
+	if got.RootCAData == nil || *got.RootCAData != rootCAData {
+		t.Errorf("rootCAData = %q, want %q", provider.PtrOr(got.RootCAData, ""), rootCAData)
+	}
+}
+
+func TestGitHubHostNameChangeReplaces(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name        string
+		old, new    *string
+		wantReplace bool
+	}{
+		{name: "nil to value", old: nil, new: str("github.example.com"), wantReplace: true},
+		{name: "value to nil", old: str("github.example.com"), new: nil, wantReplace: true},
+		{name: "nil to nil", old: nil, new: nil},
+		{name: "value to same value", old: str("github.example.com"), new: str("github.example.com")},
+		{name: "value to other value", old: str("github.example.com"), new: str("git.example.org"), wantReplace: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			olds := GitHubConnectorArgs{ConnectorId: "gh", Name: "GitHub", ClientId: "id", HostName: tt.old}
+			news := olds
+			news.HostName = tt.new
+
+			if got := fieldDiffKind("GitHubConnector", "hostName"); got != p.UpdateReplace {
+				t.Errorf("fieldDiffKind(hostName) = %q, want %q", got, p.UpdateReplace)
+			}
+			diff := diffResource("GitHubConnector", olds, news)
+			d, changed := diff.DetailedDiff["hostName"]
+			if changed != tt.wantReplace {
+				t.Fatalf("hostName in diff = %v, want %v (diff %+v)", changed, tt.wantReplace, diff.DetailedDiff)
+			}
+			if changed && d.Kind != p.UpdateReplace {
+				t.Errorf("hostName diff kind = %q, want %q", d.Kind, p.UpdateReplace)
+			}
+			if diff.DeleteBeforeReplace != tt.wantReplace {
+				t.Errorf("DeleteBeforeReplace = %v, want %v", diff.DeleteBeforeReplace, tt.wantReplace)
+			}
+			if err := checkImmutableFields("GitHubConnector", olds, news); (err != nil) != tt.wantReplace {
+				t.Errorf("checkImmutableFields() = %v, want error %v", err, tt.wantReplace)
+			}
+		})
+	}
+}
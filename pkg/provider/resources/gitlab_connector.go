@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
@@ -17,6 +16,9 @@ import (
 // GitLabConnector - GitLab OAuth2 connector (type: "gitlab")
 // ============================================================================
 
+// defaultGitLabBaseURL is the base URL used when baseURL is not set.
+const defaultGitLabBaseURL = "https://gitlab.com"
+
 // GitLabConnectorArgs defines inputs for GitLabConnector.
 type GitLabConnectorArgs struct {
 	ConnectorId         string   `pulumi:"connectorId"`
@@ -28,11 +30,17 @@ type GitLabConnectorArgs struct {
 	Groups              []string `pulumi:"groups,optional"`
 	UseLoginAsID        *bool    `pulumi:"useLoginAsID,optional"`
 	GetGroupsPermission *bool    `pulumi:"getGroupsPermission,optional"`
+	AllGroupsDetailed   *bool    `pulumi:"allGroupsDetailed,optional"`
+	RootCA              *string  `pulumi:"rootCA,optional"`
+	RootCAData          *string  `pulumi:"rootCAData,optional"`
+	TimeoutSeconds      *int     `pulumi:"timeoutSeconds,optional"`
 }
 
 // GitLabConnectorState defines outputs for GitLabConnector.
 type GitLabConnectorState struct {
 	GitLabConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
 }
 
 // GitLabConnector manages a GitLab connector in Dex.
@@ -54,11 +62,17 @@ func (c *GitLabConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.Groups, "List of GitLab group names. Only users in these groups will be allowed to authenticate.")
 	a.Describe(&c.UseLoginAsID, "If true, use GitLab username as the user ID. Defaults to false.")
 	a.Describe(&c.GetGroupsPermission, "If true, request 'read_api' scope to fetch group memberships. Defaults to false.")
+	a.Describe(&c.AllGroupsDetailed, "If true, groups claims include every group the user belongs to along with their role in it, rather than only group names.")
+	a.Describe(&c.RootCA, "Path on the Dex server to a PEM root CA file for a self-managed GitLab instance behind an internal CA. Mutually exclusive with rootCAData.")
+	a.Describe(&c.RootCAData, "Inline PEM root CA certificate for a self-managed GitLab instance, for when files cannot be placed on the Dex server. Sent to Dex base64-encoded as rootCAData. Mutually exclusive with rootCA.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for GitLabConnectorState.
 func (c *GitLabConnectorState) Annotate(a infer.Annotator) {
 	// GitLabConnectorState embeds GitLabConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
 }
 
 // Check validates inputs.
@@ -68,19 +82,12 @@ func (c *GitLabConnector) Check(ctx context.Context, req infer.CheckRequest) (in
 		return infer.CheckResponse[GitLabConnectorArgs]{Failures: failures}, err
 	}
 
-	// Apply defaults
-	if args.BaseURL == nil || *args.BaseURL == "" {
-		defaultURL := "https://gitlab.com"
-		args.BaseURL = &defaultURL
-	}
-	if args.UseLoginAsID == nil {
-		defaultUseLogin := false
-		args.UseLoginAsID = &defaultUseLogin
-	}
-	if args.GetGroupsPermission == nil {
-		defaultGetGroups := false
-		args.GetGroupsPermission = &defaultGetGroups
-	}
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	failures = append(failures, validateRootCA(args.RootCA, args.RootCAData)...)
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+
+	applyGitLabDefaults(&args)
 
 	return infer.CheckResponse[GitLabConnectorArgs]{
 		Inputs:   args,
@@ -88,14 +95,23 @@ func (c *GitLabConnector) Check(ctx context.Context, req infer.CheckRequest) (in
 	}, nil
 }
 
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *GitLabConnector) Diff(ctx context.Context, req infer.DiffRequest[GitLabConnectorArgs, GitLabConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("GitLabConnector", req.State.GitLabConnectorArgs, req.Inputs), nil
+}
+
 // Create creates a new GitLab connector.
 func (c *GitLabConnector) Create(ctx context.Context, req infer.CreateRequest[GitLabConnectorArgs]) (infer.CreateResponse[GitLabConnectorState], error) {
 	args := req.Inputs
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[GitLabConnectorState]{}, err
+		}
 		state := GitLabConnectorState{
 			GitLabConnectorArgs: args,
+			CallbackUrl:         dexCallbackURL(ctx),
 		}
 		return infer.CreateResponse[GitLabConnectorState]{
 			ID:     args.ConnectorId,
@@ -105,30 +121,10 @@ func (c *GitLabConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[GitLabConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.CreateResponse[GitLabConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	// Build GitLab connector config
-	gitlabConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-	}
-
-	if args.BaseURL != nil && *args.BaseURL != "" {
-		gitlabConfig["baseURL"] = *args.BaseURL
-	}
-	if len(args.Groups) > 0 {
-		gitlabConfig["groups"] = args.Groups
-	}
-	if args.UseLoginAsID != nil {
-		gitlabConfig["useLoginAsID"] = *args.UseLoginAsID
-	}
-	if args.GetGroupsPermission != nil {
-		gitlabConfig["getGroupsPermission"] = *args.GetGroupsPermission
-	}
-
-	configBytes, err := json.Marshal(gitlabConfig)
+	configBytes, err := json.Marshal(buildGitLabConfig(args))
 	if err != nil {
 		return infer.CreateResponse[GitLabConnectorState]{}, fmt.Errorf("failed to marshal GitLab config: %w", err)
 	}
@@ -140,7 +136,7 @@ func (c *GitLabConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 		Config: configBytes,
 	}
 
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
@@ -151,11 +147,15 @@ func (c *GitLabConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 	}
 
 	if resp.AlreadyExists {
-		return infer.CreateResponse[GitLabConnectorState]{}, fmt.Errorf("connector with id %q already exists", args.ConnectorId)
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[GitLabConnectorState]{}, err
+		}
 	}
 
 	state := GitLabConnectorState{
 		GitLabConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
 	}
 
 	return infer.CreateResponse[GitLabConnectorState]{
@@ -168,23 +168,12 @@ func (c *GitLabConnector) Create(ctx context.Context, req infer.CreateRequest[Gi
 func (c *GitLabConnector) Read(ctx context.Context, req infer.ReadRequest[GitLabConnectorArgs, GitLabConnectorState]) (infer.ReadResponse[GitLabConnectorArgs, GitLabConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[GitLabConnectorArgs, GitLabConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[GitLabConnectorArgs, GitLabConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	listCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[GitLabConnectorArgs, GitLabConnectorState]{}, fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, conn := range listResp.Connectors {
-		if conn.Id == req.ID {
-			found = conn
-			break
-		}
+		return infer.ReadResponse[GitLabConnectorArgs, GitLabConnectorState]{}, err
 	}
 
 	if found == nil {
@@ -192,38 +181,29 @@ func (c *GitLabConnector) Read(ctx context.Context, req infer.ReadRequest[GitLab
 	}
 
 	var configMap map[string]any
-	if err := json.Unmarshal(found.Config, &configMap); err != nil {
-		return infer.ReadResponse[GitLabConnectorArgs, GitLabConnectorState]{}, nil
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
 	}
 
-	// Parse groups array
-	var groups []string
-	if groupsVal, ok := configMap["groups"].([]any); ok {
-		for _, g := range groupsVal {
-			if str, ok := g.(string); ok {
-				groups = append(groups, str)
-			}
-		}
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildGitLabConfig(req.State.GitLabConnectorArgs))
 	}
 
-	baseURL := GetStringPtr(configMap, "baseURL")
-	useLoginAsID := GetBoolPtr(configMap, "useLoginAsID")
-	getGroupsPermission := GetBoolPtr(configMap, "getGroupsPermission")
+	args := decodeGitLabConfig(configMap)
+	args.ConnectorId = found.Id
+	args.Name = found.Name
+	args.ClientSecret = readSecret(cfg, args.ClientSecret, req.State.ClientSecret, req.Inputs.ClientSecret)
 
-	args := GitLabConnectorArgs{
-		ConnectorId:         found.Id,
-		Name:                found.Name,
-		BaseURL:             baseURL,
-		ClientId:            GetString(configMap, "clientID"),
-		ClientSecret:        GetString(configMap, "clientSecret"),
-		RedirectUri:         GetString(configMap, "redirectURI"),
-		Groups:              groups,
-		UseLoginAsID:        useLoginAsID,
-		GetGroupsPermission: getGroupsPermission,
-	}
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
 
 	state := GitLabConnectorState{
 		GitLabConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
+		RawConfigOut:        redactedRawConfig(found.Config),
 	}
 
 	return infer.ReadResponse[GitLabConnectorArgs, GitLabConnectorState]{
@@ -240,8 +220,12 @@ func (c *GitLabConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[GitLabConnectorState]{}, err
+		}
 		state := GitLabConnectorState{
 			GitLabConnectorArgs: args,
+			CallbackUrl:         dexCallbackURL(ctx),
 		}
 		return infer.UpdateResponse[GitLabConnectorState]{
 			Output: state,
@@ -250,41 +234,23 @@ func (c *GitLabConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[GitLabConnectorState]{}, fmt.Errorf("Dex client not configured")
-	}
-
-	if args.ConnectorId != oldState.ConnectorId {
-		return infer.UpdateResponse[GitLabConnectorState]{}, fmt.Errorf("connectorId cannot be changed")
-	}
-	if args.BaseURL != nil && oldState.BaseURL != nil && *args.BaseURL != *oldState.BaseURL {
-		return infer.UpdateResponse[GitLabConnectorState]{}, fmt.Errorf("baseURL cannot be changed (would require replace)")
+		return infer.UpdateResponse[GitLabConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	gitlabConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
+	if err := checkImmutableFields("GitLabConnector", oldState.GitLabConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[GitLabConnectorState]{}, err
 	}
 
-	if args.BaseURL != nil && *args.BaseURL != "" {
-		gitlabConfig["baseURL"] = *args.BaseURL
-	}
-	if len(args.Groups) > 0 {
-		gitlabConfig["groups"] = args.Groups
-	}
-	if args.UseLoginAsID != nil {
-		gitlabConfig["useLoginAsID"] = *args.UseLoginAsID
-	}
-	if args.GetGroupsPermission != nil {
-		gitlabConfig["getGroupsPermission"] = *args.GetGroupsPermission
-	}
-
-	configBytes, err := json.Marshal(gitlabConfig)
+	configBytes, err := json.Marshal(buildGitLabConfig(args))
 	if err != nil {
 		return infer.UpdateResponse[GitLabConnectorState]{}, fmt.Errorf("failed to marshal GitLab config: %w", err)
 	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[GitLabConnectorState]{}, err
+	}
 
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
@@ -299,6 +265,7 @@ func (c *GitLabConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 
 	state := GitLabConnectorState{
 		GitLabConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
 	}
 
 	return infer.UpdateResponse[GitLabConnectorState]{
@@ -310,7 +277,7 @@ func (c *GitLabConnector) Update(ctx context.Context, req infer.UpdateRequest[Gi
 func (c *GitLabConnector) Delete(ctx context.Context, req infer.DeleteRequest[GitLabConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -318,7 +285,7 @@ func (c *GitLabConnector) Delete(ctx context.Context, req infer.DeleteRequest[Gi
 		deleteID = req.State.ConnectorId
 	}
 
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
@@ -331,5 +298,84 @@ func (c *GitLabConnector) Delete(ctx context.Context, req infer.DeleteRequest[Gi
 		return infer.DeleteResponse{}, provider.WrapError("delete", "gitlab-connector", deleteID, err)
 	}
 
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
 	return infer.DeleteResponse{}, nil
 }
+
+// applyGitLabDefaults fills in defaults for optional GitLabConnector inputs.
+func applyGitLabDefaults(args *GitLabConnectorArgs) {
+	if args.BaseURL == nil || *args.BaseURL == "" {
+		defaultURL := defaultGitLabBaseURL
+		args.BaseURL = &defaultURL
+	}
+	if args.UseLoginAsID == nil {
+		defaultUseLogin := false
+		args.UseLoginAsID = &defaultUseLogin
+	}
+	if args.GetGroupsPermission == nil {
+		defaultGetGroups := false
+		args.GetGroupsPermission = &defaultGetGroups
+	}
+}
+
+// buildGitLabConfig builds the Dex "gitlab" connector config from args.
+// The defaulted fields are always written so that Read round-trips them even
+// when Dex (or whatever created the connector) would otherwise omit them.
+func buildGitLabConfig(args GitLabConnectorArgs) map[string]any {
+	gitlabConfig := map[string]any{
+		"clientID":            args.ClientId,
+		"clientSecret":        args.ClientSecret,
+		"redirectURI":         args.RedirectUri,
+		"baseURL":             defaultGitLabBaseURL,
+		"useLoginAsID":        provider.PtrOr(args.UseLoginAsID, false),
+		"getGroupsPermission": provider.PtrOr(args.GetGroupsPermission, false),
+	}
+
+	if args.BaseURL != nil && *args.BaseURL != "" {
+		gitlabConfig["baseURL"] = *args.BaseURL
+	}
+	if len(args.Groups) > 0 {
+		gitlabConfig["groups"] = args.Groups
+	}
+	if args.AllGroupsDetailed != nil {
+		gitlabConfig["allGroupsDetailed"] = *args.AllGroupsDetailed
+	}
+	if args.RootCA != nil && *args.RootCA != "" {
+		gitlabConfig["rootCA"] = *args.RootCA
+	}
+	if args.RootCAData != nil && *args.RootCAData != "" {
+		gitlabConfig["rootCAData"] = encodeRootCAData(*args.RootCAData)
+	}
+
+	return gitlabConfig
+}
+
+// decodeGitLabConfig converts a Dex "gitlab" connector config into args.
+// Connectors created outside the provider (e.g. via Dex YAML) may omit the
+// keys that Check defaults, so those are filled in the same way and Read
+// round-trips.
+func decodeGitLabConfig(configMap map[string]any) GitLabConnectorArgs {
+	args := GitLabConnectorArgs{
+		BaseURL:             GetStringPtr(configMap, "baseURL"),
+		ClientId:            GetString(configMap, "clientID"),
+		ClientSecret:        GetString(configMap, "clientSecret"),
+		RedirectUri:         GetString(configMap, "redirectURI"),
+		UseLoginAsID:        GetBoolPtr(configMap, "useLoginAsID"),
+		GetGroupsPermission: GetBoolPtr(configMap, "getGroupsPermission"),
+		AllGroupsDetailed:   GetBoolPtr(configMap, "allGroupsDetailed"),
+		RootCA:              GetStringPtr(configMap, "rootCA"),
+		RootCAData:          decodeRootCAData(configMap),
+	}
+	if groups, ok := configMap["groups"].([]any); ok {
+		for _, g := range groups {
+			if str, ok := g.(string); ok {
+				args.Groups = append(args.Groups, str)
+			}
+		}
+	}
+	applyGitLabDefaults(&args)
+	return args
+}
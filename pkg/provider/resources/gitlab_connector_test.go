@@ -0,0 +1,70 @@
+package resources
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// roundTripConfig encodes config the way Create sends it to Dex and decodes
+// it the way Read gets it back.
+func roundTripConfig(t *testing.T, config map[string]any) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal config: %v", err)
+	}
+	return out
+}
+
+func TestGitLabConnectorDefaultsSurviveRefresh(t *testing.T) {
+	args := GitLabConnectorArgs{
+		ClientId:     "client",
+		ClientSecret: "secret",
+		RedirectUri:  "https://dex.example.com/callback",
+	}
+	applyGitLabDefaults(&args)
+
+	got := decodeGitLabConfig(roundTripConfig(t, buildGitLabConfig(args)))
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("refresh changed defaulted args:\n got  %+v\n want %+v", got, args)
+	}
+	if diff := diffResource("GitLabConnector", got, args); diff.HasChanges {
+		t.Errorf("refresh reported changes: %+v", diff.DetailedDiff)
+	}
+}
+
+func TestDecodeGitLabConfigFillsOmittedDefaults(t *testing.T) {
+	// A connector created outside the provider, e.g. from Dex YAML.
+	got := decodeGitLabConfig(map[string]any{
+		"clientID":     "client",
+		"clientSecret": "secret",
+		"redirectURI":  "https://dex.example.com/callback",
+	})
+
+	if got.BaseURL == nil || *got.BaseURL != defaultGitLabBaseURL {
+		t.Errorf("baseURL = %v, want %q", got.BaseURL, defaultGitLabBaseURL)
+	}
+	if got.UseLoginAsID == nil || *got.UseLoginAsID {
+		t.Errorf("useLoginAsID = %v, want false", got.UseLoginAsID)
+	}
+	if got.GetGroupsPermission == nil || *got.GetGroupsPermission {
+		t.Errorf("getGroupsPermission = %v, want false", got.GetGroupsPermission)
+	}
+}
+
+func TestBuildGitLabConfigSkipsEmptyRootCA(t *testing.T) {
+	empty := ""
+	args := GitLabConnectorArgs{RootCA: &empty, RootCAData: &empty}
+
+	config := buildGitLabConfig(args)
+	for _, key := range []string{"rootCA", "rootCAData"} {
+		if _, ok := config[key]; ok {
+			t.Errorf("empty %s was written to the config", key)
+		}
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
 	"github.com/pulumi/pulumi-go-provider/infer"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,21 +19,27 @@ import (
 
 // GoogleConnectorArgs defines inputs for GoogleConnector.
 type GoogleConnectorArgs struct {
-	ConnectorId            string            `pulumi:"connectorId"`
-	Name                   string            `pulumi:"name"`
-	ClientId               string            `pulumi:"clientId"`
-	ClientSecret           string            `pulumi:"clientSecret" provider:"secret"`
-	RedirectUri            string            `pulumi:"redirectUri"`
-	PromptType             *string           `pulumi:"promptType,optional"`
-	HostedDomains          []string          `pulumi:"hostedDomains,optional"`
-	Groups                 []string          `pulumi:"groups,optional"`
-	ServiceAccountFilePath *string           `pulumi:"serviceAccountFilePath,optional"`
-	DomainToAdminEmail     map[string]string `pulumi:"domainToAdminEmail,optional"`
+	ConnectorId                    string            `pulumi:"connectorId"`
+	Name                           string            `pulumi:"name"`
+	ClientId                       string            `pulumi:"clientId"`
+	ClientSecret                   string            `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri                    string            `pulumi:"redirectUri"`
+	PromptType                     *string           `pulumi:"promptType,optional"`
+	HostedDomains                  []string          `pulumi:"hostedDomains,optional"`
+	Groups                         []string          `pulumi:"groups,optional"`
+	ServiceAccountFilePath         *string           `pulumi:"serviceAccountFilePath,optional"`
+	DomainToAdminEmail             map[string]string `pulumi:"domainToAdminEmail,optional"`
+	AdminEmail                     *string           `pulumi:"adminEmail,optional"`
+	ServiceAccountJSON             *string           `pulumi:"serviceAccountJSON,optional" provider:"secret"`
+	FetchTransitiveGroupMembership *bool             `pulumi:"fetchTransitiveGroupMembership,optional"`
+	TimeoutSeconds                 *int              `pulumi:"timeoutSeconds,optional"`
 }
 
 // GoogleConnectorState defines outputs for GoogleConnector.
 type GoogleConnectorState struct {
 	GoogleConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
 }
 
 // GoogleConnector manages a Google connector in Dex.
@@ -56,11 +62,17 @@ func (c *GoogleConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.Groups, "List of Google Groups. Only users in these groups will be allowed to authenticate.")
 	a.Describe(&c.ServiceAccountFilePath, "Path to Google service account JSON file. Required for group-based access control.")
 	a.Describe(&c.DomainToAdminEmail, "Map of domain names to admin email addresses. Used for group lookups in Google Workspace.")
+	a.Describe(&c.AdminEmail, "Email of a Google Workspace admin to impersonate for group lookups. Prefer domainToAdminEmail for multiple domains.")
+	a.Describe(&c.ServiceAccountJSON, "Google service account key JSON, inline. Mutually exclusive with serviceAccountFilePath.")
+	a.Describe(&c.FetchTransitiveGroupMembership, "If true, groups the user belongs to indirectly through nested groups are also returned. Defaults to false.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for GoogleConnectorState.
 func (c *GoogleConnectorState) Annotate(a infer.Annotator) {
 	// GoogleConnectorState embeds GoogleConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
 }
 
 // Check validates inputs.
@@ -70,26 +82,49 @@ func (c *GoogleConnector) Check(ctx context.Context, req infer.CheckRequest) (in
 		return infer.CheckResponse[GoogleConnectorArgs]{Failures: failures}, err
 	}
 
-	// Apply defaults
-	if args.PromptType == nil || *args.PromptType == "" {
-		defaultPrompt := "consent"
-		args.PromptType = &defaultPrompt
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	if args.ServiceAccountJSON != nil && *args.ServiceAccountJSON != "" {
+		if args.ServiceAccountFilePath != nil && *args.ServiceAccountFilePath != "" {
+			failures = append(failures, p.CheckFailure{
+				Property: "serviceAccountJSON",
+				Reason:   "serviceAccountJSON and serviceAccountFilePath are mutually exclusive; set only one",
+			})
+		} else if !json.Valid([]byte(*args.ServiceAccountJSON)) {
+			failures = append(failures, p.CheckFailure{
+				Property: "serviceAccountJSON",
+				Reason:   "must be a valid JSON service account key",
+			})
+		}
 	}
 
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+
+	applyGoogleDefaults(&args)
+
 	return infer.CheckResponse[GoogleConnectorArgs]{
 		Inputs:   args,
 		Failures: failures,
 	}, nil
 }
 
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *GoogleConnector) Diff(ctx context.Context, req infer.DiffRequest[GoogleConnectorArgs, GoogleConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("GoogleConnector", req.State.GoogleConnectorArgs, req.Inputs), nil
+}
+
 // Create creates a new Google connector.
 func (c *GoogleConnector) Create(ctx context.Context, req infer.CreateRequest[GoogleConnectorArgs]) (infer.CreateResponse[GoogleConnectorState], error) {
 	args := req.Inputs
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[GoogleConnectorState]{}, err
+		}
 		state := GoogleConnectorState{
 			GoogleConnectorArgs: args,
+			CallbackUrl:         dexCallbackURL(ctx),
 		}
 		return infer.CreateResponse[GoogleConnectorState]{
 			ID:     args.ConnectorId,
@@ -99,33 +134,10 @@ func (c *GoogleConnector) Create(ctx context.Context, req infer.CreateRequest[Go
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[GoogleConnectorState]{}, fmt.Errorf("Dex client not configured")
-	}
-
-	// Build Google connector config
-	googleConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-	}
-
-	if args.PromptType != nil {
-		googleConfig["promptType"] = *args.PromptType
-	}
-	if len(args.HostedDomains) > 0 {
-		googleConfig["hostedDomains"] = args.HostedDomains
-	}
-	if len(args.Groups) > 0 {
-		googleConfig["groups"] = args.Groups
-	}
-	if args.ServiceAccountFilePath != nil {
-		googleConfig["serviceAccountFilePath"] = *args.ServiceAccountFilePath
-	}
-	if len(args.DomainToAdminEmail) > 0 {
-		googleConfig["domainToAdminEmail"] = args.DomainToAdminEmail
+		return infer.CreateResponse[GoogleConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	configBytes, err := json.Marshal(googleConfig)
+	configBytes, err := json.Marshal(buildGoogleConfig(args))
 	if err != nil {
 		return infer.CreateResponse[GoogleConnectorState]{}, fmt.Errorf("failed to marshal Google config: %w", err)
 	}
@@ -137,7 +149,7 @@ func (c *GoogleConnector) Create(ctx context.Context, req infer.CreateRequest[Go
 		Config: configBytes,
 	}
 
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
@@ -148,11 +160,15 @@ func (c *GoogleConnector) Create(ctx context.Context, req infer.CreateRequest[Go
 	}
 
 	if resp.AlreadyExists {
-		return infer.CreateResponse[GoogleConnectorState]{}, fmt.Errorf("connector with id %q already exists", args.ConnectorId)
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[GoogleConnectorState]{}, err
+		}
 	}
 
 	state := GoogleConnectorState{
 		GoogleConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
 	}
 
 	return infer.CreateResponse[GoogleConnectorState]{
@@ -165,23 +181,12 @@ func (c *GoogleConnector) Create(ctx context.Context, req infer.CreateRequest[Go
 func (c *GoogleConnector) Read(ctx context.Context, req infer.ReadRequest[GoogleConnectorArgs, GoogleConnectorState]) (infer.ReadResponse[GoogleConnectorArgs, GoogleConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[GoogleConnectorArgs, GoogleConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[GoogleConnectorArgs, GoogleConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	listCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[GoogleConnectorArgs, GoogleConnectorState]{}, fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, conn := range listResp.Connectors {
-		if conn.Id == req.ID {
-			found = conn
-			break
-		}
+		return infer.ReadResponse[GoogleConnectorArgs, GoogleConnectorState]{}, err
 	}
 
 	if found == nil {
@@ -189,8 +194,16 @@ func (c *GoogleConnector) Read(ctx context.Context, req infer.ReadRequest[Google
 	}
 
 	var configMap map[string]any
-	if err := json.Unmarshal(found.Config, &configMap); err != nil {
-		return infer.ReadResponse[GoogleConnectorArgs, GoogleConnectorState]{}, nil
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildGoogleConfig(req.State.GoogleConnectorArgs))
 	}
 
 	// Parse arrays
@@ -212,31 +225,42 @@ func (c *GoogleConnector) Read(ctx context.Context, req infer.ReadRequest[Google
 		}
 	}
 
-	// Parse domainToAdminEmail map
-	domainToAdminEmail := make(map[string]string)
+	// Parse domainToAdminEmail map; left nil when empty so it round-trips
+	// against a program that does not set it.
+	var domainToAdminEmail map[string]string
 	if domainMap, ok := configMap["domainToAdminEmail"].(map[string]any); ok {
 		for k, v := range domainMap {
 			if str, ok := v.(string); ok {
+				if domainToAdminEmail == nil {
+					domainToAdminEmail = make(map[string]string, len(domainMap))
+				}
 				domainToAdminEmail[k] = str
 			}
 		}
 	}
 
 	args := GoogleConnectorArgs{
-		ConnectorId:            found.Id,
-		Name:                   found.Name,
-		ClientId:               GetString(configMap, "clientID"),
-		ClientSecret:           GetString(configMap, "clientSecret"),
-		RedirectUri:            GetString(configMap, "redirectURI"),
-		PromptType:             GetStringPtr(configMap, "promptType"),
-		HostedDomains:          hostedDomains,
-		Groups:                 groups,
-		ServiceAccountFilePath: GetStringPtr(configMap, "serviceAccountFilePath"),
-		DomainToAdminEmail:     domainToAdminEmail,
-	}
+		ConnectorId:                    found.Id,
+		Name:                           found.Name,
+		ClientId:                       GetString(configMap, "clientID"),
+		ClientSecret:                   readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
+		RedirectUri:                    GetString(configMap, "redirectURI"),
+		PromptType:                     GetStringPtr(configMap, "promptType"),
+		HostedDomains:                  hostedDomains,
+		Groups:                         groups,
+		ServiceAccountFilePath:         GetStringPtr(configMap, "serviceAccountFilePath"),
+		DomainToAdminEmail:             domainToAdminEmail,
+		AdminEmail:                     GetStringPtr(configMap, "adminEmail"),
+		ServiceAccountJSON:             readSecretPtr(cfg, GetStringPtr(configMap, "serviceAccountJSON"), req.State.ServiceAccountJSON, req.Inputs.ServiceAccountJSON),
+		FetchTransitiveGroupMembership: GetBoolPtr(configMap, "fetchTransitiveGroupMembership"),
+	}
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
 
 	state := GoogleConnectorState{
 		GoogleConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
+		RawConfigOut:        redactedRawConfig(found.Config),
 	}
 
 	return infer.ReadResponse[GoogleConnectorArgs, GoogleConnectorState]{
@@ -253,8 +277,12 @@ func (c *GoogleConnector) Update(ctx context.Context, req infer.UpdateRequest[Go
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[GoogleConnectorState]{}, err
+		}
 		state := GoogleConnectorState{
 			GoogleConnectorArgs: args,
+			CallbackUrl:         dexCallbackURL(ctx),
 		}
 		return infer.UpdateResponse[GoogleConnectorState]{
 			Output: state,
@@ -263,41 +291,23 @@ func (c *GoogleConnector) Update(ctx context.Context, req infer.UpdateRequest[Go
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[GoogleConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.UpdateResponse[GoogleConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	if args.ConnectorId != oldState.ConnectorId {
-		return infer.UpdateResponse[GoogleConnectorState]{}, fmt.Errorf("connectorId cannot be changed")
+	if err := checkImmutableFields("GoogleConnector", oldState.GoogleConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[GoogleConnectorState]{}, err
 	}
 
-	googleConfig := map[string]any{
-		"clientID":     args.ClientId,
-		"clientSecret": args.ClientSecret,
-		"redirectURI":  args.RedirectUri,
-	}
-
-	if args.PromptType != nil {
-		googleConfig["promptType"] = *args.PromptType
-	}
-	if len(args.HostedDomains) > 0 {
-		googleConfig["hostedDomains"] = args.HostedDomains
-	}
-	if len(args.Groups) > 0 {
-		googleConfig["groups"] = args.Groups
-	}
-	if args.ServiceAccountFilePath != nil {
-		googleConfig["serviceAccountFilePath"] = *args.ServiceAccountFilePath
-	}
-	if len(args.DomainToAdminEmail) > 0 {
-		googleConfig["domainToAdminEmail"] = args.DomainToAdminEmail
-	}
-
-	configBytes, err := json.Marshal(googleConfig)
+	configBytes, err := json.Marshal(buildGoogleConfig(args))
 	if err != nil {
 		return infer.UpdateResponse[GoogleConnectorState]{}, fmt.Errorf("failed to marshal Google config: %w", err)
 	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[GoogleConnectorState]{}, err
+	}
 
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
 	defer cancel()
 
 	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
@@ -312,6 +322,7 @@ func (c *GoogleConnector) Update(ctx context.Context, req infer.UpdateRequest[Go
 
 	state := GoogleConnectorState{
 		GoogleConnectorArgs: args,
+		CallbackUrl:         dexCallbackURL(ctx),
 	}
 
 	return infer.UpdateResponse[GoogleConnectorState]{
@@ -323,7 +334,7 @@ func (c *GoogleConnector) Update(ctx context.Context, req infer.UpdateRequest[Go
 func (c *GoogleConnector) Delete(ctx context.Context, req infer.DeleteRequest[GoogleConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -331,7 +342,7 @@ func (c *GoogleConnector) Delete(ctx context.Context, req infer.DeleteRequest[Go
 		deleteID = req.State.ConnectorId
 	}
 
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
@@ -344,5 +355,53 @@ func (c *GoogleConnector) Delete(ctx context.Context, req infer.DeleteRequest[Go
 		return infer.DeleteResponse{}, provider.WrapError("delete", "google-connector", deleteID, err)
 	}
 
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
 	return infer.DeleteResponse{}, nil
 }
+
+// applyGoogleDefaults fills in defaults for optional GoogleConnector inputs.
+func applyGoogleDefaults(args *GoogleConnectorArgs) {
+	if args.PromptType == nil || *args.PromptType == "" {
+		defaultPrompt := "consent"
+		args.PromptType = &defaultPrompt
+	}
+}
+
+// buildGoogleConfig builds the Dex "google" connector config from args.
+func buildGoogleConfig(args GoogleConnectorArgs) map[string]any {
+	googleConfig := map[string]any{
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+	}
+
+	if args.PromptType != nil {
+		googleConfig["promptType"] = *args.PromptType
+	}
+	if len(args.HostedDomains) > 0 {
+		googleConfig["hostedDomains"] = args.HostedDomains
+	}
+	if len(args.Groups) > 0 {
+		googleConfig["groups"] = args.Groups
+	}
+	if args.ServiceAccountFilePath != nil {
+		googleConfig["serviceAccountFilePath"] = *args.ServiceAccountFilePath
+	}
+	if len(args.DomainToAdminEmail) > 0 {
+		googleConfig["domainToAdminEmail"] = args.DomainToAdminEmail
+	}
+	if args.AdminEmail != nil {
+		googleConfig["adminEmail"] = *args.AdminEmail
+	}
+	if args.ServiceAccountJSON != nil {
+		googleConfig["serviceAccountJSON"] = *args.ServiceAccountJSON
+	}
+	if args.FetchTransitiveGroupMembership != nil {
+		googleConfig["fetchTransitiveGroupMembership"] = *args.FetchTransitiveGroupMembership
+	}
+
+	return googleConfig
+}
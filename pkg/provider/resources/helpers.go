@@ -1,5 +1,23 @@
 package resources
 
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
 // ============================================================================
 // Helper functions for connector implementations
 // ============================================================================
@@ -33,3 +51,367 @@ func GetBoolPtr(m map[string]any, key string) *bool {
 	}
 	return nil
 }
+
+// redactedValue replaces secret values in configs returned to users.
+const redactedValue = "[REDACTED]"
+
+// secretConfigKeys lists Dex connector config keys whose values are secrets.
+var secretConfigKeys = map[string]bool{
+	"clientSecret":       true,
+	"bindPW":             true,
+	"serviceAccountJSON": true,
+}
+
+// RedactConfig returns a copy of a decoded connector config with secret values
+// replaced by a placeholder. Nested maps and slices are redacted recursively.
+func RedactConfig(config map[string]any) map[string]any {
+	if config == nil {
+		return nil
+	}
+	out := make(map[string]any, len(config))
+	for k, v := range config {
+		if secretConfigKeys[k] {
+			if s, ok := v.(string); ok && s == "" {
+				out[k] = s
+			} else {
+				out[k] = redactedValue
+			}
+			continue
+		}
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return RedactConfig(val)
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// isScopeTokenChar reports whether r is allowed in an OAuth 2.0 scope token.
+// RFC 6749 section 3.3 permits any printable ASCII character except space,
+// double quote, and backslash, i.e. %x21 / %x23-5B / %x5D-7E.
+func isScopeTokenChar(r rune) bool {
+	return r == 0x21 || (r >= 0x23 && r <= 0x5B) || (r >= 0x5D && r <= 0x7E)
+}
+
+// validateScopes checks that every scope is a non-empty RFC 6749 scope token.
+// This deliberately accepts vendor-specific formats such as Azure resource
+// scopes ("api://<app-id>/.default") and Cognito custom scopes
+// ("https://api.example.com/orders.read").
+func validateScopes(property string, scopes []string) []p.CheckFailure {
+	var failures []p.CheckFailure
+	for i, scope := range scopes {
+		if scope == "" {
+			failures = append(failures, p.CheckFailure{
+				Property: fmt.Sprintf("%s[%d]", property, i),
+				Reason:   "scope must not be empty",
+			})
+			continue
+		}
+		for _, r := range scope {
+			if !isScopeTokenChar(r) {
+				failures = append(failures, p.CheckFailure{
+					Property: fmt.Sprintf("%s[%d]", property, i),
+					Reason:   fmt.Sprintf("scope %q contains invalid character %q; scopes may contain printable ASCII except space, '\"' and '\\'", scope, r),
+				})
+				break
+			}
+		}
+	}
+	return failures
+}
+
+// defaultOidcScopes returns scopes if set, otherwise a copy of the provider's
+// defaultOidcScopes, or fallback when the provider does not set them either.
+func defaultOidcScopes(cfg provider.DexConfig, scopes []string, fallback ...string) []string {
+	if len(scopes) > 0 {
+		return scopes
+	}
+	if len(cfg.DefaultOidcScopes) > 0 {
+		return append([]string(nil), cfg.DefaultOidcScopes...)
+	}
+	return fallback
+}
+
+// checkOpenIDScope warns (see checkWarning) when an explicitly set list of
+// OIDC scopes omits "openid", without which the upstream provider returns no
+// ID token and logins fail. An empty list means the connector's defaults,
+// which include it.
+func checkOpenIDScope(ctx context.Context, cfg provider.DexConfig, property string, scopes []string) []p.CheckFailure {
+	if len(scopes) == 0 {
+		return nil
+	}
+	for _, scope := range scopes {
+		if scope == "openid" {
+			return nil
+		}
+	}
+	return checkWarning(ctx, cfg, property, `scopes do not include "openid"; OIDC login fails without it`)
+}
+
+// redactedRawConfig returns the connector config bytes as stored in Dex, with
+// secret values redacted. Configs that are not JSON objects are returned as nil,
+// since they cannot be redacted reliably.
+func redactedRawConfig(config []byte) *string {
+	var m map[string]any
+	if err := json.Unmarshal(trimConfigBytes(config), &m); err != nil {
+		return nil
+	}
+	out, err := json.Marshal(RedactConfig(m))
+	if err != nil {
+		return nil
+	}
+	s := string(out)
+	return &s
+}
+
+// utf8BOM is the byte order mark some storage backends prepend to stored text.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// trimConfigBytes strips a leading UTF-8 BOM and surrounding whitespace from
+// connector config bytes returned by Dex, which some storage backends add.
+func trimConfigBytes(config []byte) []byte {
+	return bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(config), utf8BOM))
+}
+
+// unparseableConnectorRead is the Read result for a connector that exists in
+// Dex but whose config cannot be parsed. The previous inputs and state are kept
+// so the connector is not mistaken for deleted; with no previous state (import)
+// there is nothing to keep, so an error is returned.
+func unparseableConnectorRead[I, O any](ctx context.Context, req infer.ReadRequest[I, O], id string, err error) (infer.ReadResponse[I, O], error) {
+	if reflect.ValueOf(req.State).IsZero() {
+		return infer.ReadResponse[I, O]{}, fmt.Errorf("connector %q has a config that is not valid JSON: %w", id, err)
+	}
+	p.GetLogger(ctx).Warningf("connector %q has a config that is not valid JSON; keeping the previous state: %v", id, err)
+	return infer.ReadResponse[I, O]{
+		ID:     id,
+		Inputs: req.Inputs,
+		State:  req.State,
+	}, nil
+}
+
+// adoptExistingConnector handles an AlreadyExists response from CreateConnector
+// for the connector resources. A connector matching want, e.g. one left behind
+// by an interrupted apply, is adopted so Pulumi tracks it and destroy removes
+// it. Any other connector with the same ID is not taken over; see
+// matchExistingConnector.
+func adoptExistingConnector(ctx context.Context, cfg provider.DexConfig, want *api.Connector, timeoutSeconds *int) error {
+	if err := matchExistingConnector(ctx, cfg, want, timeoutSeconds); err != nil {
+		return err
+	}
+	p.GetLogger(ctx).Infof("connector %q already exists in Dex with the same configuration; adopting it", want.Id)
+	return nil
+}
+
+// readExtraOidc returns the current Dex values of the extraOidc keys the
+// program set, so drift in them is detected on refresh. Other config keys are
+// not attributed to extraOidc, since they belong to the typed fields.
+func readExtraOidc(config, extra map[string]any) map[string]any {
+	if len(extra) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(extra))
+	for k := range extra {
+		if v, ok := config[k]; ok {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// dexCallbackURL returns Dex's callback URL, the provider's dexPublicUrl with
+// Dex's /callback path, or nil when dexPublicUrl is not configured.
+func dexCallbackURL(ctx context.Context) *string {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	base := strings.TrimRight(provider.PtrOr(cfg.DexPublicUrl, ""), "/")
+	if base == "" {
+		return nil
+	}
+	callback := base + "/callback"
+	return &callback
+}
+
+// deleteVerifyAttempts is how often verifyDeleted checks before giving up.
+const deleteVerifyAttempts = 5
+
+// verifyDeleted confirms after a successful delete call that the object is
+// gone, for storage backends that apply deletes with a delay. exists is
+// polled up to deleteVerifyAttempts times, waiting the provider's
+// deleteVerifyDelayMs before the first check and doubling the wait after each.
+// With deleteVerify set to false it returns nil without checking.
+func verifyDeleted(ctx context.Context, cfg provider.DexConfig, kind, id string, exists func(context.Context) (bool, error)) error {
+	if !provider.PtrOr(cfg.DeleteVerify, true) {
+		return nil
+	}
+	wait := time.Duration(provider.PtrOr(cfg.DeleteVerifyDelayMs, 200)) * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("delete reported success but verifying that %s %q is gone was interrupted: %w", kind, id, ctx.Err())
+		case <-timer.C:
+		}
+
+		found, err := exists(ctx)
+		if err != nil {
+			// Can't verify, but the delete call succeeded; fail to be safe
+			// rather than silently ignore the verification failure.
+			return fmt.Errorf("delete reported success but verification failed (%w)", err)
+		}
+		if !found {
+			return nil
+		}
+		if attempt >= deleteVerifyAttempts {
+			return fmt.Errorf("delete reported success but %s %q still exists in Dex after %d checks; set deleteVerifyDelayMs higher for storage backends that apply deletes slowly, or deleteVerify to false", kind, id, attempt)
+		}
+		wait *= 2
+	}
+}
+
+// checkPreviewConnectivity runs the provider's optional preview connectivity
+// check; see provider.DexConfig.CheckPreviewConnectivity. Create and Update
+// call it first thing in their dry-run branch.
+func checkPreviewConnectivity(ctx context.Context) error {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	return cfg.CheckPreviewConnectivity(ctx)
+}
+
+// checkWarning reports a soft validation rule. By default the reason is
+// logged as a warning so preview surfaces it without blocking; with the
+// provider's strictValidation flag set it is returned as a check failure.
+func checkWarning(ctx context.Context, cfg provider.DexConfig, property, reason string) []p.CheckFailure {
+	if provider.PtrOr(cfg.StrictValidation, false) {
+		return []p.CheckFailure{{Property: property, Reason: reason}}
+	}
+	p.GetLogger(ctx).Warningf("%s: %s", property, reason)
+	return nil
+}
+
+// maxConnectorConfigBytes is the marshaled config size above which Check warns.
+// Dex's gRPC server rejects requests over 4 MiB by default, and large configs
+// are almost always inlined certificates that could be referenced by path.
+const maxConnectorConfigBytes = 1 << 20
+
+// checkConfigSize warns (see checkWarning) when a marshaled connector config is
+// larger than maxConnectorConfigBytes.
+func checkConfigSize(ctx context.Context, cfg provider.DexConfig, property string, config []byte) []p.CheckFailure {
+	if len(config) <= maxConnectorConfigBytes {
+		return nil
+	}
+	return checkWarning(ctx, cfg, property, fmt.Sprintf("connector config is %d bytes, which may exceed gRPC message size limits; consider referencing large values such as CA certificates by path, or raise the provider's maxSendMsgSizeMB", len(config)))
+}
+
+// validateAbsoluteURL checks that value is an absolute http(s) URL with a host.
+// Empty values are left to the required-field check.
+func validateAbsoluteURL(property, value string) []p.CheckFailure {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return []p.CheckFailure{{Property: property, Reason: fmt.Sprintf("must be an absolute http(s) URL, got %q", value)}}
+	}
+	return nil
+}
+
+// validateRedirectURI checks a connector's redirectUri, which is Dex's own
+// callback URL. It must be an absolute http(s) URL; plain http is accepted for
+// localhost during development and warned about (see checkWarning) otherwise,
+// since the authorization code would travel unencrypted.
+func validateRedirectURI(ctx context.Context, cfg provider.DexConfig, property, value string) []p.CheckFailure {
+	if failures := validateAbsoluteURL(property, value); len(failures) > 0 || value == "" {
+		return failures
+	}
+	u, _ := url.Parse(value)
+	if u.Scheme == "https" {
+		return nil
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return nil
+	}
+	return checkWarning(ctx, cfg, property, fmt.Sprintf("should use https; plain http is only expected for localhost, got %q", value))
+}
+
+// validateRootCA checks that at most one of the rootCA file path and the inline
+// rootCAData PEM is set, and that rootCAData holds a PEM certificate.
+func validateRootCA(rootCA, rootCAData *string) []p.CheckFailure {
+	hasPath := rootCA != nil && *rootCA != ""
+	hasData := rootCAData != nil && *rootCAData != ""
+	if hasPath && hasData {
+		return []p.CheckFailure{{Property: "rootCAData", Reason: "rootCA and rootCAData are mutually exclusive; set only one"}}
+	}
+	if hasData {
+		if block, _ := pem.Decode([]byte(*rootCAData)); block == nil || block.Type != "CERTIFICATE" {
+			return []p.CheckFailure{{Property: "rootCAData", Reason: "must contain a PEM-encoded certificate"}}
+		}
+	}
+	return nil
+}
+
+// encodeRootCAData converts an inline PEM into Dex's rootCAData value. Dex
+// decodes rootCAData into a byte slice, so it is base64-encoded in JSON.
+func encodeRootCAData(pemData string) string {
+	return base64.StdEncoding.EncodeToString([]byte(pemData))
+}
+
+// decodeRootCAData returns the PEM stored in a config's rootCAData key, or nil
+// when it is absent. A value that is not valid base64 is returned verbatim.
+func decodeRootCAData(m map[string]any) *string {
+	encoded := GetStringPtr(m, "rootCAData")
+	if encoded == nil {
+		return nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(*encoded)
+	if err != nil {
+		return encoded
+	}
+	s := string(decoded)
+	return &s
+}
+
+// dexObject is an object returned in a Dex list response, such as
+// *api.Connector or *api.Client.
+type dexObject interface {
+	GetId() string
+}
+
+// findByID returns the object with the given ID from a Dex list response, or
+// the zero value (nil) if there is none. Dex's list calls are the only way to
+// look objects up, so every lookup goes through here; connector lookups that
+// have a provider config go through findConnectorById instead, which uses the
+// ID index of cfg's connector cache.
+func findByID[T dexObject](objects []T, id string) T {
+	for _, obj := range objects {
+		if obj.GetId() == id {
+			return obj
+		}
+	}
+	var zero T
+	return zero
+}
+
+// indexByID maps the objects of a Dex list response by ID, for callers that
+// look up more than one of them.
+func indexByID[T dexObject](objects []T) map[string]T {
+	index := make(map[string]T, len(objects))
+	for _, obj := range objects {
+		index[obj.GetId()] = obj
+	}
+	return index
+}
@@ -0,0 +1,82 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+func TestValidateScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		scopes    []string
+		wantProps []string
+	}{
+		{name: "standard OIDC scopes", scopes: []string{"openid", "profile", "email", "offline_access"}},
+		{name: "Azure resource scope", scopes: []string{"openid", "api://6f9b1c2e-0000-4000-8000-000000000000/.default"}},
+		{name: "Azure Graph scope", scopes: []string{"https://graph.microsoft.com/User.Read"}},
+		{name: "Cognito custom scope", scopes: []string{"https://api.example.com/orders.read", "aws.cognito.signin.user.admin"}},
+		{name: "empty scope", scopes: []string{"openid", ""}, wantProps: []string{"scopes[1]"}},
+		{name: "space", scopes: []string{"openid email"}, wantProps: []string{"scopes[0]"}},
+		{name: "double quote and backslash", scopes: []string{`a"b`, `a\b`}, wantProps: []string{"scopes[0]", "scopes[1]"}},
+		{name: "non-ASCII", scopes: []string{"prüfen"}, wantProps: []string{"scopes[0]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := validateScopes("scopes", tt.scopes)
+			if len(failures) != len(tt.wantProps) {
+				t.Fatalf("validateScopes() = %+v, want failures for %v", failures, tt.wantProps)
+			}
+			for i, f := range failures {
+				if f.Property != tt.wantProps[i] {
+					t.Errorf("failure %d property = %q, want %q", i, f.Property, tt.wantProps[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTrimConfigBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"plain", `{"issuer":"https://idp.example.com"}`},
+		{"leading and trailing whitespace", " \n\t{\"issuer\":\"https://idp.example.com\"}\r\n "},
+		{"BOM", "\xef\xbb\xbf{\"issuer\":\"https://idp.example.com\"}"},
+		{"whitespace around BOM", "\n\xef\xbb\xbf  {\"issuer\":\"https://idp.example.com\"}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(trimConfigBytes([]byte(tt.config))); got != `{"issuer":"https://idp.example.com"}` {
+				t.Errorf("trimConfigBytes() = %q", got)
+			}
+		})
+	}
+}
+
+func TestUnparseableConnectorRead(t *testing.T) {
+	prior := GitHubConnectorState{GitHubConnectorArgs: GitHubConnectorArgs{ConnectorId: "gh", Name: "GitHub"}}
+	req := infer.ReadRequest[GitHubConnectorArgs, GitHubConnectorState]{
+		ID:     "gh",
+		Inputs: prior.GitHubConnectorArgs,
+		State:  prior,
+	}
+
+	resp, err := unparseableConnectorRead(context.Background(), req, "gh", errors.New("bad JSON"))
+	if err != nil {
+		t.Fatalf("unparseableConnectorRead() = %v, want the previous state", err)
+	}
+	if resp.ID != "gh" || !reflect.DeepEqual(resp.State, prior) {
+		t.Errorf("resp = %+v, want ID gh and the previous state", resp)
+	}
+
+	// On import there is no previous state to keep.
+	req.State = GitHubConnectorState{}
+	if _, err := unparseableConnectorRead(context.Background(), req, "gh", errors.New("bad JSON")); err == nil {
+		t.Error("unparseableConnectorRead() on import = nil, want an error")
+	}
+}
@@ -0,0 +1,378 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// KeycloakOidcConnector - Uses generic OIDC connector (type: "oidc")
+// ============================================================================
+
+// keycloakIssuerRegex matches Keycloak issuers of the form <baseUrl>/realms/<realm>.
+var keycloakIssuerRegex = regexp.MustCompile(`^(https?://.+)/realms/([^/]+)$`)
+
+// KeycloakOidcConnectorArgs defines inputs for KeycloakOidcConnector.
+type KeycloakOidcConnectorArgs struct {
+	ConnectorId    string         `pulumi:"connectorId"`
+	Name           string         `pulumi:"name"`
+	BaseUrl        string         `pulumi:"baseUrl"`
+	Realm          string         `pulumi:"realm"`
+	ClientId       string         `pulumi:"clientId"`
+	ClientSecret   string         `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri    string         `pulumi:"redirectUri"`
+	Scopes         []string       `pulumi:"scopes,optional"`
+	ExtraOidc      map[string]any `pulumi:"extraOidc,optional"`
+	TimeoutSeconds *int           `pulumi:"timeoutSeconds,optional"`
+}
+
+// KeycloakOidcConnectorState defines outputs for KeycloakOidcConnector.
+type KeycloakOidcConnectorState struct {
+	KeycloakOidcConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
+}
+
+// KeycloakOidcConnector manages a Keycloak realm connector using Dex's generic OIDC connector.
+type KeycloakOidcConnector struct{}
+
+// Annotate provides schema metadata.
+func (c *KeycloakOidcConnector) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages a Keycloak realm connector in Dex using the generic OIDC connector (type: oidc). The issuer is derived from the base URL and realm as '<baseUrl>/realms/<realm>'.")
+}
+
+// Annotate provides schema metadata for KeycloakOidcConnectorArgs.
+func (c *KeycloakOidcConnectorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the Keycloak connector.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.BaseUrl, "Keycloak base URL, e.g. 'https://keycloak.example.com' (include '/auth' for Keycloak versions before 17). Changing this forces a replacement.")
+	a.Describe(&c.Realm, "Keycloak realm name. Changing this forces a replacement.")
+	a.Describe(&c.ClientId, "Keycloak client ID.")
+	a.Describe(&c.ClientSecret, "Keycloak client secret.")
+	a.Describe(&c.RedirectUri, "Redirect URI registered in the Keycloak client. Must match Dex's callback URL.")
+	a.Describe(&c.Scopes, "OIDC scopes to request from Keycloak. Defaults to ['openid', 'profile', 'email'] if not specified.")
+	a.Describe(&c.ExtraOidc, "Additional OIDC configuration fields as key-value pairs for advanced scenarios.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for KeycloakOidcConnectorState.
+func (c *KeycloakOidcConnectorState) Annotate(a infer.Annotator) {
+	// KeycloakOidcConnectorState embeds KeycloakOidcConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// Check validates inputs.
+func (c *KeycloakOidcConnector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[KeycloakOidcConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[KeycloakOidcConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[KeycloakOidcConnectorArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	if args.BaseUrl != "" {
+		if u, err := url.Parse(args.BaseUrl); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+			failures = append(failures, p.CheckFailure{
+				Property: "baseUrl",
+				Reason:   fmt.Sprintf("must be an absolute http(s) URL without query or fragment, got %q", args.BaseUrl),
+			})
+		} else if strings.Contains(u.Path, "/realms/") {
+			failures = append(failures, p.CheckFailure{
+				Property: "baseUrl",
+				Reason:   "must not include the realm path; set realm separately",
+			})
+		}
+	}
+
+	if args.Realm != "" && strings.ContainsAny(args.Realm, "/?#") {
+		failures = append(failures, p.CheckFailure{
+			Property: "realm",
+			Reason:   "must be a realm name, not a path",
+		})
+	}
+
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+	failures = append(failures, checkOpenIDScope(ctx, cfg, "scopes", args.Scopes)...)
+
+	applyKeycloakOidcDefaults(cfg, &args)
+
+	return infer.CheckResponse[KeycloakOidcConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as
+// replacements; baseUrl and realm are baked into the issuer.
+func (c *KeycloakOidcConnector) Diff(ctx context.Context, req infer.DiffRequest[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("KeycloakOidcConnector", req.State.KeycloakOidcConnectorArgs, req.Inputs), nil
+}
+
+// Create creates a new Keycloak OIDC connector.
+func (c *KeycloakOidcConnector) Create(ctx context.Context, req infer.CreateRequest[KeycloakOidcConnectorArgs]) (infer.CreateResponse[KeycloakOidcConnectorState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[KeycloakOidcConnectorState]{}, err
+		}
+		state := KeycloakOidcConnectorState{
+			KeycloakOidcConnectorArgs: args,
+			CallbackUrl:               dexCallbackURL(ctx),
+		}
+		return infer.CreateResponse[KeycloakOidcConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[KeycloakOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := json.Marshal(buildKeycloakOidcConfig(args))
+	if err != nil {
+		return infer.CreateResponse[KeycloakOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "oidc",
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[KeycloakOidcConnectorState]{}, provider.WrapError("create", "keycloak-oidc-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[KeycloakOidcConnectorState]{}, err
+		}
+	}
+
+	state := KeycloakOidcConnectorState{
+		KeycloakOidcConnectorArgs: args,
+		CallbackUrl:               dexCallbackURL(ctx),
+	}
+
+	return infer.CreateResponse[KeycloakOidcConnectorState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing Keycloak OIDC connector.
+func (c *KeycloakOidcConnector) Read(ctx context.Context, req infer.ReadRequest[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState]) (infer.ReadResponse[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState]{}, nil
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildKeycloakOidcConfig(req.State.KeycloakOidcConnectorArgs))
+	}
+
+	// Extract baseUrl and realm from issuer
+	baseUrl, realm := "", ""
+	if m := keycloakIssuerRegex.FindStringSubmatch(GetString(configMap, "issuer")); m != nil {
+		baseUrl, realm = m[1], m[2]
+	}
+
+	scopes, _ := configMap["scopes"].([]any)
+	scopesStr := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if str, ok := s.(string); ok {
+			scopesStr = append(scopesStr, str)
+		}
+	}
+
+	args := KeycloakOidcConnectorArgs{
+		ConnectorId:  found.Id,
+		Name:         found.Name,
+		BaseUrl:      baseUrl,
+		Realm:        realm,
+		ClientId:     GetString(configMap, "clientID"),
+		ClientSecret: readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
+		RedirectUri:  GetString(configMap, "redirectURI"),
+		Scopes:       scopesStr,
+	}
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := KeycloakOidcConnectorState{
+		KeycloakOidcConnectorArgs: args,
+		CallbackUrl:               dexCallbackURL(ctx),
+		RawConfigOut:              redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing Keycloak OIDC connector.
+func (c *KeycloakOidcConnector) Update(ctx context.Context, req infer.UpdateRequest[KeycloakOidcConnectorArgs, KeycloakOidcConnectorState]) (infer.UpdateResponse[KeycloakOidcConnectorState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[KeycloakOidcConnectorState]{}, err
+		}
+		state := KeycloakOidcConnectorState{
+			KeycloakOidcConnectorArgs: args,
+			CallbackUrl:               dexCallbackURL(ctx),
+		}
+		return infer.UpdateResponse[KeycloakOidcConnectorState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[KeycloakOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("KeycloakOidcConnector", oldState.KeycloakOidcConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[KeycloakOidcConnectorState]{}, err
+	}
+
+	configBytes, err := json.Marshal(buildKeycloakOidcConfig(args))
+	if err != nil {
+		return infer.UpdateResponse[KeycloakOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[KeycloakOidcConnectorState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   "oidc",
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[KeycloakOidcConnectorState]{}, provider.WrapError("update", "keycloak-oidc-connector", args.ConnectorId, err)
+	}
+
+	state := KeycloakOidcConnectorState{
+		KeycloakOidcConnectorArgs: args,
+		CallbackUrl:               dexCallbackURL(ctx),
+	}
+
+	return infer.UpdateResponse[KeycloakOidcConnectorState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes a Keycloak OIDC connector.
+func (c *KeycloakOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest[KeycloakOidcConnectorState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	// Note: Pulumi does not call Delete during preview, so no preview check needed
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "keycloak-oidc-connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// applyKeycloakOidcDefaults fills in defaults for optional KeycloakOidcConnector inputs.
+// Unset scopes default to the provider's defaultOidcScopes, if set.
+func applyKeycloakOidcDefaults(cfg provider.DexConfig, args *KeycloakOidcConnectorArgs) {
+	args.Scopes = defaultOidcScopes(cfg, args.Scopes, "openid", "profile", "email")
+	args.BaseUrl = strings.TrimSuffix(args.BaseUrl, "/")
+}
+
+// buildKeycloakOidcConfig builds the Dex "oidc" connector config for a Keycloak realm.
+// The issuer is derived from baseUrl and realm.
+func buildKeycloakOidcConfig(args KeycloakOidcConnectorArgs) map[string]any {
+	issuer := fmt.Sprintf("%s/realms/%s", strings.TrimSuffix(args.BaseUrl, "/"), args.Realm)
+
+	oidcConfig := map[string]any{
+		"issuer":       issuer,
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"scopes":       args.Scopes,
+	}
+
+	for k, v := range args.ExtraOidc {
+		oidcConfig[k] = v
+	}
+
+	return oidcConfig
+}
@@ -0,0 +1,527 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// LdapConnector - LDAP connector (type: "ldap")
+// ============================================================================
+
+// LdapUserSearch configures how Dex finds a user entry from the login username.
+type LdapUserSearch struct {
+	BaseDN                string  `pulumi:"baseDN"`
+	Filter                *string `pulumi:"filter,optional"`
+	Username              string  `pulumi:"username"`
+	Scope                 *string `pulumi:"scope,optional"`
+	IdAttr                string  `pulumi:"idAttr"`
+	EmailAttr             *string `pulumi:"emailAttr,optional"`
+	NameAttr              *string `pulumi:"nameAttr,optional"`
+	PreferredUsernameAttr *string `pulumi:"preferredUsernameAttr,optional"`
+	EmailSuffix           *string `pulumi:"emailSuffix,optional"`
+}
+
+// LdapUserMatcher pairs a user entry attribute with the group entry attribute
+// that must hold the same value for the user to be a member.
+type LdapUserMatcher struct {
+	UserAttr  string `pulumi:"userAttr"`
+	GroupAttr string `pulumi:"groupAttr"`
+}
+
+// LdapGroupSearch configures how Dex finds the groups of an authenticated user.
+type LdapGroupSearch struct {
+	BaseDN       string            `pulumi:"baseDN"`
+	Filter       *string           `pulumi:"filter,optional"`
+	Scope        *string           `pulumi:"scope,optional"`
+	UserMatchers []LdapUserMatcher `pulumi:"userMatchers"`
+	NameAttr     string            `pulumi:"nameAttr"`
+}
+
+// LdapConnectorArgs defines inputs for LdapConnector.
+type LdapConnectorArgs struct {
+	ConnectorId        string           `pulumi:"connectorId"`
+	Name               string           `pulumi:"name"`
+	Host               string           `pulumi:"host"`
+	InsecureNoSSL      *bool            `pulumi:"insecureNoSSL,optional"`
+	InsecureSkipVerify *bool            `pulumi:"insecureSkipVerify,optional"`
+	StartTLS           *bool            `pulumi:"startTLS,optional"`
+	RootCA             *string          `pulumi:"rootCA,optional"`
+	RootCAData         *string          `pulumi:"rootCAData,optional"`
+	BindDN             *string          `pulumi:"bindDN,optional"`
+	BindPW             *string          `pulumi:"bindPW,optional" provider:"secret"`
+	UsernamePrompt     *string          `pulumi:"usernamePrompt,optional"`
+	UserSearch         LdapUserSearch   `pulumi:"userSearch"`
+	GroupSearch        *LdapGroupSearch `pulumi:"groupSearch,optional"`
+	TimeoutSeconds     *int             `pulumi:"timeoutSeconds,optional"`
+}
+
+// LdapConnectorState defines outputs for LdapConnector.
+type LdapConnectorState struct {
+	LdapConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+}
+
+// LdapConnector manages an LDAP connector in Dex.
+type LdapConnector struct{}
+
+// Annotate provides schema metadata.
+func (c *LdapConnector) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages an LDAP connector in Dex. Users sign in with a username and password that Dex verifies by binding to the directory, and groups are read from group entries.")
+}
+
+// Annotate provides schema metadata for LdapConnectorArgs.
+func (c *LdapConnectorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the LDAP connector.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.Host, "LDAP server host and optional port, e.g. 'ldap.example.com:636'. Changing it forces a replacement.")
+	a.Describe(&c.InsecureNoSSL, "If true, connect without TLS (port 389 by default). Not recommended for production.")
+	a.Describe(&c.InsecureSkipVerify, "If true, skip TLS verification of the LDAP server. Not recommended for production.")
+	a.Describe(&c.StartTLS, "If true, connect on the plaintext port and upgrade with StartTLS.")
+	a.Describe(&c.RootCA, "Path on the Dex server to a PEM root CA file for the LDAP server's certificate. Mutually exclusive with rootCAData.")
+	a.Describe(&c.RootCAData, "Inline PEM root CA certificate for the LDAP server, sent to Dex base64-encoded as rootCAData. Mutually exclusive with rootCA.")
+	a.Describe(&c.BindDN, "DN of the service account used to search the directory. If unset, Dex searches anonymously.")
+	a.Describe(&c.BindPW, "Password of the bind DN.")
+	a.Describe(&c.UsernamePrompt, "Label of the username field on Dex's login form, e.g. 'Email Address'.")
+	a.Describe(&c.UserSearch, "How to find the user entry for a login username.")
+	a.Describe(&c.GroupSearch, "How to find the user's groups. If unset, no groups are returned.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for LdapUserSearch.
+func (s *LdapUserSearch) Annotate(a infer.Annotator) {
+	a.Describe(&s.BaseDN, "DN to start the user search from, e.g. 'ou=People,dc=example,dc=com'.")
+	a.Describe(&s.Filter, "Optional filter applied to the search, e.g. '(objectClass=person)'.")
+	a.Describe(&s.Username, "Attribute matched against the login username, e.g. 'uid' or 'mail'.")
+	a.Describe(&s.Scope, "Search scope: 'sub' (default) or 'one'.")
+	a.Describe(&s.IdAttr, "Attribute holding the user ID, e.g. 'uid' or 'DN'.")
+	a.Describe(&s.EmailAttr, "Attribute holding the email address. Required unless emailSuffix is set.")
+	a.Describe(&s.NameAttr, "Attribute holding the display name.")
+	a.Describe(&s.PreferredUsernameAttr, "Attribute holding the preferred username.")
+	a.Describe(&s.EmailSuffix, "If set, the email is built as '<idAttr>@<emailSuffix>' instead of read from emailAttr.")
+}
+
+// Annotate provides schema metadata for LdapUserMatcher.
+func (m *LdapUserMatcher) Annotate(a infer.Annotator) {
+	a.Describe(&m.UserAttr, "Attribute of the user entry, e.g. 'DN' or 'uid'.")
+	a.Describe(&m.GroupAttr, "Attribute of the group entry that lists members, e.g. 'member' or 'memberUid'.")
+}
+
+// Annotate provides schema metadata for LdapGroupSearch.
+func (s *LdapGroupSearch) Annotate(a infer.Annotator) {
+	a.Describe(&s.BaseDN, "DN to start the group search from, e.g. 'ou=Groups,dc=example,dc=com'.")
+	a.Describe(&s.Filter, "Optional filter applied to the search, e.g. '(objectClass=groupOfNames)'.")
+	a.Describe(&s.Scope, "Search scope: 'sub' (default) or 'one'.")
+	a.Describe(&s.UserMatchers, "Attribute pairs that link a user to a group. A group matches if any pair matches.")
+	a.Describe(&s.NameAttr, "Attribute holding the group name, e.g. 'cn'.")
+}
+
+// Annotate provides schema metadata for LdapConnectorState.
+func (c *LdapConnectorState) Annotate(a infer.Annotator) {
+	// LdapConnectorState embeds LdapConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+}
+
+// Check validates inputs.
+func (c *LdapConnector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[LdapConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[LdapConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[LdapConnectorArgs]{Failures: failures}, err
+	}
+
+	if strings.TrimSpace(args.Host) == "" {
+		failures = append(failures, p.CheckFailure{
+			Property: "host",
+			Reason:   "host must not be empty",
+		})
+	}
+
+	// Dex picks LDAPS unless one of these is set; it rejects both together.
+	if provider.PtrOr(args.InsecureNoSSL, false) && provider.PtrOr(args.StartTLS, false) {
+		failures = append(failures, p.CheckFailure{
+			Property: "startTLS",
+			Reason:   "insecureNoSSL and startTLS are mutually exclusive; set at most one",
+		})
+	}
+
+	failures = append(failures, validateRootCA(args.RootCA, args.RootCAData)...)
+
+	if args.BindPW != nil && *args.BindPW != "" && (args.BindDN == nil || *args.BindDN == "") {
+		failures = append(failures, p.CheckFailure{
+			Property: "bindDN",
+			Reason:   "bindDN is required when bindPW is set",
+		})
+	}
+
+	if args.UsernamePrompt != nil {
+		failures = append(failures, validateUsernamePrompt("usernamePrompt", *args.UsernamePrompt)...)
+	}
+
+	if args.UserSearch.EmailAttr == nil && args.UserSearch.EmailSuffix == nil {
+		failures = append(failures, p.CheckFailure{
+			Property: "userSearch.emailAttr",
+			Reason:   "one of userSearch.emailAttr or userSearch.emailSuffix must be set",
+		})
+	}
+	failures = append(failures, validateLdapScope("userSearch.scope", args.UserSearch.Scope)...)
+	if args.GroupSearch != nil {
+		failures = append(failures, validateLdapScope("groupSearch.scope", args.GroupSearch.Scope)...)
+		if len(args.GroupSearch.UserMatchers) == 0 {
+			failures = append(failures, p.CheckFailure{
+				Property: "groupSearch.userMatchers",
+				Reason:   "at least one user matcher is required",
+			})
+		}
+	}
+
+	return infer.CheckResponse[LdapConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *LdapConnector) Diff(ctx context.Context, req infer.DiffRequest[LdapConnectorArgs, LdapConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("LdapConnector", req.State.LdapConnectorArgs, req.Inputs), nil
+}
+
+// Create creates a new LDAP connector.
+func (c *LdapConnector) Create(ctx context.Context, req infer.CreateRequest[LdapConnectorArgs]) (infer.CreateResponse[LdapConnectorState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[LdapConnectorState]{}, err
+		}
+		state := LdapConnectorState{
+			LdapConnectorArgs: args,
+		}
+		return infer.CreateResponse[LdapConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[LdapConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := json.Marshal(buildLdapConfig(args))
+	if err != nil {
+		return infer.CreateResponse[LdapConnectorState]{}, fmt.Errorf("failed to marshal LDAP config: %w", err)
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "ldap",
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[LdapConnectorState]{}, provider.WrapError("create", "ldap-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[LdapConnectorState]{}, err
+		}
+	}
+
+	state := LdapConnectorState{
+		LdapConnectorArgs: args,
+	}
+
+	return infer.CreateResponse[LdapConnectorState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing LDAP connector.
+func (c *LdapConnector) Read(ctx context.Context, req infer.ReadRequest[LdapConnectorArgs, LdapConnectorState]) (infer.ReadResponse[LdapConnectorArgs, LdapConnectorState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[LdapConnectorArgs, LdapConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[LdapConnectorArgs, LdapConnectorState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[LdapConnectorArgs, LdapConnectorState]{}, nil
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildLdapConfig(req.State.LdapConnectorArgs))
+	}
+
+	args := decodeLdapConfig(configMap)
+	args.ConnectorId = found.Id
+	args.Name = found.Name
+	args.BindPW = readSecretPtr(cfg, args.BindPW, req.State.BindPW, req.Inputs.BindPW)
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := LdapConnectorState{
+		LdapConnectorArgs: args,
+		RawConfigOut:      redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[LdapConnectorArgs, LdapConnectorState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing LDAP connector.
+func (c *LdapConnector) Update(ctx context.Context, req infer.UpdateRequest[LdapConnectorArgs, LdapConnectorState]) (infer.UpdateResponse[LdapConnectorState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[LdapConnectorState]{}, err
+		}
+		state := LdapConnectorState{
+			LdapConnectorArgs: args,
+		}
+		return infer.UpdateResponse[LdapConnectorState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[LdapConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("LdapConnector", oldState.LdapConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[LdapConnectorState]{}, err
+	}
+
+	configBytes, err := json.Marshal(buildLdapConfig(args))
+	if err != nil {
+		return infer.UpdateResponse[LdapConnectorState]{}, fmt.Errorf("failed to marshal LDAP config: %w", err)
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[LdapConnectorState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   "ldap",
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[LdapConnectorState]{}, provider.WrapError("update", "ldap-connector", args.ConnectorId, err)
+	}
+
+	state := LdapConnectorState{
+		LdapConnectorArgs: args,
+	}
+
+	return infer.UpdateResponse[LdapConnectorState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes an LDAP connector.
+func (c *LdapConnector) Delete(ctx context.Context, req infer.DeleteRequest[LdapConnectorState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "ldap-connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// validateLdapScope checks an LDAP search scope.
+func validateLdapScope(property string, scope *string) []p.CheckFailure {
+	if scope == nil || *scope == "sub" || *scope == "one" {
+		return nil
+	}
+	return []p.CheckFailure{{Property: property, Reason: "must be one of: sub, one"}}
+}
+
+// buildLdapConfig builds the Dex "ldap" connector config from args.
+func buildLdapConfig(args LdapConnectorArgs) map[string]any {
+	ldapConfig := map[string]any{
+		"host": args.Host,
+	}
+
+	if args.InsecureNoSSL != nil {
+		ldapConfig["insecureNoSSL"] = *args.InsecureNoSSL
+	}
+	if args.InsecureSkipVerify != nil {
+		ldapConfig["insecureSkipVerify"] = *args.InsecureSkipVerify
+	}
+	if args.StartTLS != nil {
+		ldapConfig["startTLS"] = *args.StartTLS
+	}
+	if args.RootCA != nil {
+		ldapConfig["rootCA"] = *args.RootCA
+	}
+	if args.RootCAData != nil {
+		ldapConfig["rootCAData"] = encodeRootCAData(*args.RootCAData)
+	}
+	if args.BindDN != nil {
+		ldapConfig["bindDN"] = *args.BindDN
+	}
+	if args.BindPW != nil {
+		ldapConfig["bindPW"] = *args.BindPW
+	}
+	if args.UsernamePrompt != nil {
+		ldapConfig["usernamePrompt"] = *args.UsernamePrompt
+	}
+
+	us := args.UserSearch
+	userSearch := map[string]any{
+		"baseDN":   us.BaseDN,
+		"username": us.Username,
+		"idAttr":   us.IdAttr,
+	}
+	setOptionalString(userSearch, "filter", us.Filter)
+	setOptionalString(userSearch, "scope", us.Scope)
+	setOptionalString(userSearch, "emailAttr", us.EmailAttr)
+	setOptionalString(userSearch, "nameAttr", us.NameAttr)
+	setOptionalString(userSearch, "preferredUsernameAttr", us.PreferredUsernameAttr)
+	setOptionalString(userSearch, "emailSuffix", us.EmailSuffix)
+	ldapConfig["userSearch"] = userSearch
+
+	if gs := args.GroupSearch; gs != nil {
+		matchers := make([]map[string]any, 0, len(gs.UserMatchers))
+		for _, m := range gs.UserMatchers {
+			matchers = append(matchers, map[string]any{
+				"userAttr":  m.UserAttr,
+				"groupAttr": m.GroupAttr,
+			})
+		}
+		groupSearch := map[string]any{
+			"baseDN":       gs.BaseDN,
+			"userMatchers": matchers,
+			"nameAttr":     gs.NameAttr,
+		}
+		setOptionalString(groupSearch, "filter", gs.Filter)
+		setOptionalString(groupSearch, "scope", gs.Scope)
+		ldapConfig["groupSearch"] = groupSearch
+	}
+
+	return ldapConfig
+}
+
+// decodeLdapConfig converts a Dex "ldap" connector config into args.
+func decodeLdapConfig(configMap map[string]any) LdapConnectorArgs {
+	args := LdapConnectorArgs{
+		Host:               GetString(configMap, "host"),
+		InsecureNoSSL:      GetBoolPtr(configMap, "insecureNoSSL"),
+		InsecureSkipVerify: GetBoolPtr(configMap, "insecureSkipVerify"),
+		StartTLS:           GetBoolPtr(configMap, "startTLS"),
+		RootCA:             GetStringPtr(configMap, "rootCA"),
+		RootCAData:         decodeRootCAData(configMap),
+		BindDN:             GetStringPtr(configMap, "bindDN"),
+		BindPW:             GetStringPtr(configMap, "bindPW"),
+		UsernamePrompt:     GetStringPtr(configMap, "usernamePrompt"),
+	}
+
+	if us, ok := configMap["userSearch"].(map[string]any); ok {
+		args.UserSearch = LdapUserSearch{
+			BaseDN:                GetString(us, "baseDN"),
+			Filter:                GetStringPtr(us, "filter"),
+			Username:              GetString(us, "username"),
+			Scope:                 GetStringPtr(us, "scope"),
+			IdAttr:                GetString(us, "idAttr"),
+			EmailAttr:             GetStringPtr(us, "emailAttr"),
+			NameAttr:              GetStringPtr(us, "nameAttr"),
+			PreferredUsernameAttr: GetStringPtr(us, "preferredUsernameAttr"),
+			EmailSuffix:           GetStringPtr(us, "emailSuffix"),
+		}
+	}
+
+	if gs, ok := configMap["groupSearch"].(map[string]any); ok {
+		groupSearch := &LdapGroupSearch{
+			BaseDN:   GetString(gs, "baseDN"),
+			Filter:   GetStringPtr(gs, "filter"),
+			Scope:    GetStringPtr(gs, "scope"),
+			NameAttr: GetString(gs, "nameAttr"),
+		}
+		if matchers, ok := gs["userMatchers"].([]any); ok {
+			for _, m := range matchers {
+				if mm, ok := m.(map[string]any); ok {
+					groupSearch.UserMatchers = append(groupSearch.UserMatchers, LdapUserMatcher{
+						UserAttr:  GetString(mm, "userAttr"),
+						GroupAttr: GetString(mm, "groupAttr"),
+					})
+				}
+			}
+		}
+		args.GroupSearch = groupSearch
+	}
+
+	return args
+}
+
+// setOptionalString sets m[key] when value is non-nil.
+func setOptionalString(m map[string]any, key string, value *string) {
+	if value != nil {
+		m[key] = *value
+	}
+}
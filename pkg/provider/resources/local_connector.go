@@ -2,8 +2,6 @@ package resources
 
 import (
 	"context"
-	"fmt"
-	"time"
 
 	api "github.com/dexidp/dex/api/v2"
 	"github.com/kotaicode/pulumi-dex/pkg/provider"
@@ -18,14 +16,16 @@ import (
 
 // LocalConnectorArgs defines inputs for LocalConnector.
 type LocalConnectorArgs struct {
-	ConnectorId string `pulumi:"connectorId"`
-	Name        string `pulumi:"name"`
-	Enabled     *bool  `pulumi:"enabled,optional"`
+	ConnectorId    string `pulumi:"connectorId"`
+	Name           string `pulumi:"name"`
+	Enabled        *bool  `pulumi:"enabled,optional"`
+	TimeoutSeconds *int   `pulumi:"timeoutSeconds,optional"`
 }
 
 // LocalConnectorState defines outputs for LocalConnector.
 type LocalConnectorState struct {
 	LocalConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
 }
 
 // LocalConnector manages a local/builtin connector in Dex.
@@ -33,19 +33,22 @@ type LocalConnector struct{}
 
 // Annotate provides schema metadata.
 func (c *LocalConnector) Annotate(a infer.Annotator) {
-	a.Describe(c, "Manages a local/builtin connector in Dex. The local connector provides username/password authentication stored in Dex's database. This is useful for testing or when you don't have an external identity provider.")
+	a.Describe(c, "Manages a local/builtin connector in Dex. The local connector provides username/password authentication stored in Dex's database. This is useful for testing or when you don't have an external identity provider. "+
+		"Dex has no setting to disable a connector, so a disabled LocalConnector is removed from Dex and created again when re-enabled.")
 }
 
 // Annotate provides schema metadata for LocalConnectorArgs.
 func (c *LocalConnectorArgs) Annotate(a infer.Annotator) {
 	a.Describe(&c.ConnectorId, "Unique identifier for the local connector.")
 	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
-	a.Describe(&c.Enabled, "Whether the local connector is enabled. Defaults to true.")
+	a.Describe(&c.Enabled, "Whether the local connector exists in Dex. Defaults to true. When false, the connector is deleted from Dex but stays managed, and setting it back to true recreates it.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
 }
 
 // Annotate provides schema metadata for LocalConnectorState.
 func (c *LocalConnectorState) Annotate(a infer.Annotator) {
 	// LocalConnectorState embeds LocalConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
 }
 
 // Check validates inputs.
@@ -67,12 +70,20 @@ func (c *LocalConnector) Check(ctx context.Context, req infer.CheckRequest) (inf
 	}, nil
 }
 
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *LocalConnector) Diff(ctx context.Context, req infer.DiffRequest[LocalConnectorArgs, LocalConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("LocalConnector", req.State.LocalConnectorArgs, req.Inputs), nil
+}
+
 // Create creates a new local connector.
 func (c *LocalConnector) Create(ctx context.Context, req infer.CreateRequest[LocalConnectorArgs]) (infer.CreateResponse[LocalConnectorState], error) {
 	args := req.Inputs
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[LocalConnectorState]{}, err
+		}
 		state := LocalConnectorState{
 			LocalConnectorArgs: args,
 		}
@@ -84,31 +95,14 @@ func (c *LocalConnector) Create(ctx context.Context, req infer.CreateRequest[Loc
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.CreateResponse[LocalConnectorState]{}, fmt.Errorf("Dex client not configured")
-	}
-
-	// Local connector has minimal config - just an empty JSON object
-	configBytes := []byte("{}")
-
-	connector := &api.Connector{
-		Id:     args.ConnectorId,
-		Type:   "local",
-		Name:   args.Name,
-		Config: configBytes,
+		return infer.CreateResponse[LocalConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	createCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
-		Connector: connector,
-	})
-	if err != nil {
-		return infer.CreateResponse[LocalConnectorState]{}, provider.WrapError("create", "local-connector", args.ConnectorId, err)
-	}
-
-	if resp.AlreadyExists {
-		return infer.CreateResponse[LocalConnectorState]{}, fmt.Errorf("connector with id %q already exists", args.ConnectorId)
+	// A disabled connector is tracked in state only.
+	if provider.PtrOr(args.Enabled, true) {
+		if err := createLocalConnector(ctx, cfg, args); err != nil {
+			return infer.CreateResponse[LocalConnectorState]{}, err
+		}
 	}
 
 	state := LocalConnectorState{
@@ -125,30 +119,27 @@ func (c *LocalConnector) Create(ctx context.Context, req infer.CreateRequest[Loc
 func (c *LocalConnector) Read(ctx context.Context, req infer.ReadRequest[LocalConnectorArgs, LocalConnectorState]) (infer.ReadResponse[LocalConnectorArgs, LocalConnectorState], error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.ReadResponse[LocalConnectorArgs, LocalConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.ReadResponse[LocalConnectorArgs, LocalConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	listCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
 	if err != nil {
-		return infer.ReadResponse[LocalConnectorArgs, LocalConnectorState]{}, fmt.Errorf("failed to list connectors: %w", err)
-	}
-
-	var found *api.Connector
-	for _, conn := range listResp.Connectors {
-		if conn.Id == req.ID {
-			found = conn
-			break
-		}
+		return infer.ReadResponse[LocalConnectorArgs, LocalConnectorState]{}, err
 	}
 
 	if found == nil {
+		// A disabled connector is expected to be absent and is still managed.
+		if req.State.ConnectorId != "" && !provider.PtrOr(req.State.Enabled, true) {
+			return infer.ReadResponse[LocalConnectorArgs, LocalConnectorState]{
+				ID:     req.ID,
+				Inputs: req.Inputs,
+				State:  req.State,
+			}, nil
+		}
 		return infer.ReadResponse[LocalConnectorArgs, LocalConnectorState]{}, nil
 	}
 
-	// Local connector has minimal config, so we just use defaults
+	// The connector exists in Dex, so it is enabled whatever state says.
 	enabled := true
 	args := LocalConnectorArgs{
 		ConnectorId: found.Id,
@@ -156,8 +147,11 @@ func (c *LocalConnector) Read(ctx context.Context, req infer.ReadRequest[LocalCo
 		Enabled:     &enabled,
 	}
 
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
 	state := LocalConnectorState{
 		LocalConnectorArgs: args,
+		RawConfigOut:       redactedRawConfig(found.Config),
 	}
 
 	return infer.ReadResponse[LocalConnectorArgs, LocalConnectorState]{
@@ -174,6 +168,9 @@ func (c *LocalConnector) Update(ctx context.Context, req infer.UpdateRequest[Loc
 
 	// In preview/dry-run mode, skip actual Dex API calls and return expected state
 	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[LocalConnectorState]{}, err
+		}
 		state := LocalConnectorState{
 			LocalConnectorArgs: args,
 		}
@@ -184,26 +181,37 @@ func (c *LocalConnector) Update(ctx context.Context, req infer.UpdateRequest[Loc
 
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.UpdateResponse[LocalConnectorState]{}, fmt.Errorf("Dex client not configured")
+		return infer.UpdateResponse[LocalConnectorState]{}, provider.ErrClientNotConfigured
 	}
 
-	if args.ConnectorId != oldState.ConnectorId {
-		return infer.UpdateResponse[LocalConnectorState]{}, fmt.Errorf("connectorId cannot be changed")
+	if err := checkImmutableFields("LocalConnector", oldState.LocalConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[LocalConnectorState]{}, err
 	}
 
-	configBytes := []byte("{}")
-
-	updateCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
-	defer cancel()
-
-	_, err := cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
-		Id:        args.ConnectorId,
-		NewType:   "local",
-		NewName:   args.Name,
-		NewConfig: configBytes,
-	})
-	if err != nil {
-		return infer.UpdateResponse[LocalConnectorState]{}, provider.WrapError("update", "local-connector", args.ConnectorId, err)
+	wasEnabled := provider.PtrOr(oldState.Enabled, true)
+	enabled := provider.PtrOr(args.Enabled, true)
+	switch {
+	case enabled && !wasEnabled:
+		if err := createLocalConnector(ctx, cfg, args); err != nil {
+			return infer.UpdateResponse[LocalConnectorState]{}, err
+		}
+	case !enabled && wasEnabled:
+		if err := deleteLocalConnector(ctx, cfg, args.ConnectorId, args.TimeoutSeconds); err != nil {
+			return infer.UpdateResponse[LocalConnectorState]{}, err
+		}
+	case enabled:
+		updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+		defer cancel()
+
+		_, err := cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+			Id:        args.ConnectorId,
+			NewType:   "local",
+			NewName:   args.Name,
+			NewConfig: []byte("{}"),
+		})
+		if err != nil {
+			return infer.UpdateResponse[LocalConnectorState]{}, provider.WrapError("update", "local-connector", args.ConnectorId, err)
+		}
 	}
 
 	state := LocalConnectorState{
@@ -219,7 +227,7 @@ func (c *LocalConnector) Update(ctx context.Context, req infer.UpdateRequest[Loc
 func (c *LocalConnector) Delete(ctx context.Context, req infer.DeleteRequest[LocalConnectorState]) (infer.DeleteResponse, error) {
 	cfg := infer.GetConfig[provider.DexConfig](ctx)
 	if cfg.Client == nil {
-		return infer.DeleteResponse{}, fmt.Errorf("Dex client not configured")
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
 	}
 
 	deleteID := req.ID
@@ -227,18 +235,55 @@ func (c *LocalConnector) Delete(ctx context.Context, req infer.DeleteRequest[Loc
 		deleteID = req.State.ConnectorId
 	}
 
-	deleteCtx, cancel := context.WithTimeout(ctx, time.Duration(provider.PtrOr(cfg.TimeoutSeconds, 5))*time.Second)
+	// A disabled connector was already removed from Dex.
+	if !provider.PtrOr(req.State.Enabled, true) {
+		return infer.DeleteResponse{}, nil
+	}
+
+	return infer.DeleteResponse{}, deleteLocalConnector(ctx, cfg, deleteID, req.State.TimeoutSeconds)
+}
+
+// createLocalConnector creates the local connector described by args in Dex.
+func createLocalConnector(ctx context.Context, cfg provider.DexConfig, args LocalConnectorArgs) error {
+	// Local connector has minimal config - just an empty JSON object
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "local",
+		Name:   args.Name,
+		Config: []byte("{}"),
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return provider.WrapError("create", "local-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Tolerate a create race as long as the existing connector matches.
+		return matchExistingConnector(ctx, cfg, connector, args.TimeoutSeconds)
+	}
+	return nil
+}
+
+// deleteLocalConnector deletes the local connector with the given ID from Dex.
+// A connector that is already gone is not an error.
+func deleteLocalConnector(ctx context.Context, cfg provider.DexConfig, id string, timeoutSeconds *int) error {
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
 	defer cancel()
 
 	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
-		Id: deleteID,
+		Id: id,
 	})
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			return infer.DeleteResponse{}, nil
+			return nil
 		}
-		return infer.DeleteResponse{}, provider.WrapError("delete", "local-connector", deleteID, err)
+		return provider.WrapError("delete", "local-connector", id, err)
 	}
-
-	return infer.DeleteResponse{}, nil
+	return verifyConnectorDeleted(ctx, cfg, id, timeoutSeconds)
 }
@@ -0,0 +1,335 @@
+package resources
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ============================================================================
+// LocalUser - Password database user with provider-managed hashing and ID
+// ============================================================================
+
+// LocalUserArgs defines inputs for LocalUser.
+type LocalUserArgs struct {
+	Email          string  `pulumi:"email"`
+	Username       string  `pulumi:"username"`
+	Password       string  `pulumi:"password" provider:"secret"`
+	UserId         *string `pulumi:"userId,optional"`
+	BcryptCost     *int    `pulumi:"bcryptCost,optional"`
+	TimeoutSeconds *int    `pulumi:"timeoutSeconds,optional"`
+}
+
+// LocalUserState defines outputs for LocalUser. UserId always holds the ID
+// stored in Dex, including a generated one.
+type LocalUserState struct {
+	LocalUserArgs
+	Hash string `pulumi:"hash" provider:"secret"`
+}
+
+// LocalUser manages a user in Dex's password database from a plaintext
+// password, generating a stable user ID when none is given.
+type LocalUser struct{}
+
+// Annotate provides schema metadata.
+func (r *LocalUser) Annotate(a infer.Annotator) {
+	a.Describe(r, "Manages a user in Dex's password database for the local connector. Unlike Password, it takes a plaintext password, hashes it with bcrypt, and generates a UUID user ID when userId is not set. Requires enablePasswordDB in the Dex configuration.")
+}
+
+// Annotate provides schema metadata for LocalUserArgs.
+func (r *LocalUserArgs) Annotate(a infer.Annotator) {
+	a.Describe(&r.Email, "Email address the user signs in with. Changing it forces a replacement.")
+	a.Describe(&r.Username, "Display username.")
+	a.Describe(&r.Password, "Plaintext password, hashed with bcrypt by the provider. Only the hash is sent to Dex.")
+	a.Describe(&r.UserId, "Stable user ID reported in tokens (the 'sub' claim). A UUID is generated and kept in state when unset. Changing it forces a replacement.")
+	a.Describe(&r.BcryptCost, "bcrypt cost used to hash the password. Defaults to 10.")
+	a.Describe(&r.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for LocalUserState.
+func (r *LocalUserState) Annotate(a infer.Annotator) {
+	a.Describe(&r.Hash, "bcrypt hash stored in Dex.")
+}
+
+// Check validates inputs.
+func (r *LocalUser) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[LocalUserArgs], error) {
+	args, failures, err := infer.DefaultCheck[LocalUserArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[LocalUserArgs]{Failures: failures}, err
+	}
+
+	if args.Email != "" && !strings.Contains(args.Email, "@") {
+		failures = append(failures, p.CheckFailure{
+			Property: "email",
+			Reason:   "must be an email address",
+		})
+	}
+	if args.Password == "" {
+		failures = append(failures, p.CheckFailure{
+			Property: "password",
+			Reason:   "must not be empty",
+		})
+	}
+	if args.UserId != nil && *args.UserId == "" {
+		failures = append(failures, p.CheckFailure{
+			Property: "userId",
+			Reason:   "must not be empty; omit it to generate one",
+		})
+	}
+	if args.BcryptCost != nil && (*args.BcryptCost < bcrypt.MinCost || *args.BcryptCost > bcrypt.MaxCost) {
+		failures = append(failures, p.CheckFailure{
+			Property: "bcryptCost",
+			Reason:   fmt.Sprintf("must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost),
+		})
+	}
+
+	return infer.CheckResponse[LocalUserArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as
+// replacements. An unset userId keeps the one in state, so a generated ID
+// never shows up as a change.
+func (r *LocalUser) Diff(ctx context.Context, req infer.DiffRequest[LocalUserArgs, LocalUserState]) (infer.DiffResponse, error) {
+	olds, news := comparableLocalUserArgs(req.State.LocalUserArgs, req.Inputs)
+	return diffResource("LocalUser", olds, news), nil
+}
+
+// Create creates a new local user in Dex.
+func (r *LocalUser) Create(ctx context.Context, req infer.CreateRequest[LocalUserArgs]) (infer.CreateResponse[LocalUserState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[LocalUserState]{}, err
+		}
+		return infer.CreateResponse[LocalUserState]{
+			ID:     args.Email,
+			Output: LocalUserState{LocalUserArgs: args},
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[LocalUserState]{}, provider.ErrClientNotConfigured
+	}
+
+	userID := provider.PtrOr(args.UserId, "")
+	if userID == "" {
+		generated, err := generateUserID()
+		if err != nil {
+			return infer.CreateResponse[LocalUserState]{}, provider.WrapError("create", "local user", args.Email, err)
+		}
+		userID = generated
+	}
+
+	hash, err := resolvePasswordHash(localUserPasswordArgs(args), "")
+	if err != nil {
+		return infer.CreateResponse[LocalUserState]{}, provider.WrapError("create", "local user", args.Email, err)
+	}
+
+	password := &api.Password{
+		Email:    args.Email,
+		Hash:     []byte(hash),
+		Username: args.Username,
+		UserId:   userID,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreatePassword(createCtx, &api.CreatePasswordReq{
+		Password: password,
+	})
+	if err != nil {
+		return infer.CreateResponse[LocalUserState]{}, provider.WrapError("create", "local user", args.Email, err)
+	}
+
+	if resp.AlreadyExists {
+		// A retried create cannot know the ID it generated last time, so
+		// take the stored one when the program left userId unset.
+		if args.UserId == nil {
+			if existing, err := findPassword(ctx, cfg, args.Email, args.TimeoutSeconds); err == nil && existing != nil {
+				password.UserId = existing.UserId
+			}
+		}
+		existing, err := matchExistingPassword(ctx, cfg, password, &args.Password, args.TimeoutSeconds)
+		if err != nil {
+			return infer.CreateResponse[LocalUserState]{}, err
+		}
+		hash = string(existing.Hash)
+		userID = existing.UserId
+	}
+
+	return infer.CreateResponse[LocalUserState]{
+		ID:     args.Email,
+		Output: localUserState(args, userID, hash),
+	}, nil
+}
+
+// Read retrieves an existing local user from Dex.
+func (r *LocalUser) Read(ctx context.Context, req infer.ReadRequest[LocalUserArgs, LocalUserState]) (infer.ReadResponse[LocalUserArgs, LocalUserState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[LocalUserArgs, LocalUserState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findPassword(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[LocalUserArgs, LocalUserState]{}, err
+	}
+	if found == nil {
+		return infer.ReadResponse[LocalUserArgs, LocalUserState]{}, nil
+	}
+
+	args := LocalUserArgs{
+		Email:          found.Email,
+		Username:       found.Username,
+		Password:       req.Inputs.Password,
+		UserId:         req.Inputs.UserId,
+		BcryptCost:     req.Inputs.BcryptCost,
+		TimeoutSeconds: req.Inputs.TimeoutSeconds,
+	}
+	// A userId the program did not specify is provider-managed: it stays in
+	// state only, like a generated client secret.
+	if args.UserId != nil {
+		args.UserId = &found.UserId
+	}
+
+	state := localUserState(args, found.UserId, string(found.Hash))
+	// The password cannot be read back. Keep it only while it still matches
+	// the stored hash, so a password changed outside Pulumi shows as a diff.
+	if bcrypt.CompareHashAndPassword(found.Hash, []byte(args.Password)) != nil {
+		state.Password = ""
+	}
+
+	return infer.ReadResponse[LocalUserArgs, LocalUserState]{
+		ID:     req.ID,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates the password and username of an existing local user.
+func (r *LocalUser) Update(ctx context.Context, req infer.UpdateRequest[LocalUserArgs, LocalUserState]) (infer.UpdateResponse[LocalUserState], error) {
+	args := req.Inputs
+	oldState := req.State
+	userID := provider.PtrOr(oldState.UserId, "")
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[LocalUserState]{}, err
+		}
+		return infer.UpdateResponse[LocalUserState]{
+			Output: localUserState(args, userID, oldState.Hash),
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[LocalUserState]{}, provider.ErrClientNotConfigured
+	}
+
+	olds, news := comparableLocalUserArgs(oldState.LocalUserArgs, args)
+	if err := checkImmutableFields("LocalUser", olds, news); err != nil {
+		return infer.UpdateResponse[LocalUserState]{}, err
+	}
+
+	hash, err := resolvePasswordHash(localUserPasswordArgs(args), oldState.Hash)
+	if err != nil {
+		return infer.UpdateResponse[LocalUserState]{}, provider.WrapError("update", "local user", args.Email, err)
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.UpdatePassword(updateCtx, &api.UpdatePasswordReq{
+		Email:       args.Email,
+		NewHash:     []byte(hash),
+		NewUsername: args.Username,
+	})
+	if err != nil {
+		return infer.UpdateResponse[LocalUserState]{}, provider.WrapError("update", "local user", args.Email, err)
+	}
+	if resp.NotFound {
+		return infer.UpdateResponse[LocalUserState]{}, fmt.Errorf("local user %q no longer exists in Dex; run 'pulumi refresh' to recreate it", args.Email)
+	}
+
+	return infer.UpdateResponse[LocalUserState]{
+		Output: localUserState(args, userID, hash),
+	}, nil
+}
+
+// Delete deletes a local user from Dex.
+func (r *LocalUser) Delete(ctx context.Context, req infer.DeleteRequest[LocalUserState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	email := req.ID
+	if email == "" {
+		email = req.State.Email
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	// A NotFound response means it is already gone, which is what Delete wants.
+	if _, err := cfg.Client.DeletePassword(deleteCtx, &api.DeletePasswordReq{Email: email}); err != nil {
+		return infer.DeleteResponse{}, provider.WrapError("delete", "local user", email, err)
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// comparableLocalUserArgs returns olds and news normalized for diffing: an
+// unset userId in news takes the stored one.
+func comparableLocalUserArgs(olds, news LocalUserArgs) (LocalUserArgs, LocalUserArgs) {
+	if news.UserId == nil {
+		news.UserId = olds.UserId
+	}
+	return olds, news
+}
+
+// localUserPasswordArgs returns the PasswordArgs that resolvePasswordHash
+// needs to hash the user's password.
+func localUserPasswordArgs(args LocalUserArgs) PasswordArgs {
+	return PasswordArgs{
+		Email:             args.Email,
+		Username:          args.Username,
+		PlaintextPassword: &args.Password,
+		BcryptCost:        args.BcryptCost,
+	}
+}
+
+// localUserState builds the state for args with the user ID and hash stored
+// in Dex.
+func localUserState(args LocalUserArgs, userID, hash string) LocalUserState {
+	if userID != "" {
+		args.UserId = &userID
+	}
+	return LocalUserState{LocalUserArgs: args, Hash: hash}
+}
+
+// generateUserID returns a random (version 4) UUID.
+func generateUserID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate user ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,446 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// OAuthConnector - Generic OAuth2 connector (type: "oauth")
+// ============================================================================
+
+// OAuthClaimMapping mirrors the claimMapping object of Dex's oauth connector.
+// JSON tags match Dex's keys.
+type OAuthClaimMapping struct {
+	UserNameKey          *string `pulumi:"userNameKey,optional" json:"userNameKey,omitempty"`
+	PreferredUsernameKey *string `pulumi:"preferredUsernameKey,optional" json:"preferredUsernameKey,omitempty"`
+	GroupsKey            *string `pulumi:"groupsKey,optional" json:"groupsKey,omitempty"`
+	EmailKey             *string `pulumi:"emailKey,optional" json:"emailKey,omitempty"`
+	EmailVerifiedKey     *string `pulumi:"emailVerifiedKey,optional" json:"emailVerifiedKey,omitempty"`
+}
+
+// OAuthConnectorArgs defines inputs for OAuthConnector.
+type OAuthConnectorArgs struct {
+	ConnectorId        string             `pulumi:"connectorId"`
+	Name               string             `pulumi:"name"`
+	ClientId           string             `pulumi:"clientId"`
+	ClientSecret       string             `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri        string             `pulumi:"redirectUri"`
+	TokenUrl           string             `pulumi:"tokenUrl"`
+	AuthorizationUrl   string             `pulumi:"authorizationUrl"`
+	UserInfoUrl        string             `pulumi:"userInfoUrl"`
+	Scopes             []string           `pulumi:"scopes,optional"`
+	RootCAs            []string           `pulumi:"rootCAs,optional"`
+	InsecureSkipVerify *bool              `pulumi:"insecureSkipVerify,optional"`
+	UserIdKey          *string            `pulumi:"userIdKey,optional"`
+	ClaimMapping       *OAuthClaimMapping `pulumi:"claimMapping,optional"`
+	Extra              map[string]any     `pulumi:"extra,optional"`
+	TimeoutSeconds     *int               `pulumi:"timeoutSeconds,optional"`
+}
+
+// OAuthConnectorState defines outputs for OAuthConnector.
+type OAuthConnectorState struct {
+	OAuthConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
+}
+
+// OAuthConnector manages a generic OAuth2 connector in Dex.
+type OAuthConnector struct{}
+
+// Annotate provides schema metadata.
+func (c *OAuthConnector) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages a generic OAuth2 connector in Dex (type: oauth) for providers that do not speak OIDC. User attributes are read from the provider's user info endpoint using the configured claim mapping.")
+}
+
+// Annotate provides schema metadata for OAuthConnectorArgs.
+func (c *OAuthConnectorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the OAuth connector.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.ClientId, "OAuth2 client ID.")
+	a.Describe(&c.ClientSecret, "OAuth2 client secret.")
+	a.Describe(&c.RedirectUri, "Redirect URI registered with the provider. Must match Dex's callback URL.")
+	a.Describe(&c.TokenUrl, "The provider's token endpoint.")
+	a.Describe(&c.AuthorizationUrl, "The provider's authorization endpoint.")
+	a.Describe(&c.UserInfoUrl, "The provider's user info endpoint, from which user attributes are read.")
+	a.Describe(&c.Scopes, "OAuth2 scopes to request.")
+	a.Describe(&c.RootCAs, "Paths to PEM root CA files for the provider's TLS certificates.")
+	a.Describe(&c.InsecureSkipVerify, "If true, skip TLS verification of the provider. Not recommended for production.")
+	a.Describe(&c.UserIdKey, "User info key holding the user ID. Dex defaults to 'id'.")
+	a.Describe(&c.ClaimMapping, "Mapping of user info keys to Dex user attributes.")
+	a.Describe(&c.Extra, "Additional provider-specific config fields as key-value pairs.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for OAuthClaimMapping.
+func (c *OAuthClaimMapping) Annotate(a infer.Annotator) {
+	a.Describe(&c.UserNameKey, "User info key holding the user name. Dex defaults to 'user_name'.")
+	a.Describe(&c.PreferredUsernameKey, "User info key holding the preferred username. Dex defaults to 'preferred_username'.")
+	a.Describe(&c.GroupsKey, "User info key holding the user's groups. Dex defaults to 'groups'.")
+	a.Describe(&c.EmailKey, "User info key holding the email address. Dex defaults to 'email'.")
+	a.Describe(&c.EmailVerifiedKey, "User info key holding the email-verified flag. Dex defaults to 'email_verified'.")
+}
+
+// Annotate provides schema metadata for OAuthConnectorState.
+func (c *OAuthConnectorState) Annotate(a infer.Annotator) {
+	// OAuthConnectorState embeds OAuthConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// Check validates inputs.
+func (c *OAuthConnector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[OAuthConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[OAuthConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[OAuthConnectorArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	failures = append(failures, validateAbsoluteURL("tokenUrl", args.TokenUrl)...)
+	failures = append(failures, validateAbsoluteURL("authorizationUrl", args.AuthorizationUrl)...)
+	failures = append(failures, validateAbsoluteURL("userInfoUrl", args.UserInfoUrl)...)
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+
+	return infer.CheckResponse[OAuthConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *OAuthConnector) Diff(ctx context.Context, req infer.DiffRequest[OAuthConnectorArgs, OAuthConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("OAuthConnector", req.State.OAuthConnectorArgs, req.Inputs), nil
+}
+
+// Create creates a new OAuth connector.
+func (c *OAuthConnector) Create(ctx context.Context, req infer.CreateRequest[OAuthConnectorArgs]) (infer.CreateResponse[OAuthConnectorState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[OAuthConnectorState]{}, err
+		}
+		state := OAuthConnectorState{
+			OAuthConnectorArgs: args,
+			CallbackUrl:        dexCallbackURL(ctx),
+		}
+		return infer.CreateResponse[OAuthConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[OAuthConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := json.Marshal(buildOAuthConfig(args))
+	if err != nil {
+		return infer.CreateResponse[OAuthConnectorState]{}, fmt.Errorf("failed to marshal OAuth config: %w", err)
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "oauth",
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[OAuthConnectorState]{}, provider.WrapError("create", "oauth-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[OAuthConnectorState]{}, err
+		}
+	}
+
+	state := OAuthConnectorState{
+		OAuthConnectorArgs: args,
+		CallbackUrl:        dexCallbackURL(ctx),
+	}
+
+	return infer.CreateResponse[OAuthConnectorState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing OAuth connector.
+func (c *OAuthConnector) Read(ctx context.Context, req infer.ReadRequest[OAuthConnectorArgs, OAuthConnectorState]) (infer.ReadResponse[OAuthConnectorArgs, OAuthConnectorState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[OAuthConnectorArgs, OAuthConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[OAuthConnectorArgs, OAuthConnectorState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[OAuthConnectorArgs, OAuthConnectorState]{}, nil
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildOAuthConfig(req.State.OAuthConnectorArgs))
+	}
+
+	args := decodeOAuthConfig(configMap)
+	args.ConnectorId = found.Id
+	args.Name = found.Name
+	args.ClientSecret = readSecret(cfg, args.ClientSecret, req.State.ClientSecret, req.Inputs.ClientSecret)
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := OAuthConnectorState{
+		OAuthConnectorArgs: args,
+		CallbackUrl:        dexCallbackURL(ctx),
+		RawConfigOut:       redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[OAuthConnectorArgs, OAuthConnectorState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing OAuth connector.
+func (c *OAuthConnector) Update(ctx context.Context, req infer.UpdateRequest[OAuthConnectorArgs, OAuthConnectorState]) (infer.UpdateResponse[OAuthConnectorState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[OAuthConnectorState]{}, err
+		}
+		state := OAuthConnectorState{
+			OAuthConnectorArgs: args,
+			CallbackUrl:        dexCallbackURL(ctx),
+		}
+		return infer.UpdateResponse[OAuthConnectorState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[OAuthConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("OAuthConnector", oldState.OAuthConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[OAuthConnectorState]{}, err
+	}
+
+	configBytes, err := json.Marshal(buildOAuthConfig(args))
+	if err != nil {
+		return infer.UpdateResponse[OAuthConnectorState]{}, fmt.Errorf("failed to marshal OAuth config: %w", err)
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[OAuthConnectorState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   "oauth",
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[OAuthConnectorState]{}, provider.WrapError("update", "oauth-connector", args.ConnectorId, err)
+	}
+
+	state := OAuthConnectorState{
+		OAuthConnectorArgs: args,
+		CallbackUrl:        dexCallbackURL(ctx),
+	}
+
+	return infer.UpdateResponse[OAuthConnectorState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes an OAuth connector.
+func (c *OAuthConnector) Delete(ctx context.Context, req infer.DeleteRequest[OAuthConnectorState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "oauth-connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// oauthConfigKeys lists the Dex oauth connector keys modeled as typed fields;
+// any other key round-trips through Extra.
+var oauthConfigKeys = map[string]bool{
+	"clientID":           true,
+	"clientSecret":       true,
+	"redirectURI":        true,
+	"tokenURL":           true,
+	"authorizationURL":   true,
+	"userInfoURL":        true,
+	"scopes":             true,
+	"rootCAs":            true,
+	"insecureSkipVerify": true,
+	"userIDKey":          true,
+	"claimMapping":       true,
+}
+
+// buildOAuthConfig builds the Dex "oauth" connector config.
+func buildOAuthConfig(args OAuthConnectorArgs) map[string]any {
+	oauthConfig := map[string]any{
+		"clientID":         args.ClientId,
+		"clientSecret":     args.ClientSecret,
+		"redirectURI":      args.RedirectUri,
+		"tokenURL":         args.TokenUrl,
+		"authorizationURL": args.AuthorizationUrl,
+		"userInfoURL":      args.UserInfoUrl,
+	}
+
+	if len(args.Scopes) > 0 {
+		oauthConfig["scopes"] = args.Scopes
+	}
+	if len(args.RootCAs) > 0 {
+		oauthConfig["rootCAs"] = args.RootCAs
+	}
+	if args.InsecureSkipVerify != nil {
+		oauthConfig["insecureSkipVerify"] = *args.InsecureSkipVerify
+	}
+	if args.UserIdKey != nil {
+		oauthConfig["userIDKey"] = *args.UserIdKey
+	}
+	if args.ClaimMapping != nil {
+		cm := map[string]any{}
+		if args.ClaimMapping.UserNameKey != nil {
+			cm["userNameKey"] = *args.ClaimMapping.UserNameKey
+		}
+		if args.ClaimMapping.PreferredUsernameKey != nil {
+			cm["preferredUsernameKey"] = *args.ClaimMapping.PreferredUsernameKey
+		}
+		if args.ClaimMapping.GroupsKey != nil {
+			cm["groupsKey"] = *args.ClaimMapping.GroupsKey
+		}
+		if args.ClaimMapping.EmailKey != nil {
+			cm["emailKey"] = *args.ClaimMapping.EmailKey
+		}
+		if args.ClaimMapping.EmailVerifiedKey != nil {
+			cm["emailVerifiedKey"] = *args.ClaimMapping.EmailVerifiedKey
+		}
+		if len(cm) > 0 {
+			oauthConfig["claimMapping"] = cm
+		}
+	}
+
+	for k, v := range args.Extra {
+		oauthConfig[k] = v
+	}
+
+	return oauthConfig
+}
+
+// decodeOAuthConfig converts a Dex "oauth" connector config into args. Keys
+// that are not modeled as typed fields are kept in Extra.
+func decodeOAuthConfig(configMap map[string]any) OAuthConnectorArgs {
+	args := OAuthConnectorArgs{
+		ClientId:           GetString(configMap, "clientID"),
+		ClientSecret:       GetString(configMap, "clientSecret"),
+		RedirectUri:        GetString(configMap, "redirectURI"),
+		TokenUrl:           GetString(configMap, "tokenURL"),
+		AuthorizationUrl:   GetString(configMap, "authorizationURL"),
+		UserInfoUrl:        GetString(configMap, "userInfoURL"),
+		InsecureSkipVerify: GetBoolPtr(configMap, "insecureSkipVerify"),
+		UserIdKey:          GetStringPtr(configMap, "userIDKey"),
+	}
+
+	if scopes, ok := configMap["scopes"].([]any); ok {
+		for _, s := range scopes {
+			if str, ok := s.(string); ok {
+				args.Scopes = append(args.Scopes, str)
+			}
+		}
+	}
+	if rootCAs, ok := configMap["rootCAs"].([]any); ok {
+		for _, s := range rootCAs {
+			if str, ok := s.(string); ok {
+				args.RootCAs = append(args.RootCAs, str)
+			}
+		}
+	}
+	if cm, ok := configMap["claimMapping"].(map[string]any); ok && len(cm) > 0 {
+		args.ClaimMapping = &OAuthClaimMapping{
+			UserNameKey:          GetStringPtr(cm, "userNameKey"),
+			PreferredUsernameKey: GetStringPtr(cm, "preferredUsernameKey"),
+			GroupsKey:            GetStringPtr(cm, "groupsKey"),
+			EmailKey:             GetStringPtr(cm, "emailKey"),
+			EmailVerifiedKey:     GetStringPtr(cm, "emailVerifiedKey"),
+		}
+	}
+
+	for k, v := range configMap {
+		if oauthConfigKeys[k] {
+			continue
+		}
+		if args.Extra == nil {
+			args.Extra = map[string]any{}
+		}
+		args.Extra[k] = v
+	}
+
+	return args
+}
@@ -0,0 +1,371 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// OktaOidcConnector - Uses generic OIDC connector (type: "oidc")
+// ============================================================================
+
+// OktaOidcConnectorArgs defines inputs for OktaOidcConnector.
+type OktaOidcConnectorArgs struct {
+	ConnectorId    string         `pulumi:"connectorId"`
+	Name           string         `pulumi:"name"`
+	OktaDomain     string         `pulumi:"oktaDomain"`
+	ClientId       string         `pulumi:"clientId"`
+	ClientSecret   string         `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri    string         `pulumi:"redirectUri"`
+	Scopes         []string       `pulumi:"scopes,optional"`
+	UserNameSource *string        `pulumi:"userNameSource,optional"` // "preferred_username" | "email" | "sub"
+	ExtraOidc      map[string]any `pulumi:"extraOidc,optional"`
+	TimeoutSeconds *int           `pulumi:"timeoutSeconds,optional"`
+}
+
+// OktaOidcConnectorState defines outputs for OktaOidcConnector.
+type OktaOidcConnectorState struct {
+	OktaOidcConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
+}
+
+// OktaOidcConnector manages an Okta connector using Dex's generic OIDC connector.
+type OktaOidcConnector struct{}
+
+// Annotate provides schema metadata.
+func (c *OktaOidcConnector) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages an Okta connector in Dex using the generic OIDC connector (type: oidc). The issuer is derived from the Okta domain as 'https://<oktaDomain>'.")
+}
+
+// Annotate provides schema metadata for OktaOidcConnectorArgs.
+func (c *OktaOidcConnectorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the Okta connector.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.OktaDomain, "Okta org domain without scheme, e.g. 'acme.okta.com' or a custom domain. Changing this forces a replacement.")
+	a.Describe(&c.ClientId, "Okta application client ID.")
+	a.Describe(&c.ClientSecret, "Okta application client secret.")
+	a.Describe(&c.RedirectUri, "Redirect URI registered in the Okta application. Must match Dex's callback URL.")
+	a.Describe(&c.Scopes, "OIDC scopes to request from Okta. Defaults to ['openid', 'profile', 'email'] if not specified.")
+	a.Describe(&c.UserNameSource, "Source for the username claim. Valid values: 'preferred_username' (default), 'email', or 'sub'.")
+	a.Describe(&c.ExtraOidc, "Additional OIDC configuration fields as key-value pairs for advanced scenarios.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for OktaOidcConnectorState.
+func (c *OktaOidcConnectorState) Annotate(a infer.Annotator) {
+	// OktaOidcConnectorState embeds OktaOidcConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// Check validates inputs.
+func (c *OktaOidcConnector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[OktaOidcConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[OktaOidcConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[OktaOidcConnectorArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	if args.OktaDomain != "" && !bareDomainRegex.MatchString(args.OktaDomain) {
+		failures = append(failures, p.CheckFailure{
+			Property: "oktaDomain",
+			Reason:   fmt.Sprintf("must be a bare domain such as 'acme.okta.com' (no scheme or path), got %q", args.OktaDomain),
+		})
+	}
+
+	// Validate userNameSource
+	if args.UserNameSource != nil {
+		valid := map[string]bool{"preferred_username": true, "email": true, "sub": true}
+		if !valid[*args.UserNameSource] {
+			failures = append(failures, p.CheckFailure{
+				Property: "userNameSource",
+				Reason:   "must be one of: preferred_username, email, sub",
+			})
+		}
+	}
+
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+	failures = append(failures, checkOpenIDScope(ctx, cfg, "scopes", args.Scopes)...)
+
+	applyOktaOidcDefaults(cfg, &args)
+
+	return infer.CheckResponse[OktaOidcConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as
+// replacements; oktaDomain is baked into the issuer.
+func (c *OktaOidcConnector) Diff(ctx context.Context, req infer.DiffRequest[OktaOidcConnectorArgs, OktaOidcConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("OktaOidcConnector", req.State.OktaOidcConnectorArgs, req.Inputs), nil
+}
+
+// Create creates a new Okta OIDC connector.
+func (c *OktaOidcConnector) Create(ctx context.Context, req infer.CreateRequest[OktaOidcConnectorArgs]) (infer.CreateResponse[OktaOidcConnectorState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[OktaOidcConnectorState]{}, err
+		}
+		state := OktaOidcConnectorState{
+			OktaOidcConnectorArgs: args,
+			CallbackUrl:           dexCallbackURL(ctx),
+		}
+		return infer.CreateResponse[OktaOidcConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[OktaOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := json.Marshal(buildOktaOidcConfig(args))
+	if err != nil {
+		return infer.CreateResponse[OktaOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "oidc",
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[OktaOidcConnectorState]{}, provider.WrapError("create", "okta-oidc-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[OktaOidcConnectorState]{}, err
+		}
+	}
+
+	state := OktaOidcConnectorState{
+		OktaOidcConnectorArgs: args,
+		CallbackUrl:           dexCallbackURL(ctx),
+	}
+
+	return infer.CreateResponse[OktaOidcConnectorState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing Okta OIDC connector.
+func (c *OktaOidcConnector) Read(ctx context.Context, req infer.ReadRequest[OktaOidcConnectorArgs, OktaOidcConnectorState]) (infer.ReadResponse[OktaOidcConnectorArgs, OktaOidcConnectorState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[OktaOidcConnectorArgs, OktaOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[OktaOidcConnectorArgs, OktaOidcConnectorState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[OktaOidcConnectorArgs, OktaOidcConnectorState]{}, nil
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildOktaOidcConfig(req.State.OktaOidcConnectorArgs))
+	}
+
+	// Extract the Okta domain from the issuer
+	oktaDomain := strings.TrimSuffix(strings.TrimPrefix(GetString(configMap, "issuer"), "https://"), "/")
+
+	scopes, _ := configMap["scopes"].([]any)
+	scopesStr := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if str, ok := s.(string); ok {
+			scopesStr = append(scopesStr, str)
+		}
+	}
+
+	args := OktaOidcConnectorArgs{
+		ConnectorId:    found.Id,
+		Name:           found.Name,
+		OktaDomain:     oktaDomain,
+		ClientId:       GetString(configMap, "clientID"),
+		ClientSecret:   readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
+		RedirectUri:    GetString(configMap, "redirectURI"),
+		Scopes:         scopesStr,
+		UserNameSource: GetStringPtr(configMap, "userNameKey"),
+	}
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := OktaOidcConnectorState{
+		OktaOidcConnectorArgs: args,
+		CallbackUrl:           dexCallbackURL(ctx),
+		RawConfigOut:          redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[OktaOidcConnectorArgs, OktaOidcConnectorState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing Okta OIDC connector.
+func (c *OktaOidcConnector) Update(ctx context.Context, req infer.UpdateRequest[OktaOidcConnectorArgs, OktaOidcConnectorState]) (infer.UpdateResponse[OktaOidcConnectorState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[OktaOidcConnectorState]{}, err
+		}
+		state := OktaOidcConnectorState{
+			OktaOidcConnectorArgs: args,
+			CallbackUrl:           dexCallbackURL(ctx),
+		}
+		return infer.UpdateResponse[OktaOidcConnectorState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[OktaOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("OktaOidcConnector", oldState.OktaOidcConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[OktaOidcConnectorState]{}, err
+	}
+
+	configBytes, err := json.Marshal(buildOktaOidcConfig(args))
+	if err != nil {
+		return infer.UpdateResponse[OktaOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[OktaOidcConnectorState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   "oidc",
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[OktaOidcConnectorState]{}, provider.WrapError("update", "okta-oidc-connector", args.ConnectorId, err)
+	}
+
+	state := OktaOidcConnectorState{
+		OktaOidcConnectorArgs: args,
+		CallbackUrl:           dexCallbackURL(ctx),
+	}
+
+	return infer.UpdateResponse[OktaOidcConnectorState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes a Okta OIDC connector.
+func (c *OktaOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest[OktaOidcConnectorState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	// Note: Pulumi does not call Delete during preview, so no preview check needed
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "okta-oidc-connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// applyOktaOidcDefaults fills in defaults for optional OktaOidcConnector inputs.
+// Unset scopes default to the provider's defaultOidcScopes, if set.
+func applyOktaOidcDefaults(cfg provider.DexConfig, args *OktaOidcConnectorArgs) {
+	args.Scopes = defaultOidcScopes(cfg, args.Scopes, "openid", "profile", "email")
+	if args.UserNameSource == nil {
+		defaultUserNameSource := "preferred_username"
+		args.UserNameSource = &defaultUserNameSource
+	}
+}
+
+// buildOktaOidcConfig builds the Dex "oidc" connector config for an Okta org.
+// The issuer is derived from oktaDomain.
+func buildOktaOidcConfig(args OktaOidcConnectorArgs) map[string]any {
+	issuer := fmt.Sprintf("https://%s", args.OktaDomain)
+
+	oidcConfig := map[string]any{
+		"issuer":       issuer,
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"scopes":       args.Scopes,
+		"userNameKey":  provider.PtrOr(args.UserNameSource, "preferred_username"),
+	}
+
+	for k, v := range args.ExtraOidc {
+		oidcConfig[k] = v
+	}
+
+	return oidcConfig
+}
@@ -0,0 +1,363 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ============================================================================
+// Password - Static password in Dex's password database
+// ============================================================================
+
+// PasswordArgs defines inputs for Password.
+// Exactly one of Hash and PlaintextPassword must be set.
+type PasswordArgs struct {
+	Email             string  `pulumi:"email"`
+	Username          string  `pulumi:"username"`
+	UserId            string  `pulumi:"userId"`
+	Hash              string  `pulumi:"hash,optional" provider:"secret"`
+	PlaintextPassword *string `pulumi:"plaintextPassword,optional" provider:"secret"`
+	BcryptCost        *int    `pulumi:"bcryptCost,optional"`
+	TimeoutSeconds    *int    `pulumi:"timeoutSeconds,optional"`
+}
+
+// PasswordState defines outputs for Password. Hash always holds the hash
+// stored in Dex; PlaintextPassword is never kept in state.
+type PasswordState struct {
+	PasswordArgs
+}
+
+// Password manages a user in Dex's password database.
+type Password struct{}
+
+// Annotate provides schema metadata.
+func (r *Password) Annotate(a infer.Annotator) {
+	a.Describe(r, "Manages a user in Dex's password database, used by the local connector. Requires enablePasswordDB in the Dex configuration. The email address identifies the password.")
+}
+
+// Annotate provides schema metadata for PasswordArgs.
+func (r *PasswordArgs) Annotate(a infer.Annotator) {
+	a.Describe(&r.Email, "Email address the user signs in with. Changing it forces a replacement.")
+	a.Describe(&r.Username, "Display username.")
+	a.Describe(&r.UserId, "Stable user ID reported in tokens (the 'sub' claim). Changing it forces a replacement.")
+	a.Describe(&r.Hash, "bcrypt hash of the password, e.g. from 'htpasswd -bnBC 10 \"\" <password>'. Exactly one of hash and plaintextPassword must be set.")
+	a.Describe(&r.PlaintextPassword, "Plaintext password, hashed with bcrypt by the provider. Only the hash is sent to Dex and kept in state.")
+	a.Describe(&r.BcryptCost, "bcrypt cost used to hash plaintextPassword. Defaults to 10.")
+	a.Describe(&r.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Check validates inputs.
+func (r *Password) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[PasswordArgs], error) {
+	args, failures, err := infer.DefaultCheck[PasswordArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[PasswordArgs]{Failures: failures}, err
+	}
+
+	if args.Email != "" && !strings.Contains(args.Email, "@") {
+		failures = append(failures, p.CheckFailure{
+			Property: "email",
+			Reason:   "must be an email address",
+		})
+	}
+	if args.Hash != "" && !isBcryptHash(args.Hash) {
+		failures = append(failures, p.CheckFailure{
+			Property: "hash",
+			Reason:   "must be a bcrypt hash ($2a$, $2b$, or $2y$)",
+		})
+	}
+	switch {
+	case args.Hash != "" && args.PlaintextPassword != nil:
+		failures = append(failures, p.CheckFailure{
+			Property: "plaintextPassword",
+			Reason:   "hash and plaintextPassword are mutually exclusive; set only one",
+		})
+	case args.Hash == "" && args.PlaintextPassword == nil:
+		failures = append(failures, p.CheckFailure{
+			Property: "hash",
+			Reason:   "one of hash or plaintextPassword must be set",
+		})
+	case args.PlaintextPassword != nil && *args.PlaintextPassword == "":
+		failures = append(failures, p.CheckFailure{
+			Property: "plaintextPassword",
+			Reason:   "must not be empty",
+		})
+	}
+	if args.BcryptCost != nil && (*args.BcryptCost < bcrypt.MinCost || *args.BcryptCost > bcrypt.MaxCost) {
+		failures = append(failures, p.CheckFailure{
+			Property: "bcryptCost",
+			Reason:   fmt.Sprintf("must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost),
+		})
+	}
+
+	return infer.CheckResponse[PasswordArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as
+// replacements. A plaintextPassword is compared against the stored hash, since
+// hashing it again would never produce the same hash.
+func (r *Password) Diff(ctx context.Context, req infer.DiffRequest[PasswordArgs, PasswordState]) (infer.DiffResponse, error) {
+	olds, news := req.State.PasswordArgs, req.Inputs
+	if news.PlaintextPassword != nil {
+		news.Hash = olds.Hash
+		if bcrypt.CompareHashAndPassword([]byte(olds.Hash), []byte(*news.PlaintextPassword)) == nil {
+			olds.PlaintextPassword = news.PlaintextPassword
+		}
+	}
+	return diffResource("Password", olds, news), nil
+}
+
+// Create creates a new password in Dex.
+func (r *Password) Create(ctx context.Context, req infer.CreateRequest[PasswordArgs]) (infer.CreateResponse[PasswordState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[PasswordState]{}, err
+		}
+		return infer.CreateResponse[PasswordState]{
+			ID:     args.Email,
+			Output: passwordState(args, args.Hash),
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[PasswordState]{}, provider.ErrClientNotConfigured
+	}
+
+	hash, err := resolvePasswordHash(args, "")
+	if err != nil {
+		return infer.CreateResponse[PasswordState]{}, provider.WrapError("create", "password", args.Email, err)
+	}
+
+	password := &api.Password{
+		Email:    args.Email,
+		Hash:     []byte(hash),
+		Username: args.Username,
+		UserId:   args.UserId,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreatePassword(createCtx, &api.CreatePasswordReq{
+		Password: password,
+	})
+	if err != nil {
+		return infer.CreateResponse[PasswordState]{}, provider.WrapError("create", "password", args.Email, err)
+	}
+
+	if resp.AlreadyExists {
+		// Tolerate a create race as long as the existing password matches.
+		existing, err := matchExistingPassword(ctx, cfg, password, args.PlaintextPassword, args.TimeoutSeconds)
+		if err != nil {
+			return infer.CreateResponse[PasswordState]{}, err
+		}
+		hash = string(existing.Hash)
+	}
+
+	return infer.CreateResponse[PasswordState]{
+		ID:     args.Email,
+		Output: passwordState(args, hash),
+	}, nil
+}
+
+// Read retrieves an existing password from Dex.
+func (r *Password) Read(ctx context.Context, req infer.ReadRequest[PasswordArgs, PasswordState]) (infer.ReadResponse[PasswordArgs, PasswordState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[PasswordArgs, PasswordState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findPassword(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[PasswordArgs, PasswordState]{}, err
+	}
+	if found == nil {
+		return infer.ReadResponse[PasswordArgs, PasswordState]{}, nil
+	}
+
+	args := PasswordArgs{
+		Email:      found.Email,
+		Username:   found.Username,
+		UserId:     found.UserId,
+		Hash:       string(found.Hash),
+		BcryptCost: req.Inputs.BcryptCost,
+	}
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := passwordState(args, string(found.Hash))
+
+	// Keep a plaintext input as the program wrote it; Diff checks it against
+	// the refreshed hash.
+	if req.Inputs.PlaintextPassword != nil {
+		args.Hash = ""
+		args.PlaintextPassword = req.Inputs.PlaintextPassword
+	}
+
+	return infer.ReadResponse[PasswordArgs, PasswordState]{
+		ID:     req.ID,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates the hash and username of an existing password.
+func (r *Password) Update(ctx context.Context, req infer.UpdateRequest[PasswordArgs, PasswordState]) (infer.UpdateResponse[PasswordState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[PasswordState]{}, err
+		}
+		return infer.UpdateResponse[PasswordState]{
+			Output: passwordState(args, args.Hash),
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[PasswordState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("Password", oldState.PasswordArgs, args); err != nil {
+		return infer.UpdateResponse[PasswordState]{}, err
+	}
+
+	hash, err := resolvePasswordHash(args, oldState.Hash)
+	if err != nil {
+		return infer.UpdateResponse[PasswordState]{}, provider.WrapError("update", "password", args.Email, err)
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.UpdatePassword(updateCtx, &api.UpdatePasswordReq{
+		Email:       args.Email,
+		NewHash:     []byte(hash),
+		NewUsername: args.Username,
+	})
+	if err != nil {
+		return infer.UpdateResponse[PasswordState]{}, provider.WrapError("update", "password", args.Email, err)
+	}
+	if resp.NotFound {
+		return infer.UpdateResponse[PasswordState]{}, fmt.Errorf("password for %q no longer exists in Dex; run 'pulumi refresh' to recreate it", args.Email)
+	}
+
+	return infer.UpdateResponse[PasswordState]{
+		Output: passwordState(args, hash),
+	}, nil
+}
+
+// Delete deletes a password from Dex.
+func (r *Password) Delete(ctx context.Context, req infer.DeleteRequest[PasswordState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	email := req.ID
+	if email == "" {
+		email = req.State.Email
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	// A NotFound response means it is already gone, which is what Delete wants.
+	if _, err := cfg.Client.DeletePassword(deleteCtx, &api.DeletePasswordReq{Email: email}); err != nil {
+		return infer.DeleteResponse{}, provider.WrapError("delete", "password", email, err)
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// findPassword returns the password stored for email, or nil if there is none.
+// Dex has no single-password lookup, so all passwords are listed and filtered.
+// Emails are compared case-insensitively, since Dex lowercases them on storage.
+func findPassword(ctx context.Context, cfg provider.DexConfig, email string, timeoutSeconds *int) (*api.Password, error) {
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, timeoutSeconds))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListPasswords(listCtx, &api.ListPasswordReq{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passwords: %w", err)
+	}
+	for _, pw := range listResp.Passwords {
+		if strings.EqualFold(pw.Email, email) {
+			return pw, nil
+		}
+	}
+	return nil, nil
+}
+
+// matchExistingPassword handles an AlreadyExists response from CreatePassword
+// the same way matchExistingConnector does for connectors: the create is
+// treated as idempotent when the stored password equals want, and fails
+// otherwise. When plaintext is set it is checked against the stored hash
+// instead of comparing hashes. The existing password is returned.
+func matchExistingPassword(ctx context.Context, cfg provider.DexConfig, want *api.Password, plaintext *string, timeoutSeconds *int) (*api.Password, error) {
+	existing, err := findPassword(ctx, cfg, want.Email, timeoutSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("password for %q already exists but %w", want.Email, err)
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("password for %q already exists but could not be found; retry the operation", want.Email)
+	}
+	sameHash := string(existing.Hash) == string(want.Hash)
+	if plaintext != nil {
+		sameHash = bcrypt.CompareHashAndPassword(existing.Hash, []byte(*plaintext)) == nil
+	}
+	if existing.Username != want.Username || existing.UserId != want.UserId || !sameHash {
+		return nil, fmt.Errorf("password for %q %w in Dex with different settings; import it with 'pulumi import' or delete it first", want.Email, provider.ErrAlreadyExists)
+	}
+	return existing, nil
+}
+
+// resolvePasswordHash returns the hash to store for args. An explicit hash is
+// used as is. A plaintext password keeps oldHash when it still matches at the
+// requested cost, so unrelated updates do not rehash it; otherwise it is
+// hashed with the configured cost.
+func resolvePasswordHash(args PasswordArgs, oldHash string) (string, error) {
+	if args.PlaintextPassword == nil {
+		return args.Hash, nil
+	}
+	plaintext := []byte(*args.PlaintextPassword)
+	cost := provider.PtrOr(args.BcryptCost, bcrypt.DefaultCost)
+	if oldHash != "" && bcrypt.CompareHashAndPassword([]byte(oldHash), plaintext) == nil {
+		if oldCost, err := bcrypt.Cost([]byte(oldHash)); err == nil && oldCost == cost {
+			return oldHash, nil
+		}
+	}
+	hash, err := bcrypt.GenerateFromPassword(plaintext, cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash plaintextPassword: %w", err)
+	}
+	return string(hash), nil
+}
+
+// passwordState builds the state for args with the stored hash. The plaintext
+// password is dropped so it never appears in outputs.
+func passwordState(args PasswordArgs, hash string) PasswordState {
+	args.Hash = hash
+	args.PlaintextPassword = nil
+	return PasswordState{PasswordArgs: args}
+}
+
+// isBcryptHash reports whether s looks like a bcrypt hash.
+func isBcryptHash(s string) bool {
+	return len(s) == 60 && (strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$"))
+}
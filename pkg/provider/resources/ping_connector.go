@@ -0,0 +1,410 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// PingOidcConnector - Uses generic OIDC connector (type: "oidc")
+// ============================================================================
+
+// pingIssuerRegex matches PingOne issuers of the form https://auth.pingone.<region>/<envId>/as.
+var pingIssuerRegex = regexp.MustCompile(`^https://auth\.pingone\.([a-z.]+)/([^/]+)/as$`)
+
+// pingRegions lists the PingOne region top-level domains.
+var pingRegions = map[string]bool{"com": true, "eu": true, "ca": true, "asia": true, "com.au": true, "sg": true}
+
+// PingOidcConnectorArgs defines inputs for PingOidcConnector.
+type PingOidcConnectorArgs struct {
+	ConnectorId    string         `pulumi:"connectorId"`
+	Name           string         `pulumi:"name"`
+	EnvironmentId  string         `pulumi:"environmentId"`
+	Region         string         `pulumi:"region"`
+	ClientId       string         `pulumi:"clientId"`
+	ClientSecret   string         `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri    string         `pulumi:"redirectUri"`
+	Scopes         []string       `pulumi:"scopes,optional"`
+	UserNameSource *string        `pulumi:"userNameSource,optional"` // "preferred_username" | "email" | "sub"
+	GroupsClaim    *string        `pulumi:"groupsClaim,optional"`
+	ExtraOidc      map[string]any `pulumi:"extraOidc,optional"`
+	TimeoutSeconds *int           `pulumi:"timeoutSeconds,optional"`
+}
+
+// PingOidcConnectorState defines outputs for PingOidcConnector.
+type PingOidcConnectorState struct {
+	PingOidcConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
+}
+
+// PingOidcConnector manages a PingOne/PingFederate connector using Dex's generic OIDC connector.
+type PingOidcConnector struct{}
+
+// Annotate provides schema metadata.
+func (c *PingOidcConnector) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages a PingOne connector in Dex using the generic OIDC connector (type: oidc). The issuer is derived from the environment ID and region as 'https://auth.pingone.<region>/<environmentId>/as'.")
+}
+
+// Annotate provides schema metadata for PingOidcConnectorArgs.
+func (c *PingOidcConnectorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the Ping connector.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.EnvironmentId, "PingOne environment ID (UUID format). Changing this forces a replacement.")
+	a.Describe(&c.Region, "PingOne region top-level domain: 'com' (North America), 'eu', 'ca', 'asia', 'com.au', or 'sg'. Changing this forces a replacement.")
+	a.Describe(&c.ClientId, "PingOne application client ID.")
+	a.Describe(&c.ClientSecret, "PingOne application client secret.")
+	a.Describe(&c.RedirectUri, "Redirect URI registered in the PingOne application. Must match Dex's callback URL.")
+	a.Describe(&c.Scopes, "OIDC scopes to request from PingOne. Defaults to ['openid', 'profile', 'email'] if not specified.")
+	a.Describe(&c.UserNameSource, "Source for the username claim. Valid values: 'preferred_username' (default), 'email', or 'sub'.")
+	a.Describe(&c.GroupsClaim, "Name of the ID token claim carrying group memberships (e.g. a custom 'groups' attribute mapping). When set, it is used as Dex's groups claim mapping.")
+	a.Describe(&c.ExtraOidc, "Additional OIDC configuration fields as key-value pairs for advanced scenarios.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for PingOidcConnectorState.
+func (c *PingOidcConnectorState) Annotate(a infer.Annotator) {
+	// PingOidcConnectorState embeds PingOidcConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// Check validates inputs.
+func (c *PingOidcConnector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[PingOidcConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[PingOidcConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[PingOidcConnectorArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	// Validate environmentId format (UUID)
+	if args.EnvironmentId != "" {
+		uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+		if !uuidRegex.MatchString(strings.ToLower(args.EnvironmentId)) {
+			failures = append(failures, p.CheckFailure{
+				Property: "environmentId",
+				Reason:   "must be a valid UUID",
+			})
+		}
+	}
+
+	if args.Region != "" && !pingRegions[args.Region] {
+		failures = append(failures, p.CheckFailure{
+			Property: "region",
+			Reason:   "must be one of: com, eu, ca, asia, com.au, sg",
+		})
+	}
+
+	// Validate userNameSource
+	if args.UserNameSource != nil {
+		valid := map[string]bool{"preferred_username": true, "email": true, "sub": true}
+		if !valid[*args.UserNameSource] {
+			failures = append(failures, p.CheckFailure{
+				Property: "userNameSource",
+				Reason:   "must be one of: preferred_username, email, sub",
+			})
+		}
+	}
+
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+	failures = append(failures, checkOpenIDScope(ctx, cfg, "scopes", args.Scopes)...)
+
+	applyPingOidcDefaults(cfg, &args)
+
+	return infer.CheckResponse[PingOidcConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as
+// replacements; environmentId and region are baked into the issuer.
+func (c *PingOidcConnector) Diff(ctx context.Context, req infer.DiffRequest[PingOidcConnectorArgs, PingOidcConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("PingOidcConnector", req.State.PingOidcConnectorArgs, req.Inputs), nil
+}
+
+// Create creates a new Ping OIDC connector.
+func (c *PingOidcConnector) Create(ctx context.Context, req infer.CreateRequest[PingOidcConnectorArgs]) (infer.CreateResponse[PingOidcConnectorState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[PingOidcConnectorState]{}, err
+		}
+		state := PingOidcConnectorState{
+			PingOidcConnectorArgs: args,
+			CallbackUrl:           dexCallbackURL(ctx),
+		}
+		return infer.CreateResponse[PingOidcConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[PingOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := json.Marshal(buildPingOidcConfig(args))
+	if err != nil {
+		return infer.CreateResponse[PingOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "oidc",
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[PingOidcConnectorState]{}, provider.WrapError("create", "ping-oidc-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[PingOidcConnectorState]{}, err
+		}
+	}
+
+	state := PingOidcConnectorState{
+		PingOidcConnectorArgs: args,
+		CallbackUrl:           dexCallbackURL(ctx),
+	}
+
+	return infer.CreateResponse[PingOidcConnectorState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing Ping OIDC connector.
+func (c *PingOidcConnector) Read(ctx context.Context, req infer.ReadRequest[PingOidcConnectorArgs, PingOidcConnectorState]) (infer.ReadResponse[PingOidcConnectorArgs, PingOidcConnectorState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[PingOidcConnectorArgs, PingOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[PingOidcConnectorArgs, PingOidcConnectorState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[PingOidcConnectorArgs, PingOidcConnectorState]{}, nil
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildPingOidcConfig(req.State.PingOidcConnectorArgs))
+	}
+
+	// Extract region and environmentId from issuer
+	region, environmentId := "", ""
+	if m := pingIssuerRegex.FindStringSubmatch(GetString(configMap, "issuer")); m != nil {
+		region, environmentId = m[1], m[2]
+	}
+
+	var groupsClaim *string
+	if claimMapping, ok := configMap["claimMapping"].(map[string]any); ok {
+		groupsClaim = GetStringPtr(claimMapping, "groups")
+	}
+
+	scopes, _ := configMap["scopes"].([]any)
+	scopesStr := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if str, ok := s.(string); ok {
+			scopesStr = append(scopesStr, str)
+		}
+	}
+
+	args := PingOidcConnectorArgs{
+		ConnectorId:    found.Id,
+		Name:           found.Name,
+		EnvironmentId:  environmentId,
+		Region:         region,
+		ClientId:       GetString(configMap, "clientID"),
+		ClientSecret:   readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
+		RedirectUri:    GetString(configMap, "redirectURI"),
+		Scopes:         scopesStr,
+		UserNameSource: GetStringPtr(configMap, "userNameKey"),
+		GroupsClaim:    groupsClaim,
+	}
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := PingOidcConnectorState{
+		PingOidcConnectorArgs: args,
+		CallbackUrl:           dexCallbackURL(ctx),
+		RawConfigOut:          redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[PingOidcConnectorArgs, PingOidcConnectorState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing Ping OIDC connector.
+func (c *PingOidcConnector) Update(ctx context.Context, req infer.UpdateRequest[PingOidcConnectorArgs, PingOidcConnectorState]) (infer.UpdateResponse[PingOidcConnectorState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[PingOidcConnectorState]{}, err
+		}
+		state := PingOidcConnectorState{
+			PingOidcConnectorArgs: args,
+			CallbackUrl:           dexCallbackURL(ctx),
+		}
+		return infer.UpdateResponse[PingOidcConnectorState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[PingOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("PingOidcConnector", oldState.PingOidcConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[PingOidcConnectorState]{}, err
+	}
+
+	configBytes, err := json.Marshal(buildPingOidcConfig(args))
+	if err != nil {
+		return infer.UpdateResponse[PingOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[PingOidcConnectorState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   "oidc",
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[PingOidcConnectorState]{}, provider.WrapError("update", "ping-oidc-connector", args.ConnectorId, err)
+	}
+
+	state := PingOidcConnectorState{
+		PingOidcConnectorArgs: args,
+		CallbackUrl:           dexCallbackURL(ctx),
+	}
+
+	return infer.UpdateResponse[PingOidcConnectorState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes a Ping OIDC connector.
+func (c *PingOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest[PingOidcConnectorState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	// Note: Pulumi does not call Delete during preview, so no preview check needed
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "ping-oidc-connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// applyPingOidcDefaults fills in defaults for optional PingOidcConnector inputs.
+// Unset scopes default to the provider's defaultOidcScopes, if set.
+func applyPingOidcDefaults(cfg provider.DexConfig, args *PingOidcConnectorArgs) {
+	args.Scopes = defaultOidcScopes(cfg, args.Scopes, "openid", "profile", "email")
+	if args.UserNameSource == nil {
+		defaultUserNameSource := "preferred_username"
+		args.UserNameSource = &defaultUserNameSource
+	}
+}
+
+// buildPingOidcConfig builds the Dex "oidc" connector config for a PingOne environment.
+// The issuer is derived from region and environmentId.
+func buildPingOidcConfig(args PingOidcConnectorArgs) map[string]any {
+	issuer := fmt.Sprintf("https://auth.pingone.%s/%s/as", args.Region, args.EnvironmentId)
+
+	oidcConfig := map[string]any{
+		"issuer":       issuer,
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"scopes":       args.Scopes,
+		"userNameKey":  provider.PtrOr(args.UserNameSource, "preferred_username"),
+	}
+
+	if args.GroupsClaim != nil && *args.GroupsClaim != "" {
+		// PingOne only emits groups when an attribute mapping is configured, and
+		// never marks them as verified, so Dex must be told to trust them.
+		oidcConfig["insecureEnableGroups"] = true
+		oidcConfig["claimMapping"] = map[string]any{"groups": *args.GroupsClaim}
+	}
+
+	for k, v := range args.ExtraOidc {
+		oidcConfig[k] = v
+	}
+
+	return oidcConfig
+}
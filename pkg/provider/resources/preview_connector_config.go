@@ -0,0 +1,195 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// previewConnectorConfig - Renders the Dex config for an opinionated connector
+// ============================================================================
+
+// PreviewConnectorConfigArgs defines inputs for the previewConnectorConfig function.
+// Exactly one connector block must be set.
+type PreviewConnectorConfigArgs struct {
+	Auth0Oidc      *Auth0OidcConnectorArgs      `pulumi:"auth0Oidc,optional"`
+	AwsSsoOidc     *AwsSsoOidcConnectorArgs     `pulumi:"awsSsoOidc,optional"`
+	AzureOidc      *AzureOidcConnectorArgs      `pulumi:"azureOidc,optional"`
+	AzureMicrosoft *AzureMicrosoftConnectorArgs `pulumi:"azureMicrosoft,optional"`
+	CognitoOidc    *CognitoOidcConnectorArgs    `pulumi:"cognitoOidc,optional"`
+	GitHub         *GitHubConnectorArgs         `pulumi:"gitHub,optional"`
+	GitLab         *GitLabConnectorArgs         `pulumi:"gitLab,optional"`
+	Google         *GoogleConnectorArgs         `pulumi:"google,optional"`
+	KeycloakOidc   *KeycloakOidcConnectorArgs   `pulumi:"keycloakOidc,optional"`
+	Ldap           *LdapConnectorArgs           `pulumi:"ldap,optional"`
+	OAuth          *OAuthConnectorArgs          `pulumi:"oauth,optional"`
+	OktaOidc       *OktaOidcConnectorArgs       `pulumi:"oktaOidc,optional"`
+	PingOidc       *PingOidcConnectorArgs       `pulumi:"pingOidc,optional"`
+	SalesforceOidc *SalesforceOidcConnectorArgs `pulumi:"salesforceOidc,optional"`
+}
+
+// PreviewConnectorConfigResult defines outputs for the previewConnectorConfig function.
+type PreviewConnectorConfigResult struct {
+	Type   string `pulumi:"type"`
+	Config string `pulumi:"config"`
+}
+
+// PreviewConnectorConfig renders the Dex connector config that an opinionated connector
+// resource would create, without calling Dex.
+type PreviewConnectorConfig struct{}
+
+// Annotate provides schema metadata.
+func (f *PreviewConnectorConfig) Annotate(a infer.Annotator) {
+	a.Describe(f, "Returns the exact Dex connector config JSON that an opinionated connector resource would create, with secrets redacted. Useful for validating derived values (issuer, userNameKey, scopes) without touching Dex.")
+}
+
+// Annotate provides schema metadata for PreviewConnectorConfigArgs.
+func (a *PreviewConnectorConfigArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.Auth0Oidc, "Inputs of an Auth0OidcConnector to render.")
+	an.Describe(&a.AwsSsoOidc, "Inputs of an AwsSsoOidcConnector to render.")
+	an.Describe(&a.AzureOidc, "Inputs of an AzureOidcConnector to render.")
+	an.Describe(&a.AzureMicrosoft, "Inputs of an AzureMicrosoftConnector to render.")
+	an.Describe(&a.CognitoOidc, "Inputs of a CognitoOidcConnector to render.")
+	an.Describe(&a.GitHub, "Inputs of a GitHubConnector to render.")
+	an.Describe(&a.GitLab, "Inputs of a GitLabConnector to render.")
+	an.Describe(&a.Google, "Inputs of a GoogleConnector to render.")
+	an.Describe(&a.KeycloakOidc, "Inputs of a KeycloakOidcConnector to render.")
+	an.Describe(&a.Ldap, "Inputs of an LdapConnector to render.")
+	an.Describe(&a.OAuth, "Inputs of an OAuthConnector to render.")
+	an.Describe(&a.OktaOidc, "Inputs of an OktaOidcConnector to render.")
+	an.Describe(&a.PingOidc, "Inputs of a PingOidcConnector to render.")
+	an.Describe(&a.SalesforceOidc, "Inputs of a SalesforceOidcConnector to render.")
+}
+
+// Annotate provides schema metadata for PreviewConnectorConfigResult.
+func (r *PreviewConnectorConfigResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Type, "The Dex connector type that would be created (e.g. 'oidc', 'github').")
+	a.Describe(&r.Config, "The connector config JSON that would be sent to Dex, with secrets redacted.")
+}
+
+// Invoke renders the connector config. Defaults are applied the same way the
+// corresponding resource's Check applies them.
+func (f *PreviewConnectorConfig) Invoke(ctx context.Context, req infer.FunctionRequest[PreviewConnectorConfigArgs]) (infer.FunctionResponse[PreviewConnectorConfigResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	out, err := previewConnectorConfig(cfg, req.Input)
+	if err != nil {
+		return infer.FunctionResponse[PreviewConnectorConfigResult]{}, err
+	}
+	return infer.FunctionResponse[PreviewConnectorConfigResult]{Output: out}, nil
+}
+
+// previewConnectorConfig renders the redacted config for the single connector
+// block set in in.
+func previewConnectorConfig(cfg provider.DexConfig, in PreviewConnectorConfigArgs) (PreviewConnectorConfigResult, error) {
+	var (
+		connectorType string
+		config        map[string]any
+		set           int
+	)
+
+	if in.Auth0Oidc != nil {
+		set++
+		args := *in.Auth0Oidc
+		applyAuth0OidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildAuth0OidcConfig(args)
+	}
+	if in.AwsSsoOidc != nil {
+		set++
+		args := *in.AwsSsoOidc
+		applyAwsSsoOidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildAwsSsoOidcConfig(args)
+	}
+	if in.AzureOidc != nil {
+		set++
+		args := *in.AzureOidc
+		applyAzureOidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildAzureOidcConfig(args)
+	}
+	if in.AzureMicrosoft != nil {
+		set++
+		connectorType, config = "microsoft", buildAzureMicrosoftConfig(*in.AzureMicrosoft)
+	}
+	if in.CognitoOidc != nil {
+		set++
+		args := *in.CognitoOidc
+		applyCognitoOidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildCognitoOidcConfig(args)
+	}
+	if in.GitHub != nil {
+		set++
+		args := *in.GitHub
+		applyGitHubDefaults(&args)
+		connectorType, config = "github", buildGitHubConfig(args)
+	}
+	if in.GitLab != nil {
+		set++
+		args := *in.GitLab
+		applyGitLabDefaults(&args)
+		connectorType, config = "gitlab", buildGitLabConfig(args)
+	}
+	if in.Google != nil {
+		set++
+		args := *in.Google
+		applyGoogleDefaults(&args)
+		connectorType, config = "google", buildGoogleConfig(args)
+	}
+	if in.KeycloakOidc != nil {
+		set++
+		args := *in.KeycloakOidc
+		applyKeycloakOidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildKeycloakOidcConfig(args)
+	}
+	if in.Ldap != nil {
+		set++
+		connectorType, config = "ldap", buildLdapConfig(*in.Ldap)
+	}
+	if in.OAuth != nil {
+		set++
+		connectorType, config = "oauth", buildOAuthConfig(*in.OAuth)
+	}
+	if in.OktaOidc != nil {
+		set++
+		args := *in.OktaOidc
+		applyOktaOidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildOktaOidcConfig(args)
+	}
+	if in.PingOidc != nil {
+		set++
+		args := *in.PingOidc
+		applyPingOidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildPingOidcConfig(args)
+	}
+	if in.SalesforceOidc != nil {
+		set++
+		args := *in.SalesforceOidc
+		applySalesforceOidcDefaults(cfg, &args)
+		connectorType, config = "oidc", buildSalesforceOidcConfig(args)
+	}
+
+	if set != 1 {
+		return PreviewConnectorConfigResult{}, fmt.Errorf("exactly one connector block must be set (got %d)", set)
+	}
+
+	// Round-trip through JSON so the redaction sees the same shapes Dex would store.
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return PreviewConnectorConfigResult{}, fmt.Errorf("failed to marshal connector config: %w", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return PreviewConnectorConfigResult{}, fmt.Errorf("failed to decode connector config: %w", err)
+	}
+	redacted, err := json.Marshal(RedactConfig(decoded))
+	if err != nil {
+		return PreviewConnectorConfigResult{}, fmt.Errorf("failed to marshal redacted connector config: %w", err)
+	}
+
+	return PreviewConnectorConfigResult{
+		Type:   connectorType,
+		Config: string(redacted),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+)
+
+func TestPreviewConnectorConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         PreviewConnectorConfigArgs
+		wantType   string
+		wantIssuer string
+	}{
+		{
+			name: "auth0Oidc",
+			in: PreviewConnectorConfigArgs{Auth0Oidc: &Auth0OidcConnectorArgs{
+				ConnectorId:  "auth0",
+				Name:         "Auth0",
+				Domain:       "example.eu.auth0.com",
+				ClientId:     "client",
+				ClientSecret: "secret",
+				RedirectUri:  "https://dex.example.com/callback",
+			}},
+			wantType:   "oidc",
+			wantIssuer: "https://example.eu.auth0.com/",
+		},
+		{
+			name: "awsSsoOidc",
+			in: PreviewConnectorConfigArgs{AwsSsoOidc: &AwsSsoOidcConnectorArgs{
+				ConnectorId:  "aws",
+				Name:         "AWS",
+				Region:       "eu-central-1",
+				StartUrl:     "https://d-1234567890.awsapps.com/start",
+				ClientId:     "client",
+				ClientSecret: "secret",
+				RedirectUri:  "https://dex.example.com/callback",
+			}},
+			wantType:   "oidc",
+			wantIssuer: "https://oidc.eu-central-1.amazonaws.com/d-1234567890",
+		},
+		{
+			name: "salesforceOidc",
+			in: PreviewConnectorConfigArgs{SalesforceOidc: &SalesforceOidcConnectorArgs{
+				ConnectorId:  "salesforce",
+				Name:         "Salesforce",
+				InstanceUrl:  "https://example.my.salesforce.com",
+				ClientId:     "client",
+				ClientSecret: "secret",
+				RedirectUri:  "https://dex.example.com/callback",
+			}},
+			wantType:   "oidc",
+			wantIssuer: "https://example.my.salesforce.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := previewConnectorConfig(provider.DexConfig{}, tt.in)
+			if err != nil {
+				t.Fatalf("previewConnectorConfig() error = %v", err)
+			}
+			if out.Type != tt.wantType {
+				t.Errorf("type = %q, want %q", out.Type, tt.wantType)
+			}
+			var config map[string]any
+			if err := json.Unmarshal([]byte(out.Config), &config); err != nil {
+				t.Fatalf("config is not JSON: %v", err)
+			}
+			if config["issuer"] != tt.wantIssuer {
+				t.Errorf("issuer = %v, want %q", config["issuer"], tt.wantIssuer)
+			}
+			if config["clientSecret"] != redactedValue {
+				t.Errorf("clientSecret = %v, want it redacted", config["clientSecret"])
+			}
+		})
+	}
+}
+
+func TestPreviewConnectorConfigRequiresOneBlock(t *testing.T) {
+	if _, err := previewConnectorConfig(provider.DexConfig{}, PreviewConnectorConfigArgs{}); err == nil {
+		t.Error("previewConnectorConfig() with no block succeeded, want an error")
+	}
+}
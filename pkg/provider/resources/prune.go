@@ -0,0 +1,198 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// pruneConnectors / pruneClients - Delete everything not on a keep-list
+// ============================================================================
+
+// defaultPruneConcurrency is the number of deletes pruning runs at once.
+const defaultPruneConcurrency = 4
+
+// PruneArgs defines inputs for the pruneConnectors and pruneClients functions.
+type PruneArgs struct {
+	Keep        []string `pulumi:"keep"`
+	DryRun      *bool    `pulumi:"dryRun,optional"`
+	Concurrency *int     `pulumi:"concurrency,optional"`
+}
+
+// PruneResult defines outputs for the pruneConnectors and pruneClients functions.
+type PruneResult struct {
+	Deleted []string `pulumi:"deleted"`
+	Kept    []string `pulumi:"kept"`
+}
+
+// Annotate provides schema metadata for PruneArgs.
+func (a *PruneArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.Keep, "IDs to keep. Required and must not be empty, so a missing list can never delete everything.")
+	an.Describe(&a.DryRun, "If true, nothing is deleted and deleted lists what would be. Defaults to false.")
+	an.Describe(&a.Concurrency, "Maximum number of deletes in flight at once. Defaults to 4.")
+}
+
+// Annotate provides schema metadata for PruneResult.
+func (r *PruneResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Deleted, "IDs that were deleted (or would be, with dryRun), sorted.")
+	a.Describe(&r.Kept, "IDs in Dex that matched the keep-list, sorted.")
+}
+
+// PruneConnectors deletes every connector in Dex that is not on a keep-list.
+type PruneConnectors struct{}
+
+// Annotate provides schema metadata.
+func (f *PruneConnectors) Annotate(a infer.Annotator) {
+	a.Describe(f, "Deletes every connector in Dex whose ID is not in keep, e.g. to clean up after test runs. "+
+		"Deletes run in parallel and each one is verified like dex.Connector's delete. "+
+		"Functions are also invoked during preview, so set dryRun until the keep-list is known to be right.")
+}
+
+// Invoke lists the connectors in Dex and deletes those not on the keep-list.
+func (f *PruneConnectors) Invoke(ctx context.Context, req infer.FunctionRequest[PruneArgs]) (infer.FunctionResponse[PruneResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[PruneResult]{}, provider.ErrClientNotConfigured
+	}
+
+	result, err := prune(ctx, cfg, "connector", req.Input,
+		func(ctx context.Context) ([]string, error) {
+			listResp, err := cfg.Client.ListConnectors(ctx, &api.ListConnectorReq{})
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]string, 0, len(listResp.Connectors))
+			for _, con := range listResp.Connectors {
+				ids = append(ids, con.Id)
+			}
+			return ids, nil
+		},
+		func(ctx context.Context, id string) error {
+			return deleteConnector(ctx, cfg, id, nil)
+		})
+	return infer.FunctionResponse[PruneResult]{Output: result}, err
+}
+
+// PruneClients deletes every OAuth2 client in Dex that is not on a keep-list.
+type PruneClients struct{}
+
+// Annotate provides schema metadata.
+func (f *PruneClients) Annotate(a infer.Annotator) {
+	a.Describe(f, "Deletes every OAuth2 client in Dex whose ID is not in keep, e.g. to clean up after test runs. "+
+		"Deletes run in parallel. "+
+		"Functions are also invoked during preview, so set dryRun until the keep-list is known to be right.")
+}
+
+// Invoke lists the clients in Dex and deletes those not on the keep-list.
+func (f *PruneClients) Invoke(ctx context.Context, req infer.FunctionRequest[PruneArgs]) (infer.FunctionResponse[PruneResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[PruneResult]{}, provider.ErrClientNotConfigured
+	}
+
+	result, err := prune(ctx, cfg, "client", req.Input,
+		func(ctx context.Context) ([]string, error) {
+			listResp, err := cfg.Client.ListClients(ctx, &api.ListClientReq{})
+			if err != nil {
+				return nil, err
+			}
+			ids := make([]string, 0, len(listResp.Clients))
+			for _, client := range listResp.Clients {
+				ids = append(ids, client.Id)
+			}
+			return ids, nil
+		},
+		func(ctx context.Context, id string) error {
+			deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+			defer cancel()
+			_, err := cfg.Client.DeleteClient(deleteCtx, &api.DeleteClientReq{Id: id})
+			if err != nil && status.Code(err) != codes.NotFound {
+				return provider.WrapError("delete", "client", id, err)
+			}
+			return nil
+		})
+	return infer.FunctionResponse[PruneResult]{Output: result}, err
+}
+
+// prune lists the IDs of one kind of object and deletes those not in
+// args.Keep, running at most args.Concurrency deletes at once. All deletes
+// are attempted even if some fail; the error then names every failure.
+func prune(ctx context.Context, cfg provider.DexConfig, kind string, args PruneArgs,
+	list func(context.Context) ([]string, error), del func(context.Context, string) error,
+) (PruneResult, error) {
+	if len(args.Keep) == 0 {
+		return PruneResult{}, fmt.Errorf("keep must list at least one %s ID; refusing to delete every %s", kind, kind)
+	}
+	concurrency := provider.PtrOr(args.Concurrency, defaultPruneConcurrency)
+	if concurrency <= 0 {
+		return PruneResult{}, fmt.Errorf("concurrency must be positive")
+	}
+
+	keep := map[string]bool{}
+	for _, id := range args.Keep {
+		keep[id] = true
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+	ids, err := list(listCtx)
+	if err != nil {
+		return PruneResult{}, fmt.Errorf("failed to list %ss: %w", kind, err)
+	}
+
+	result := PruneResult{Deleted: []string{}, Kept: []string{}}
+	var doomed []string
+	for _, id := range ids {
+		if keep[id] {
+			result.Kept = append(result.Kept, id)
+		} else {
+			doomed = append(doomed, id)
+		}
+	}
+	sort.Strings(result.Kept)
+	sort.Strings(doomed)
+
+	if provider.PtrOr(args.DryRun, false) {
+		result.Deleted = append(result.Deleted, doomed...)
+		return result, nil
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+		sem  = make(chan struct{}, concurrency)
+	)
+	for _, id := range doomed {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := del(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			result.Deleted = append(result.Deleted, id)
+		}(id)
+	}
+	wg.Wait()
+
+	sort.Strings(result.Deleted)
+	if len(errs) > 0 {
+		return result, fmt.Errorf("deleted %d of %d %ss not on the keep-list: %w", len(result.Deleted), len(doomed), kind, errors.Join(errs...))
+	}
+	return result, nil
+}
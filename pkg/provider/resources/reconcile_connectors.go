@@ -0,0 +1,167 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// reconcileConnectors - Compares desired connectors with those in Dex
+// ============================================================================
+
+// ReconcileConnectorsArgs defines inputs for the reconcileConnectors function.
+type ReconcileConnectorsArgs struct {
+	Desired []ConnectorArgs `pulumi:"desired"`
+}
+
+// ConnectorDrift describes how a connector in Dex differs from its desired definition.
+type ConnectorDrift struct {
+	ConnectorId   string         `pulumi:"connectorId"`
+	Fields        []string       `pulumi:"fields"`
+	ConfigChanges []ConfigChange `pulumi:"configChanges"`
+}
+
+// ReconcileConnectorsResult defines outputs for the reconcileConnectors function.
+type ReconcileConnectorsResult struct {
+	Missing []string         `pulumi:"missing"`
+	Extra   []string         `pulumi:"extra"`
+	Changed []ConnectorDrift `pulumi:"changed"`
+	InSync  bool             `pulumi:"inSync"`
+}
+
+// ReconcileConnectors reports drift between a desired connector list and Dex.
+type ReconcileConnectors struct{}
+
+// Annotate provides schema metadata.
+func (f *ReconcileConnectors) Annotate(a infer.Annotator) {
+	a.Describe(f, "Compares a desired list of connectors with the connectors in Dex and reports which are missing, extra, or changed. "+
+		"Configs are compared the same way dex.Connector's diff compares them: key order, server-added defaults, and the provider's ignoreConfigKeys are disregarded. "+
+		"Secret values are redacted in the report. Read-only; useful for quick drift checks outside a full preview.")
+}
+
+// Annotate provides schema metadata for ReconcileConnectorsArgs.
+func (a *ReconcileConnectorsArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.Desired, "Desired connectors, in the same shape as dex.Connector inputs.")
+}
+
+// Annotate provides schema metadata for ConnectorDrift.
+func (d *ConnectorDrift) Annotate(a infer.Annotator) {
+	a.Describe(&d.ConnectorId, "ID of the drifted connector.")
+	a.Describe(&d.Fields, "Top-level connector fields that differ: 'type' and/or 'name'.")
+	a.Describe(&d.ConfigChanges, "Config differences from the live config (old) to the desired config (new), sorted by path.")
+}
+
+// Annotate provides schema metadata for ReconcileConnectorsResult.
+func (r *ReconcileConnectorsResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Missing, "Desired connector IDs that do not exist in Dex, sorted.")
+	a.Describe(&r.Extra, "Connector IDs in Dex that are not in the desired list, sorted.")
+	a.Describe(&r.Changed, "Connectors present in both whose type, name, or config differ, sorted by ID.")
+	a.Describe(&r.InSync, "Whether nothing is missing, extra, or changed.")
+}
+
+// Invoke lists the connectors in Dex and compares them with the desired list.
+func (f *ReconcileConnectors) Invoke(ctx context.Context, req infer.FunctionRequest[ReconcileConnectorsArgs]) (infer.FunctionResponse[ReconcileConnectorsResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[ReconcileConnectorsResult]{}, provider.ErrClientNotConfigured
+	}
+
+	desired := map[string]ConnectorArgs{}
+	for i, args := range req.Input.Desired {
+		if args.ConnectorId == "" {
+			return infer.FunctionResponse[ReconcileConnectorsResult]{}, fmt.Errorf("desired[%d]: connectorId is required", i)
+		}
+		if _, dup := desired[args.ConnectorId]; dup {
+			return infer.FunctionResponse[ReconcileConnectorsResult]{}, fmt.Errorf("desired[%d]: duplicate connectorId %q", i, args.ConnectorId)
+		}
+		if err := validateConnectorArgs(args); err != nil {
+			return infer.FunctionResponse[ReconcileConnectorsResult]{}, fmt.Errorf("desired[%d] (%s): %w", i, args.ConnectorId, err)
+		}
+		desired[args.ConnectorId] = args
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	if err != nil {
+		return infer.FunctionResponse[ReconcileConnectorsResult]{}, fmt.Errorf("failed to list connectors: %w", err)
+	}
+
+	result := ReconcileConnectorsResult{
+		Missing: []string{},
+		Extra:   []string{},
+		Changed: []ConnectorDrift{},
+	}
+
+	live := map[string]bool{}
+	for _, con := range listResp.Connectors {
+		live[con.Id] = true
+		want, ok := desired[con.Id]
+		if !ok {
+			result.Extra = append(result.Extra, con.Id)
+			continue
+		}
+		drift, err := connectorDrift(con, want, cfg.IgnoreConfigKeys)
+		if err != nil {
+			return infer.FunctionResponse[ReconcileConnectorsResult]{}, err
+		}
+		if drift != nil {
+			result.Changed = append(result.Changed, *drift)
+		}
+	}
+	for id := range desired {
+		if !live[id] {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+
+	sort.Strings(result.Missing)
+	sort.Strings(result.Extra)
+	sort.Slice(result.Changed, func(i, j int) bool { return result.Changed[i].ConnectorId < result.Changed[j].ConnectorId })
+	result.InSync = len(result.Missing) == 0 && len(result.Extra) == 0 && len(result.Changed) == 0
+
+	return infer.FunctionResponse[ReconcileConnectorsResult]{Output: result}, nil
+}
+
+// connectorDrift compares a live connector with its desired args using the
+// same canonicalization as Connector's Diff. It returns nil when they match.
+func connectorDrift(con *api.Connector, want ConnectorArgs, ignoreKeys []string) (*ConnectorDrift, error) {
+	drift := ConnectorDrift{
+		ConnectorId:   con.Id,
+		Fields:        []string{},
+		ConfigChanges: []ConfigChange{},
+	}
+	if con.Type != want.Type {
+		drift.Fields = append(drift.Fields, "type")
+	}
+	if con.Name != want.Name {
+		drift.Fields = append(drift.Fields, "name")
+	}
+
+	wantConfig, err := canonicalConnectorConfig(want, ignoreKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("connector %q: %w", con.Id, err)
+	}
+	wantMap, _ := wantConfig.(map[string]any)
+
+	var liveMap map[string]any
+	if err := json.Unmarshal(stripConnectorDefaults(con.Type, trimConfigBytes(con.Config)), &liveMap); err != nil {
+		return nil, fmt.Errorf("connector %q has a config that is not valid JSON: %w", con.Id, err)
+	}
+	for _, key := range ignoreKeys {
+		delete(liveMap, key)
+	}
+
+	drift.ConfigChanges = diffConfigMaps(liveMap, wantMap)
+	if len(drift.Fields) == 0 && len(drift.ConfigChanges) == 0 {
+		return nil, nil
+	}
+	return &drift, nil
+}
@@ -0,0 +1,188 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// reorderConnectors - Recreates connectors so storage order matches a list
+// ============================================================================
+
+// ReorderConnectorsArgs defines inputs for the reorderConnectors function.
+type ReorderConnectorsArgs struct {
+	Ids     []string          `pulumi:"ids"`
+	Secrets map[string]string `pulumi:"secrets,optional" provider:"secret"`
+	DryRun  *bool             `pulumi:"dryRun,optional"`
+}
+
+// ReorderConnectorsResult defines outputs for the reorderConnectors function.
+type ReorderConnectorsResult struct {
+	Order     []string `pulumi:"order"`
+	Recreated []string `pulumi:"recreated"`
+	DryRun    bool     `pulumi:"dryRun"`
+}
+
+// ReorderConnectors deletes and recreates connectors in a requested order.
+type ReorderConnectors struct{}
+
+// Annotate provides schema metadata.
+func (f *ReorderConnectors) Annotate(a infer.Annotator) {
+	a.Describe(f, "Operator tool that controls the login-screen order of connectors. Dex has no native ordering and lists connectors in storage order, "+
+		"which on most backends follows creation order. This function deletes the listed connectors and recreates them in the requested sequence. "+
+		"Connectors not listed keep their place ahead of the reordered ones. Runs as a dry run unless dryRun is explicitly false. "+
+		"Static connectors from the Dex config file cannot be reordered and cause the function to fail before anything is deleted.")
+}
+
+// Annotate provides schema metadata for ReorderConnectorsArgs.
+func (a *ReorderConnectorsArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.Ids, "Connector IDs in the desired order.")
+	an.Describe(&a.Secrets, "Client secrets keyed by connector ID. Required for every listed connector whose config has a clientSecret, since secrets are re-supplied rather than copied from Dex.")
+	an.Describe(&a.DryRun, "If true (the default), only validate and report the planned order without changing Dex.")
+}
+
+// Annotate provides schema metadata for ReorderConnectorsResult.
+func (r *ReorderConnectorsResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Order, "Connector IDs in the resulting (or, for a dry run, planned) storage order.")
+	a.Describe(&r.Recreated, "Connector IDs that were (or would be) deleted and recreated.")
+	a.Describe(&r.DryRun, "Whether this was a dry run.")
+}
+
+// Invoke validates the request and, unless dryRun, recreates the connectors in order.
+func (f *ReorderConnectors) Invoke(ctx context.Context, req infer.FunctionRequest[ReorderConnectorsArgs]) (infer.FunctionResponse[ReorderConnectorsResult], error) {
+	in := req.Input
+	dryRun := provider.PtrOr(in.DryRun, true)
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[ReorderConnectorsResult]{}, provider.ErrClientNotConfigured
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	listResp, err := cfg.Client.ListConnectors(listCtx, &api.ListConnectorReq{})
+	if err != nil {
+		return infer.FunctionResponse[ReorderConnectorsResult]{}, fmt.Errorf("failed to list connectors: %w", err)
+	}
+
+	existing := map[string]*api.Connector{}
+	for _, con := range listResp.Connectors {
+		existing[con.Id] = con
+	}
+
+	// Build the recreated connectors up front so that every validation error
+	// surfaces before anything is deleted.
+	seen := map[string]bool{}
+	planned := make([]*api.Connector, 0, len(in.Ids))
+	for _, id := range in.Ids {
+		if seen[id] {
+			return infer.FunctionResponse[ReorderConnectorsResult]{}, fmt.Errorf("connector %q is listed more than once", id)
+		}
+		seen[id] = true
+
+		con, ok := existing[id]
+		if !ok {
+			return infer.FunctionResponse[ReorderConnectorsResult]{}, fmt.Errorf("connector %q not found", id)
+		}
+
+		configBytes, err := reorderConnectorConfig(con, in.Secrets)
+		if err != nil {
+			return infer.FunctionResponse[ReorderConnectorsResult]{}, err
+		}
+		planned = append(planned, &api.Connector{
+			Id:     con.Id,
+			Type:   con.Type,
+			Name:   con.Name,
+			Config: configBytes,
+		})
+	}
+
+	order := make([]string, 0, len(listResp.Connectors))
+	for _, con := range listResp.Connectors {
+		if !seen[con.Id] {
+			order = append(order, con.Id)
+		}
+	}
+	order = append(order, in.Ids...)
+
+	if dryRun {
+		return infer.FunctionResponse[ReorderConnectorsResult]{
+			Output: ReorderConnectorsResult{Order: order, Recreated: in.Ids, DryRun: true},
+		}, nil
+	}
+
+	// Static connectors are read-only in Dex; a no-op update detects them
+	// before any connector has been deleted.
+	for _, con := range planned {
+		orig := existing[con.Id]
+		updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+		_, err := cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+			Id:        orig.Id,
+			NewType:   orig.Type,
+			NewName:   orig.Name,
+			NewConfig: orig.Config,
+		})
+		cancel()
+		if err != nil {
+			if strings.Contains(err.Error(), "static") {
+				return infer.FunctionResponse[ReorderConnectorsResult]{}, fmt.Errorf("connector %q is a static connector and cannot be reordered", con.Id)
+			}
+			return infer.FunctionResponse[ReorderConnectorsResult]{}, provider.WrapError("update", "connector", con.Id, err)
+		}
+	}
+
+	for _, con := range planned {
+		deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+		_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{Id: con.Id})
+		cancel()
+		if err != nil {
+			return infer.FunctionResponse[ReorderConnectorsResult]{}, provider.WrapError("delete", "connector", con.Id, err)
+		}
+
+		createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+		_, err = cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{Connector: con})
+		cancel()
+		if err != nil {
+			return infer.FunctionResponse[ReorderConnectorsResult]{}, fmt.Errorf("connector %q was deleted but could not be recreated; recreate it manually: %w", con.Id, err)
+		}
+	}
+
+	return infer.FunctionResponse[ReorderConnectorsResult]{
+		Output: ReorderConnectorsResult{Order: order, Recreated: in.Ids, DryRun: false},
+	}, nil
+}
+
+// reorderConnectorConfig returns the config to recreate con with. A connector
+// whose config has a clientSecret must have its secret re-supplied in secrets.
+func reorderConnectorConfig(con *api.Connector, secrets map[string]string) ([]byte, error) {
+	if len(con.Config) == 0 {
+		return con.Config, nil
+	}
+
+	var config map[string]any
+	if err := json.Unmarshal(con.Config, &config); err != nil {
+		return nil, fmt.Errorf("connector %q has an unparseable config: %w", con.Id, err)
+	}
+	if _, ok := config["clientSecret"]; !ok {
+		return con.Config, nil
+	}
+
+	secret, ok := secrets[con.Id]
+	if !ok || secret == "" {
+		return nil, fmt.Errorf("connector %q has a clientSecret; supply it in secrets[%q]", con.Id, con.Id)
+	}
+	config["clientSecret"] = secret
+
+	out, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config for connector %q: %w", con.Id, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,117 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// rotateClientSecret - Imperatively replaces a client's secret
+// ============================================================================
+
+// RotateClientSecretArgs defines inputs for the rotateClientSecret function.
+type RotateClientSecretArgs struct {
+	Id     string  `pulumi:"id"`
+	Secret *string `pulumi:"secret,optional" provider:"secret"`
+}
+
+// RotateClientSecretResult defines outputs for the rotateClientSecret function.
+type RotateClientSecretResult struct {
+	Id     string `pulumi:"id"`
+	Secret string `pulumi:"secret" provider:"secret"`
+}
+
+// RotateClientSecret replaces the secret of an existing Dex client.
+type RotateClientSecret struct{}
+
+// Annotate provides schema metadata.
+func (f *RotateClientSecret) Annotate(a infer.Annotator) {
+	a.Describe(f, "Rotates the secret of an existing Dex client and returns the new secret. "+
+		"Dex cannot update a client secret in place, so the client is deleted and recreated with the same settings; "+
+		"refresh tokens issued to the client are invalidated and clients listing it in trustedPeers keep working since the ID is unchanged. "+
+		"Intended for operator scripts: the rotation happens on every invocation, including during `pulumi preview`.")
+}
+
+// Annotate provides schema metadata for RotateClientSecretArgs.
+func (a *RotateClientSecretArgs) Annotate(an infer.Annotator) {
+	an.Describe(&a.Id, "ID of the client whose secret should be rotated.")
+	an.Describe(&a.Secret, "New secret to set. If not provided, a secure random secret is generated.")
+}
+
+// Annotate provides schema metadata for RotateClientSecretResult.
+func (r *RotateClientSecretResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.Id, "ID of the rotated client.")
+	a.Describe(&r.Secret, "The new client secret.")
+}
+
+// Invoke rotates the client secret by recreating the client.
+func (f *RotateClientSecret) Invoke(ctx context.Context, req infer.FunctionRequest[RotateClientSecretArgs]) (infer.FunctionResponse[RotateClientSecretResult], error) {
+	in := req.Input
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[RotateClientSecretResult]{}, provider.ErrClientNotConfigured
+	}
+
+	getCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	getResp, err := cfg.Client.GetClient(getCtx, &api.GetClientReq{Id: in.Id})
+	if err != nil {
+		return infer.FunctionResponse[RotateClientSecretResult]{}, provider.WrapError("get", "client", in.Id, err)
+	}
+	if getResp.Client == nil {
+		return infer.FunctionResponse[RotateClientSecretResult]{}, fmt.Errorf("client with id %q not found", in.Id)
+	}
+	existing := getResp.Client
+
+	secret := provider.PtrOr(in.Secret, "")
+	if secret == "" {
+		generated, err := generateClientSecret()
+		if err != nil {
+			return infer.FunctionResponse[RotateClientSecretResult]{}, provider.WrapError("rotate", "client", in.Id, err)
+		}
+		secret = generated
+	}
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	if _, err := cfg.Client.DeleteClient(deleteCtx, &api.DeleteClientReq{Id: in.Id}); err != nil {
+		return infer.FunctionResponse[RotateClientSecretResult]{}, provider.WrapError("delete", "client", in.Id, err)
+	}
+
+	rotated := &api.Client{
+		Id:           existing.Id,
+		Secret:       secret,
+		RedirectUris: existing.RedirectUris,
+		TrustedPeers: existing.TrustedPeers,
+		Public:       existing.Public,
+		Name:         existing.Name,
+		LogoUrl:      existing.LogoUrl,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+	defer cancel()
+
+	if _, err := cfg.Client.CreateClient(createCtx, &api.CreateClientReq{Client: rotated}); err != nil {
+		// Put the original client back so a failed rotation does not leave it deleted.
+		restoreCtx, restoreCancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, nil))
+		defer restoreCancel()
+		if _, restoreErr := cfg.Client.CreateClient(restoreCtx, &api.CreateClientReq{Client: existing}); restoreErr != nil {
+			return infer.FunctionResponse[RotateClientSecretResult]{}, fmt.Errorf("dex rotate client %q: recreate failed (%v) and restoring the original client also failed: %w", in.Id, err, restoreErr)
+		}
+		return infer.FunctionResponse[RotateClientSecretResult]{}, provider.WrapError("rotate", "client", in.Id, err)
+	}
+
+	return infer.FunctionResponse[RotateClientSecretResult]{
+		Output: RotateClientSecretResult{
+			Id:     in.Id,
+			Secret: secret,
+		},
+	}, nil
+}
@@ -0,0 +1,407 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	p "github.com/pulumi/pulumi-go-provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ============================================================================
+// SalesforceOidcConnector - Uses generic OIDC connector (type: "oidc")
+// ============================================================================
+
+// salesforceIssuerRegex matches the issuers of Salesforce's login hosts and
+// My Domain instances, used to recognize Salesforce connectors on import.
+var salesforceIssuerRegex = regexp.MustCompile(`^https://(login|test|[a-z0-9-]+(\.(sandbox|develop))?\.my)\.salesforce\.com$`)
+
+// SalesforceOidcConnectorArgs defines inputs for SalesforceOidcConnector.
+type SalesforceOidcConnectorArgs struct {
+	ConnectorId    string         `pulumi:"connectorId"`
+	Name           string         `pulumi:"name"`
+	InstanceUrl    string         `pulumi:"instanceUrl"`
+	ClientId       string         `pulumi:"clientId"`
+	ClientSecret   string         `pulumi:"clientSecret" provider:"secret"`
+	RedirectUri    string         `pulumi:"redirectUri"`
+	Scopes         []string       `pulumi:"scopes,optional"`
+	UserNameSource *string        `pulumi:"userNameSource,optional"` // "email" | "sub" | "preferred_username"
+	ExtraOidc      map[string]any `pulumi:"extraOidc,optional"`
+
+	OverrideClaimMapping *bool             `pulumi:"overrideClaimMapping,optional"`
+	ClaimMapping         *OIDCClaimMapping `pulumi:"claimMapping,optional"`
+	TimeoutSeconds       *int              `pulumi:"timeoutSeconds,optional"`
+}
+
+// SalesforceOidcConnectorState defines outputs for SalesforceOidcConnector.
+type SalesforceOidcConnectorState struct {
+	SalesforceOidcConnectorArgs
+	RawConfigOut *string `pulumi:"rawConfigOut,optional"`
+	CallbackUrl  *string `pulumi:"callbackUrl,optional"`
+}
+
+// SalesforceOidcConnector manages a Salesforce connector using Dex's generic OIDC connector.
+type SalesforceOidcConnector struct{}
+
+// Annotate provides schema metadata.
+func (c *SalesforceOidcConnector) Annotate(a infer.Annotator) {
+	a.Describe(c, "Manages a Salesforce connector in Dex using the generic OIDC connector (type: oidc). The issuer is the instanceUrl, from which Dex discovers the endpoints.")
+}
+
+// Annotate provides schema metadata for SalesforceOidcConnectorArgs.
+func (c *SalesforceOidcConnectorArgs) Annotate(a infer.Annotator) {
+	a.Describe(&c.ConnectorId, "Unique identifier for the Salesforce connector.")
+	a.Describe(&c.Name, "Human-readable name for the connector, displayed to users during login.")
+	a.Describe(&c.InstanceUrl, "Salesforce login URL: 'https://login.salesforce.com', 'https://test.salesforce.com' for sandboxes, or a My Domain URL such as 'https://acme.my.salesforce.com'. Must use https; a trailing slash is removed.")
+	a.Describe(&c.ClientId, "Consumer key of the Salesforce connected app.")
+	a.Describe(&c.ClientSecret, "Consumer secret of the Salesforce connected app.")
+	a.Describe(&c.RedirectUri, "Callback URL registered in the connected app. Must match Dex's callback URL.")
+	a.Describe(&c.Scopes, "OIDC scopes to request. Defaults to ['openid', 'email', 'profile'] if not specified.")
+	a.Describe(&c.UserNameSource, "Source for the username claim. Valid values: 'email' (default), 'sub' (subject), or 'preferred_username' (the Salesforce username).")
+	a.Describe(&c.ExtraOidc, "Additional OIDC configuration fields as key-value pairs for advanced scenarios.")
+	a.Describe(&c.OverrideClaimMapping, "If true, Dex uses claimMapping even when the standard claims are present in the ID token.")
+	a.Describe(&c.ClaimMapping, "Mapping of nonstandard upstream claims to Dex user attributes.")
+	a.Describe(&c.TimeoutSeconds, "Timeout in seconds for this resource's Dex API calls, overriding the provider's timeoutSeconds.")
+}
+
+// Annotate provides schema metadata for SalesforceOidcConnectorState.
+func (c *SalesforceOidcConnectorState) Annotate(a infer.Annotator) {
+	// SalesforceOidcConnectorState embeds SalesforceOidcConnectorArgs, so field descriptions are inherited
+	a.Describe(&c.RawConfigOut, "The connector config exactly as stored in Dex at the last refresh, with secrets redacted. Empty after create or update until the next refresh.")
+	a.Describe(&c.CallbackUrl, "Dex's callback URL to register as the redirect URI with the upstream identity provider, derived from the provider's dexPublicUrl. Unset when dexPublicUrl is not configured.")
+}
+
+// Check validates inputs and normalizes instanceUrl, so that it round-trips
+// through the issuer Read derives it from.
+func (c *SalesforceOidcConnector) Check(ctx context.Context, req infer.CheckRequest) (infer.CheckResponse[SalesforceOidcConnectorArgs], error) {
+	args, failures, err := infer.DefaultCheck[SalesforceOidcConnectorArgs](ctx, req.NewInputs)
+	if err != nil {
+		return infer.CheckResponse[SalesforceOidcConnectorArgs]{Failures: failures}, err
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+
+	// Validate instanceUrl; it is used as the issuer as is.
+	args.InstanceUrl = strings.TrimRight(strings.TrimSpace(args.InstanceUrl), "/")
+	if args.InstanceUrl != "" {
+		if u, err := url.Parse(args.InstanceUrl); err != nil || u.Scheme != "https" || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+			failures = append(failures, p.CheckFailure{
+				Property: "instanceUrl",
+				Reason:   fmt.Sprintf("must be an https URL without path, query, or fragment, such as https://login.salesforce.com; got %q", args.InstanceUrl),
+			})
+		}
+	}
+
+	// Validate userNameSource
+	if args.UserNameSource != nil {
+		valid := map[string]bool{"email": true, "sub": true, "preferred_username": true}
+		if !valid[*args.UserNameSource] {
+			failures = append(failures, p.CheckFailure{
+				Property: "userNameSource",
+				Reason:   "must be one of: email, sub, preferred_username",
+			})
+		}
+	}
+
+	failures = append(failures, validateRedirectURI(ctx, cfg, "redirectUri", args.RedirectUri)...)
+	failures = append(failures, validateScopes("scopes", args.Scopes)...)
+	failures = append(failures, checkOpenIDScope(ctx, cfg, "scopes", args.Scopes)...)
+
+	applySalesforceOidcDefaults(cfg, &args)
+
+	return infer.CheckResponse[SalesforceOidcConnectorArgs]{
+		Inputs:   args,
+		Failures: failures,
+	}, nil
+}
+
+// Diff reports changes to the fields in provider.ImmutableFields as replacements.
+func (c *SalesforceOidcConnector) Diff(ctx context.Context, req infer.DiffRequest[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState]) (infer.DiffResponse, error) {
+	return diffResource("SalesforceOidcConnector", req.State.SalesforceOidcConnectorArgs, req.Inputs), nil
+}
+
+// Create creates a new Salesforce OIDC connector.
+func (c *SalesforceOidcConnector) Create(ctx context.Context, req infer.CreateRequest[SalesforceOidcConnectorArgs]) (infer.CreateResponse[SalesforceOidcConnectorState], error) {
+	args := req.Inputs
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.CreateResponse[SalesforceOidcConnectorState]{}, err
+		}
+		state := SalesforceOidcConnectorState{
+			SalesforceOidcConnectorArgs: args,
+			CallbackUrl:                 dexCallbackURL(ctx),
+		}
+		return infer.CreateResponse[SalesforceOidcConnectorState]{
+			ID:     args.ConnectorId,
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.CreateResponse[SalesforceOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	configBytes, err := json.Marshal(buildSalesforceOidcConfig(args))
+	if err != nil {
+		return infer.CreateResponse[SalesforceOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+
+	connector := &api.Connector{
+		Id:     args.ConnectorId,
+		Type:   "oidc",
+		Name:   args.Name,
+		Config: configBytes,
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	resp, err := cfg.Client.CreateConnector(createCtx, &api.CreateConnectorReq{
+		Connector: connector,
+	})
+	if err != nil {
+		return infer.CreateResponse[SalesforceOidcConnectorState]{}, provider.WrapError("create", "salesforce-oidc-connector", args.ConnectorId, err)
+	}
+
+	if resp.AlreadyExists {
+		// Adopt the existing connector if it matches, so Pulumi tracks it and destroy works.
+		if err := adoptExistingConnector(ctx, cfg, connector, args.TimeoutSeconds); err != nil {
+			return infer.CreateResponse[SalesforceOidcConnectorState]{}, err
+		}
+	}
+
+	state := SalesforceOidcConnectorState{
+		SalesforceOidcConnectorArgs: args,
+		CallbackUrl:                 dexCallbackURL(ctx),
+	}
+
+	return infer.CreateResponse[SalesforceOidcConnectorState]{
+		ID:     args.ConnectorId,
+		Output: state,
+	}, nil
+}
+
+// Read retrieves an existing Salesforce OIDC connector.
+func (c *SalesforceOidcConnector) Read(ctx context.Context, req infer.ReadRequest[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState]) (infer.ReadResponse[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.ReadResponse[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	found, err := findConnectorById(ctx, cfg, req.ID, req.Inputs.TimeoutSeconds)
+	if err != nil {
+		return infer.ReadResponse[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState]{}, err
+	}
+
+	if found == nil {
+		return infer.ReadResponse[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState]{}, nil
+	}
+
+	var configMap map[string]any
+	if err := json.Unmarshal(trimConfigBytes(found.Config), &configMap); err != nil {
+		// The connector still exists, so keep the previous state rather than
+		// reporting it as deleted.
+		return unparseableConnectorRead(ctx, req, found.Id, err)
+	}
+
+	// Keys in ignoreConfigKeys are not managed here; read them back as
+	// previously applied so external changes do not show up as a diff.
+	if len(cfg.IgnoreConfigKeys) > 0 && req.State.ConnectorId != "" {
+		restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, configMap, buildSalesforceOidcConfig(req.State.SalesforceOidcConnectorArgs))
+	}
+
+	// The issuer is the instance URL.
+	instanceUrl := strings.TrimRight(GetString(configMap, "issuer"), "/")
+
+	// The default userNameKey is reported as unset unless the program set it
+	// explicitly, so it does not diff.
+	userNameKey, _ := configMap["userNameKey"].(string)
+	userNameSource := &userNameKey
+	if userNameKey == "" || (userNameKey == "email" && req.Inputs.UserNameSource == nil) {
+		userNameSource = nil
+	}
+
+	scopes, _ := configMap["scopes"].([]any)
+	scopesStr := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if str, ok := s.(string); ok {
+			scopesStr = append(scopesStr, str)
+		}
+	}
+
+	args := SalesforceOidcConnectorArgs{
+		ConnectorId:    found.Id,
+		Name:           found.Name,
+		InstanceUrl:    instanceUrl,
+		ClientId:       GetString(configMap, "clientID"),
+		ClientSecret:   readSecret(cfg, GetString(configMap, "clientSecret"), req.State.ClientSecret, req.Inputs.ClientSecret),
+		RedirectUri:    GetString(configMap, "redirectURI"),
+		Scopes:         scopesStr,
+		UserNameSource: userNameSource,
+
+		OverrideClaimMapping: GetBoolPtr(configMap, "overrideClaimMapping"),
+		ClaimMapping:         decodeClaimMapping(configMap["claimMapping"]),
+		ExtraOidc:            readExtraOidc(configMap, req.Inputs.ExtraOidc),
+	}
+
+	args.TimeoutSeconds = req.Inputs.TimeoutSeconds
+
+	state := SalesforceOidcConnectorState{
+		SalesforceOidcConnectorArgs: args,
+		CallbackUrl:                 dexCallbackURL(ctx),
+		RawConfigOut:                redactedRawConfig(found.Config),
+	}
+
+	return infer.ReadResponse[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState]{
+		ID:     found.Id,
+		Inputs: args,
+		State:  state,
+	}, nil
+}
+
+// Update updates an existing Salesforce OIDC connector.
+func (c *SalesforceOidcConnector) Update(ctx context.Context, req infer.UpdateRequest[SalesforceOidcConnectorArgs, SalesforceOidcConnectorState]) (infer.UpdateResponse[SalesforceOidcConnectorState], error) {
+	args := req.Inputs
+	oldState := req.State
+
+	// In preview/dry-run mode, skip actual Dex API calls and return expected state
+	// This check MUST be first, before any other operations or config checks
+	if req.DryRun {
+		if err := checkPreviewConnectivity(ctx); err != nil {
+			return infer.UpdateResponse[SalesforceOidcConnectorState]{}, err
+		}
+		state := SalesforceOidcConnectorState{
+			SalesforceOidcConnectorArgs: args,
+			CallbackUrl:                 dexCallbackURL(ctx),
+		}
+		return infer.UpdateResponse[SalesforceOidcConnectorState]{
+			Output: state,
+		}, nil
+	}
+
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.UpdateResponse[SalesforceOidcConnectorState]{}, provider.ErrClientNotConfigured
+	}
+
+	if err := checkImmutableFields("SalesforceOidcConnector", oldState.SalesforceOidcConnectorArgs, args); err != nil {
+		return infer.UpdateResponse[SalesforceOidcConnectorState]{}, err
+	}
+
+	configBytes, err := json.Marshal(buildSalesforceOidcConfig(args))
+	if err != nil {
+		return infer.UpdateResponse[SalesforceOidcConnectorState]{}, fmt.Errorf("failed to marshal OIDC config: %w", err)
+	}
+	configBytes, err = preserveIgnoredConfigKeys(ctx, cfg, args.ConnectorId, configBytes, args.TimeoutSeconds)
+	if err != nil {
+		return infer.UpdateResponse[SalesforceOidcConnectorState]{}, err
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, args.TimeoutSeconds))
+	defer cancel()
+
+	_, err = cfg.Client.UpdateConnector(updateCtx, &api.UpdateConnectorReq{
+		Id:        args.ConnectorId,
+		NewType:   "oidc",
+		NewName:   args.Name,
+		NewConfig: configBytes,
+	})
+	if err != nil {
+		return infer.UpdateResponse[SalesforceOidcConnectorState]{}, provider.WrapError("update", "salesforce-oidc-connector", args.ConnectorId, err)
+	}
+
+	state := SalesforceOidcConnectorState{
+		SalesforceOidcConnectorArgs: args,
+		CallbackUrl:                 dexCallbackURL(ctx),
+	}
+
+	return infer.UpdateResponse[SalesforceOidcConnectorState]{
+		Output: state,
+	}, nil
+}
+
+// Delete deletes an Salesforce OIDC connector.
+func (c *SalesforceOidcConnector) Delete(ctx context.Context, req infer.DeleteRequest[SalesforceOidcConnectorState]) (infer.DeleteResponse, error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.DeleteResponse{}, provider.ErrClientNotConfigured
+	}
+
+	deleteID := req.ID
+	if deleteID == "" && req.State.ConnectorId != "" {
+		deleteID = req.State.ConnectorId
+	}
+
+	// Note: Pulumi does not call Delete during preview, so no preview check needed
+
+	deleteCtx, cancel := context.WithTimeout(ctx, provider.ResolveTimeout(cfg, req.State.TimeoutSeconds))
+	defer cancel()
+
+	_, err := cfg.Client.DeleteConnector(deleteCtx, &api.DeleteConnectorReq{
+		Id: deleteID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return infer.DeleteResponse{}, nil
+		}
+		return infer.DeleteResponse{}, provider.WrapError("delete", "salesforce-oidc-connector", deleteID, err)
+	}
+
+	if err := verifyConnectorDeleted(ctx, cfg, deleteID, req.State.TimeoutSeconds); err != nil {
+		return infer.DeleteResponse{}, err
+	}
+
+	return infer.DeleteResponse{}, nil
+}
+
+// applySalesforceOidcDefaults fills in defaults for optional SalesforceOidcConnector inputs.
+// Unset scopes default to the provider's defaultOidcScopes, if set.
+func applySalesforceOidcDefaults(cfg provider.DexConfig, args *SalesforceOidcConnectorArgs) {
+	args.Scopes = defaultOidcScopes(cfg, args.Scopes, "openid", "email", "profile")
+}
+
+// buildSalesforceOidcConfig builds the Dex "oidc" connector config for a
+// Salesforce instance. The issuer is instanceUrl, which serves the discovery
+// document, and userNameKey is derived from userNameSource.
+func buildSalesforceOidcConfig(args SalesforceOidcConnectorArgs) map[string]any {
+	issuer := args.InstanceUrl
+
+	userNameKey := "email" // default
+	if args.UserNameSource != nil {
+		userNameKey = *args.UserNameSource
+	}
+
+	oidcConfig := map[string]any{
+		"issuer":       issuer,
+		"clientID":     args.ClientId,
+		"clientSecret": args.ClientSecret,
+		"redirectURI":  args.RedirectUri,
+		"scopes":       args.Scopes,
+		"userNameKey":  userNameKey,
+	}
+
+	if args.OverrideClaimMapping != nil {
+		oidcConfig["overrideClaimMapping"] = *args.OverrideClaimMapping
+	}
+	if cm := claimMappingConfig(args.ClaimMapping); cm != nil {
+		oidcConfig["claimMapping"] = cm
+	}
+
+	for k, v := range args.ExtraOidc {
+		oidcConfig[k] = v
+	}
+
+	return oidcConfig
+}
@@ -0,0 +1,168 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	api "github.com/dexidp/dex/api/v2"
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+)
+
+// ============================================================================
+// SelfTest - Round-trips a throwaway connector and client against a live Dex
+// ============================================================================
+
+// SelfTest creates a throwaway OIDC connector and client, reads them back, and
+// checks that they decode to what was written using the same encode/decode
+// functions as the resources. Both objects are deleted before returning, also
+// when a step fails. Progress is written to out.
+func SelfTest(ctx context.Context, client api.DexClient, timeout time.Duration, out io.Writer) (err error) {
+	suffix := time.Now().UTC().Format("20060102150405")
+	connectorID := "selftest-connector-" + suffix
+	clientID := "selftest-client-" + suffix
+
+	call := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	insecureSkipEmailVerified := false
+	userNameKey := "email"
+	want := ConnectorArgs{
+		ConnectorId: connectorID,
+		Type:        "oidc",
+		Name:        "Self-test connector",
+		OIDCConfig: &OIDCConfig{
+			Issuer:                    "https://selftest.invalid",
+			ClientId:                  "selftest",
+			ClientSecret:              "selftest-secret",
+			RedirectUri:               "https://dex.invalid/callback",
+			Scopes:                    []string{"openid", "profile", "email"},
+			InsecureSkipEmailVerified: &insecureSkipEmailVerified,
+			UserNameKey:               &userNameKey,
+			ClaimMapping: &OIDCClaimMapping{
+				GroupsKey:            PtrOrString("roles"),
+				PreferredUsernameKey: PtrOrString("upn"),
+			},
+			Extra: map[string]any{"getUserInfo": true},
+		},
+	}
+
+	// Cleanup runs whatever happens below; its failures are reported too.
+	var createdConnector, createdClient bool
+	defer func() {
+		if createdClient {
+			cctx, cancel := call()
+			_, delErr := client.DeleteClient(cctx, &api.DeleteClientReq{Id: clientID})
+			cancel()
+			if delErr != nil {
+				fmt.Fprintf(out, "  ✗ failed to delete client %q: %v\n", clientID, delErr)
+				if err == nil {
+					err = fmt.Errorf("failed to delete client %q: %w", clientID, delErr)
+				}
+			} else {
+				fmt.Fprintf(out, "  ✓ deleted client %q\n", clientID)
+			}
+		}
+		if createdConnector {
+			cctx, cancel := call()
+			_, delErr := client.DeleteConnector(cctx, &api.DeleteConnectorReq{Id: connectorID})
+			cancel()
+			if delErr != nil {
+				fmt.Fprintf(out, "  ✗ failed to delete connector %q: %v\n", connectorID, delErr)
+				if err == nil {
+					err = fmt.Errorf("failed to delete connector %q: %w", connectorID, delErr)
+				}
+			} else {
+				fmt.Fprintf(out, "  ✓ deleted connector %q\n", connectorID)
+			}
+		}
+	}()
+
+	// Connector round trip
+	fmt.Fprintf(out, "=== Connector %q ===\n", connectorID)
+	configBytes, err := buildConnectorConfigBytes(want)
+	if err != nil {
+		return fmt.Errorf("failed to encode connector: %w", err)
+	}
+	cctx, cancel := call()
+	resp, err := client.CreateConnector(cctx, &api.CreateConnectorReq{
+		Connector: &api.Connector{Id: connectorID, Type: want.Type, Name: want.Name, Config: configBytes},
+	})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to create connector: %w", err)
+	}
+	if resp.AlreadyExists {
+		return fmt.Errorf("connector %q %w", connectorID, provider.ErrAlreadyExists)
+	}
+	createdConnector = true
+	fmt.Fprintf(out, "  ✓ created\n")
+
+	cctx, cancel = call()
+	listResp, err := client.ListConnectors(cctx, &api.ListConnectorReq{})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to list connectors: %w", err)
+	}
+	found := findByID(listResp.Connectors, connectorID)
+	if found == nil {
+		return fmt.Errorf("connector %q not found after create", connectorID)
+	}
+	got, _, err := decodeConnector(found)
+	if err != nil {
+		return fmt.Errorf("failed to decode connector: %w", err)
+	}
+	if got.Type != want.Type || got.Name != want.Name || !connectorConfigsEqual(got, want) {
+		gotBytes, _ := buildConnectorConfigBytes(got)
+		return fmt.Errorf("connector did not round-trip:\n  wrote: %s\n  read:  %s", configBytes, gotBytes)
+	}
+	fmt.Fprintf(out, "  ✓ read back and round-tripped\n")
+
+	// Client round trip
+	fmt.Fprintf(out, "=== Client %q ===\n", clientID)
+	secret, err := generateClientSecret()
+	if err != nil {
+		return err
+	}
+	wantClient := &api.Client{
+		Id:           clientID,
+		Secret:       secret,
+		Name:         "Self-test client",
+		RedirectUris: []string{"https://app.invalid/callback"},
+		LogoUrl:      "https://app.invalid/logo.png",
+	}
+	cctx, cancel = call()
+	clientResp, err := client.CreateClient(cctx, &api.CreateClientReq{Client: wantClient})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	if clientResp.AlreadyExists {
+		return fmt.Errorf("client %q %w", clientID, provider.ErrAlreadyExists)
+	}
+	createdClient = true
+	fmt.Fprintf(out, "  ✓ created\n")
+
+	cctx, cancel = call()
+	getResp, err := client.GetClient(cctx, &api.GetClientReq{Id: clientID})
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get client: %w", err)
+	}
+	gotClient := getResp.Client
+	if gotClient == nil ||
+		gotClient.Secret != wantClient.Secret ||
+		gotClient.Name != wantClient.Name ||
+		gotClient.LogoUrl != wantClient.LogoUrl ||
+		gotClient.Public != wantClient.Public ||
+		!reflect.DeepEqual(gotClient.RedirectUris, wantClient.RedirectUris) {
+		return fmt.Errorf("client did not round-trip: wrote %+v, read %+v", wantClient, gotClient)
+	}
+	fmt.Fprintf(out, "  ✓ read back and round-tripped\n")
+
+	fmt.Fprintf(out, "=== Cleanup ===\n")
+	return nil
+}
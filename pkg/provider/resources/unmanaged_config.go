@@ -0,0 +1,82 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+)
+
+// Config keys listed in the provider's ignoreConfigKeys are owned by some other
+// system. Their values in Dex are neither diffed against nor overwritten.
+
+// preserveIgnoredConfigKeys carries the current Dex values of the ignored keys
+// over into config before an update, so the update does not overwrite them.
+// A key absent from the stored config is removed from config as well.
+func preserveIgnoredConfigKeys(ctx context.Context, cfg provider.DexConfig, connectorID string, config []byte, timeoutSeconds *int) ([]byte, error) {
+	if len(cfg.IgnoreConfigKeys) == 0 {
+		return config, nil
+	}
+
+	con, err := findConnectorById(ctx, cfg, connectorID, timeoutSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	var stored map[string]any
+	if con != nil {
+		_ = json.Unmarshal(trimConfigBytes(con.Config), &stored)
+	}
+
+	var desired map[string]any
+	if err := json.Unmarshal(config, &desired); err != nil {
+		// Not a JSON object; there are no keys to preserve.
+		return config, nil
+	}
+	restoreIgnoredConfigKeys(cfg.IgnoreConfigKeys, desired, stored)
+
+	out, err := json.Marshal(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal connector config: %w", err)
+	}
+	return out, nil
+}
+
+// restoreIgnoredConfigKeys sets each ignored key in config to its value in
+// source, or removes it when source does not have it. Reads use it with the
+// config built from the previous inputs as source, so that external changes to
+// ignored keys do not surface as a diff.
+func restoreIgnoredConfigKeys(keys []string, config, source map[string]any) {
+	for _, key := range keys {
+		if v, ok := source[key]; ok {
+			config[key] = v
+		} else {
+			delete(config, key)
+		}
+	}
+}
+
+// restoreIgnoredConnectorConfig returns the stored config of a Connector with
+// its ignored keys set as the previous inputs built them. The stored config is
+// returned unchanged when either side is not a JSON object.
+func restoreIgnoredConnectorConfig(keys []string, stored []byte, previous ConnectorArgs) []byte {
+	var config map[string]any
+	if err := json.Unmarshal(trimConfigBytes(stored), &config); err != nil {
+		return stored
+	}
+	previousBytes, err := buildConnectorConfigBytes(previous)
+	if err != nil {
+		return stored
+	}
+	var source map[string]any
+	if err := json.Unmarshal(previousBytes, &source); err != nil {
+		return stored
+	}
+	restoreIgnoredConfigKeys(keys, config, source)
+	out, err := json.Marshal(config)
+	if err != nil {
+		return stored
+	}
+	return out
+}
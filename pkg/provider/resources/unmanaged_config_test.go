@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"testing"
+
+	api "github.com/dexidp/dex/api/v2"
+)
+
+func TestIgnoredConfigKeyExternallyMutated(t *testing.T) {
+	ignore := []string{"hostedDomains"}
+	applied := `{"issuer":"https://idp.example.com","clientID":"client","hostedDomains":["example.com"]}`
+	state := ConnectorArgs{ConnectorId: "oidc", Type: "custom", Name: "OIDC", RawConfig: &applied}
+
+	// Another system changed hostedDomains in Dex.
+	stored := `{"issuer":"https://idp.example.com","clientID":"client","hostedDomains":["example.org"]}`
+	restored := restoreIgnoredConnectorConfig(ignore, []byte(stored), state)
+	read, _, err := decodeConnector(&api.Connector{Id: "oidc", Type: "custom", Name: "OIDC", Config: restored})
+	if err != nil {
+		t.Fatalf("decodeConnector() = %v", err)
+	}
+	if diff := diffConnector(read, state); diff.HasChanges {
+		t.Errorf("external change to an ignored key caused a diff: %+v", diff.DetailedDiff)
+	}
+
+	// A change to a managed key in Dex is still drift.
+	stored = `{"issuer":"https://other.example.com","clientID":"client","hostedDomains":["example.org"]}`
+	restored = restoreIgnoredConnectorConfig(ignore, []byte(stored), state)
+	read, _, err = decodeConnector(&api.Connector{Id: "oidc", Type: "custom", Name: "OIDC", Config: restored})
+	if err != nil {
+		t.Fatalf("decodeConnector() = %v", err)
+	}
+	if diff := diffConnector(read, state); !diff.HasChanges {
+		t.Error("external change to a managed key caused no diff")
+	}
+
+	// Changing the ignored key in the program is a diff; only the old side
+	// is read with the previous value.
+	changed := `{"issuer":"https://idp.example.com","clientID":"client","hostedDomains":["example.net"]}`
+	news := state
+	news.RawConfig = &changed
+	if diff := diffConnector(state, news); !diff.HasChanges {
+		t.Error("program change to an ignored key caused no diff")
+	}
+}
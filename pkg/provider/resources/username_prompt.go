@@ -0,0 +1,64 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	p "github.com/pulumi/pulumi-go-provider"
+)
+
+// usernamePromptConnectorTypes lists the Dex connector types that render their
+// own login form and accept a usernamePrompt label for its username field.
+var usernamePromptConnectorTypes = map[string]bool{
+	"ldap":            true,
+	"atlassian-crowd": true,
+	"keystone":        true,
+}
+
+// maxUsernamePromptLength bounds usernamePrompt, which Dex shows as a form label.
+const maxUsernamePromptLength = 64
+
+// validateUsernamePrompt checks that prompt is a short, single-line label.
+func validateUsernamePrompt(property, prompt string) []p.CheckFailure {
+	var reason string
+	switch {
+	case strings.TrimSpace(prompt) == "":
+		reason = "must not be empty or blank"
+	case strings.ContainsAny(prompt, "\r\n"):
+		reason = "must be a single line"
+	case utf8.RuneCountInString(prompt) > maxUsernamePromptLength:
+		reason = fmt.Sprintf("must be at most %d characters", maxUsernamePromptLength)
+	default:
+		return nil
+	}
+	return []p.CheckFailure{{Property: property, Reason: reason}}
+}
+
+// checkRawConfigUsernamePrompt validates the usernamePrompt key of a
+// connector's rawConfig for types in usernamePromptConnectorTypes.
+func checkRawConfigUsernamePrompt(args ConnectorArgs) []p.CheckFailure {
+	if !usernamePromptConnectorTypes[args.Type] || args.RawConfig == nil || *args.RawConfig == "" {
+		return nil
+	}
+
+	var config map[string]any
+	if err := json.Unmarshal([]byte(*args.RawConfig), &config); err != nil {
+		return nil
+	}
+
+	value, ok := config["usernamePrompt"]
+	if !ok {
+		return nil
+	}
+	prompt, ok := value.(string)
+	if !ok {
+		return []p.CheckFailure{{Property: "rawConfig", Reason: "usernamePrompt must be a string"}}
+	}
+	failures := validateUsernamePrompt("rawConfig", prompt)
+	for i := range failures {
+		failures[i].Reason = "usernamePrompt " + failures[i].Reason
+	}
+	return failures
+}
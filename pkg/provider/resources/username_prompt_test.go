@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateUsernamePrompt(t *testing.T) {
+	tests := []struct {
+		name     string
+		prompt   string
+		wantFail bool
+	}{
+		{name: "label", prompt: "Email Address"},
+		{name: "non-ASCII label", prompt: "Benutzername (ü)"},
+		{name: "max length", prompt: strings.Repeat("ü", maxUsernamePromptLength)},
+		{name: "empty", prompt: "", wantFail: true},
+		{name: "blank", prompt: " \t ", wantFail: true},
+		{name: "multi-line", prompt: "Email\nAddress", wantFail: true},
+		{name: "carriage return", prompt: "Email\rAddress", wantFail: true},
+		{name: "too long", prompt: strings.Repeat("a", maxUsernamePromptLength+1), wantFail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := validateUsernamePrompt("usernamePrompt", tt.prompt)
+			if (len(failures) > 0) != tt.wantFail {
+				t.Fatalf("validateUsernamePrompt(%q) = %+v, want failure %v", tt.prompt, failures, tt.wantFail)
+			}
+			if tt.wantFail && failures[0].Property != "usernamePrompt" {
+				t.Errorf("property = %q, want usernamePrompt", failures[0].Property)
+			}
+		})
+	}
+}
+
+func TestCheckRawConfigUsernamePrompt(t *testing.T) {
+	tests := []struct {
+		name      string
+		conType   string
+		raw       string
+		wantFails int
+	}{
+		{name: "ldap label", conType: "ldap", raw: `{"usernamePrompt":"Email Address"}`},
+		{name: "ldap without prompt", conType: "ldap", raw: `{"host":"ldap.example.com"}`},
+		{name: "ldap blank", conType: "ldap", raw: `{"usernamePrompt":"  "}`, wantFails: 1},
+		{name: "crowd multi-line", conType: "atlassian-crowd", raw: `{"usernamePrompt":"a\nb"}`, wantFails: 1},
+		{name: "keystone too long", conType: "keystone", raw: `{"usernamePrompt":"` + strings.Repeat("a", maxUsernamePromptLength+1) + `"}`, wantFails: 1},
+		{name: "not a string", conType: "ldap", raw: `{"usernamePrompt":42}`, wantFails: 1},
+		{name: "unsupported type is ignored", conType: "oidc", raw: `{"usernamePrompt":""}`},
+		{name: "invalid JSON is left to other checks", conType: "ldap", raw: `{"usernamePrompt":`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := tt.raw
+			args := ConnectorArgs{ConnectorId: "c", Type: tt.conType, Name: "C", RawConfig: &raw}
+			failures := checkRawConfigUsernamePrompt(args)
+			if len(failures) != tt.wantFails {
+				t.Fatalf("checkRawConfigUsernamePrompt() = %+v, want %d failures", failures, tt.wantFails)
+			}
+			for _, f := range failures {
+				if f.Property != "rawConfig" || !strings.HasPrefix(f.Reason, "usernamePrompt ") {
+					t.Errorf("failure = %+v, want a rawConfig usernamePrompt failure", f)
+				}
+			}
+		})
+	}
+}
+
+func TestLdapUsernamePromptRoundTrip(t *testing.T) {
+	prompt := "Email Address"
+	args := LdapConnectorArgs{
+		Host:           "ldap.example.com:636",
+		UsernamePrompt: &prompt,
+		UserSearch:     LdapUserSearch{BaseDN: "ou=people,dc=example,dc=com", Username: "mail", IdAttr: "uid"},
+	}
+
+	config := buildLdapConfig(args)
+	if config["usernamePrompt"] != prompt {
+		t.Errorf("config usernamePrompt = %v, want %q", config["usernamePrompt"], prompt)
+	}
+	got := decodeLdapConfig(roundTripConfig(t, config))
+	if got.UsernamePrompt == nil || *got.UsernamePrompt != prompt {
+		t.Errorf("usernamePrompt = %v, want %q", got.UsernamePrompt, prompt)
+	}
+
+	args.UsernamePrompt = nil
+	if _, ok := buildLdapConfig(args)["usernamePrompt"]; ok {
+		t.Error("unset usernamePrompt was written to the config")
+	}
+	if got := decodeLdapConfig(roundTripConfig(t, buildLdapConfig(args))); got.UsernamePrompt != nil {
+		t.Errorf("usernamePrompt = %q, want unset", *got.UsernamePrompt)
+	}
+}
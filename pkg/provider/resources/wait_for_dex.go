@@ -0,0 +1,79 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kotaicode/pulumi-dex/pkg/provider"
+	"github.com/pulumi/pulumi-go-provider/infer"
+)
+
+// ============================================================================
+// waitForDex - Waits until the Dex API answers
+// ============================================================================
+
+// WaitForDexArgs defines inputs for the waitForDex function.
+type WaitForDexArgs struct {
+	TimeoutSeconds *int `pulumi:"timeoutSeconds,optional"`
+	IntervalMs     *int `pulumi:"intervalMs,optional"`
+}
+
+// WaitForDexResult defines outputs for the waitForDex function.
+type WaitForDexResult struct {
+	ElapsedSeconds float64 `pulumi:"elapsedSeconds"`
+	Server         string  `pulumi:"server"`
+	Api            int     `pulumi:"api"`
+}
+
+// WaitForDex polls Dex until its API answers.
+type WaitForDex struct{}
+
+// Annotate provides schema metadata.
+func (f *WaitForDex) Annotate(a infer.Annotator) {
+	a.Describe(f, "Polls Dex's GetVersion until it succeeds or the timeout elapses, and returns the version Dex reported. "+
+		"The provider must already be connected; to wait for a Dex that is not accepting connections yet, set the provider's waitForReadySeconds instead.")
+}
+
+// Annotate provides schema metadata for WaitForDexArgs.
+func (r *WaitForDexArgs) Annotate(a infer.Annotator) {
+	a.Describe(&r.TimeoutSeconds, "How long to wait in total, in seconds. Defaults to 60.")
+	a.Describe(&r.IntervalMs, "Wait between attempts, in milliseconds. Defaults to 1000.")
+}
+
+// Annotate provides schema metadata for WaitForDexResult.
+func (r *WaitForDexResult) Annotate(a infer.Annotator) {
+	a.Describe(&r.ElapsedSeconds, "Seconds spent waiting until Dex answered.")
+	a.Describe(&r.Server, "Semantic version of the Dex server.")
+	a.Describe(&r.Api, "Numeric API version.")
+}
+
+// Invoke polls GetVersion on Dex.
+func (f *WaitForDex) Invoke(ctx context.Context, req infer.FunctionRequest[WaitForDexArgs]) (infer.FunctionResponse[WaitForDexResult], error) {
+	cfg := infer.GetConfig[provider.DexConfig](ctx)
+	if cfg.Client == nil {
+		return infer.FunctionResponse[WaitForDexResult]{}, provider.ErrClientNotConfigured
+	}
+
+	timeoutSeconds := provider.PtrOr(req.Input.TimeoutSeconds, 60)
+	intervalMs := provider.PtrOr(req.Input.IntervalMs, 1000)
+	if timeoutSeconds <= 0 {
+		return infer.FunctionResponse[WaitForDexResult]{}, fmt.Errorf("timeoutSeconds must be positive")
+	}
+	if intervalMs <= 0 {
+		return infer.FunctionResponse[WaitForDexResult]{}, fmt.Errorf("intervalMs must be positive")
+	}
+
+	resp, elapsed, err := cfg.WaitForReady(ctx, time.Duration(timeoutSeconds)*time.Second, time.Duration(intervalMs)*time.Millisecond)
+	if err != nil {
+		return infer.FunctionResponse[WaitForDexResult]{}, err
+	}
+
+	return infer.FunctionResponse[WaitForDexResult]{
+		Output: WaitForDexResult{
+			ElapsedSeconds: elapsed.Seconds(),
+			Server:         resp.Server,
+			Api:            int(resp.Api),
+		},
+	}, nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultRetryBackoffMs = 250
+	maxRetryBackoff       = 5 * time.Second
+)
+
+// IsRetryable reports whether a Dex RPC error is transient and the call may be
+// retried, e.g. while Dex is restarting. Errors describing the request itself,
+// such as AlreadyExists or InvalidArgument, are never retryable.
+func IsRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// retryInterceptor returns a unary client interceptor that retries calls
+// failing with a retryable error up to maxRetries times, doubling the wait
+// after each attempt starting at backoff. Retries stop early when the call's
+// context is done, so the per-RPC timeout still bounds the whole call.
+func retryInterceptor(maxRetries int, backoff time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		wait := backoff
+		for attempt := 0; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= maxRetries || !IsRetryable(err) || ctx.Err() != nil {
+				return err
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			wait = min(wait*2, maxRetryBackoff)
+		}
+	}
+}
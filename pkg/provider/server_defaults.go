@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"reflect"
+)
+
+// ServerDefaultConfigKeys lists, per connector type, config keys that Dex may
+// fill in on its own together with the value it uses. A key is only ignored
+// when its value equals the listed default, so explicit non-default settings
+// still show up as drift. Add entries here when Dex starts normalizing more
+// of a connector's config.
+var ServerDefaultConfigKeys = map[string]map[string]any{
+	"oidc": {
+		"getUserInfo":               false,
+		"insecureSkipEmailVerified": false,
+		"insecureEnableGroups":      false,
+		"basicAuthUnsupported":      nil,
+	},
+	"github": {
+		"loadAllGroups": false,
+		"useLoginAsID":  false,
+	},
+	"gitlab": {
+		"useLoginAsID": false,
+	},
+	"microsoft": {
+		"onlySecurityGroups": false,
+	},
+}
+
+// RegisterServerDefault adds a server-added default for a connector type.
+func RegisterServerDefault(connectorType, key string, value any) {
+	if ServerDefaultConfigKeys[connectorType] == nil {
+		ServerDefaultConfigKeys[connectorType] = map[string]any{}
+	}
+	ServerDefaultConfigKeys[connectorType][key] = value
+}
+
+// StripServerDefaults removes keys from config whose values equal the
+// server-added default for connectorType. Values are compared after JSON
+// decoding, so numbers must be given as float64. It reports whether any key
+// was removed.
+func StripServerDefaults(connectorType string, config map[string]any) bool {
+	stripped := false
+	for key, def := range ServerDefaultConfigKeys[connectorType] {
+		v, ok := config[key]
+		if !ok || !reflect.DeepEqual(v, def) {
+			continue
+		}
+		delete(config, key)
+		stripped = true
+	}
+	return stripped
+}
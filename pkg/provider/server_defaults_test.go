@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripServerDefaults(t *testing.T) {
+	tests := []struct {
+		connectorType string
+		config        map[string]any
+		want          map[string]any
+	}{
+		{
+			connectorType: "oidc",
+			config: map[string]any{
+				"issuer":                    "https://idp.example.com",
+				"getUserInfo":               false,
+				"insecureSkipEmailVerified": false,
+				"insecureEnableGroups":      true,
+				"basicAuthUnsupported":      nil,
+			},
+			want: map[string]any{
+				"issuer":               "https://idp.example.com",
+				"insecureEnableGroups": true,
+			},
+		},
+		{
+			connectorType: "github",
+			config:        map[string]any{"clientID": "id", "loadAllGroups": false, "useLoginAsID": true},
+			want:          map[string]any{"clientID": "id", "useLoginAsID": true},
+		},
+		{
+			connectorType: "gitlab",
+			config:        map[string]any{"clientID": "id", "useLoginAsID": false},
+			want:          map[string]any{"clientID": "id"},
+		},
+		{
+			connectorType: "microsoft",
+			config:        map[string]any{"tenant": "common", "onlySecurityGroups": false},
+			want:          map[string]any{"tenant": "common"},
+		},
+		{
+			// Types without server defaults are left alone.
+			connectorType: "ldap",
+			config:        map[string]any{"host": "ldap.example.com", "useLoginAsID": false},
+			want:          map[string]any{"host": "ldap.example.com", "useLoginAsID": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.connectorType, func(t *testing.T) {
+			wantStripped := len(tt.want) != len(tt.config)
+			stripped := StripServerDefaults(tt.connectorType, tt.config)
+			if !reflect.DeepEqual(tt.config, tt.want) {
+				t.Errorf("config = %v, want %v", tt.config, tt.want)
+			}
+			if stripped != wantStripped {
+				t.Errorf("StripServerDefaults() = %v, want %v", stripped, wantStripped)
+			}
+		})
+	}
+}
+
+func TestRegisterServerDefault(t *testing.T) {
+	t.Cleanup(func() { delete(ServerDefaultConfigKeys, "test-type") })
+
+	RegisterServerDefault("test-type", "timeout", float64(30))
+	config := map[string]any{"timeout": float64(30), "host": "example.com"}
+	if !StripServerDefaults("test-type", config) {
+		t.Fatal("StripServerDefaults() = false, want true")
+	}
+	if _, ok := config["timeout"]; ok {
+		t.Errorf("registered default was not stripped: %v", config)
+	}
+
+	config = map[string]any{"timeout": float64(60)}
+	if StripServerDefaults("test-type", config) {
+		t.Errorf("non-default value was stripped")
+	}
+}